@@ -17,6 +17,7 @@ import (
 
 	"questspace/docs"
 	"questspace/internal/app"
+	"questspace/internal/blobs"
 	"questspace/internal/handlers/auth"
 	"questspace/internal/handlers/auth/google"
 	"questspace/internal/handlers/play"
@@ -30,12 +31,17 @@ import (
 	"questspace/internal/pgdb"
 	"questspace/internal/questspace/authservice"
 	"questspace/internal/questspace/authservice/googleservice"
+	"questspace/internal/questspace/events"
+	"questspace/internal/questspace/lifecycle"
 	"questspace/pkg/auth/jwt"
 	"questspace/pkg/cors"
 	"questspace/pkg/dbnode"
 	"questspace/pkg/transport"
 )
 
+// schedulerLockKey identifies advisory lock, which is held by application instance running scheduled jobs
+const schedulerLockKey = 0x5155455354
+
 // InitApp godoc
 // @securityDefinitions.apikey 	ApiKeyAuth
 // @in 							header
@@ -114,15 +120,19 @@ func InitApp(ctx context.Context, application *app.App) error {
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/user/:id/password", transport.WrapCtxErr(updateUserHandler.HandlePassword))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).DELETE("/user/:id", transport.WrapCtxErr(updateUserHandler.HandleDelete))
 
-	questHandler := quest.NewHandler(clientFactory, httpClient, cfg.Teams.InviteLinkPrefix)
+	eventRelay := pgdb.NewEventRelay(nodePicker)
+	gameEvents := events.NewRelayBroker(eventRelay)
+	application.Background(func(ctx context.Context) { eventRelay.Listen(ctx, gameEvents) })
+	questHandler := quest.NewHandler(clientFactory, httpClient, cfg.Teams.InviteLinkPrefix, gameEvents)
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest", transport.WrapCtxErr(questHandler.HandleCreate))
 	r.H().Use(jwt.AuthMiddleware(jwtParser)).GET("/quest", transport.WrapCtxErr(questHandler.HandleGetMany))
 	r.H().Use(jwt.AuthMiddleware(jwtParser)).GET("/quest/:id", transport.WrapCtxErr(questHandler.HandleGet))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id", transport.WrapCtxErr(questHandler.HandleUpdate))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).DELETE("/quest/:id", transport.WrapCtxErr(questHandler.HandleDelete))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/finish", transport.WrapCtxErr(questHandler.HandleFinish))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/clone", transport.WrapCtxErr(questHandler.HandleClone))
 
-	teamsHandler := teams.NewHandler(clientFactory, cfg.Teams.InviteLinkPrefix)
+	teamsHandler := teams.NewHandler(clientFactory, cfg.Teams.InviteLinkPrefix, gameEvents)
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/teams", transport.WrapCtxErr(teamsHandler.HandleCreate))
 	r.H().GET("/quest/:id/teams", transport.WrapCtxErr(teamsHandler.HandleGetMany))
 	r.H().GET("/teams/all/:id", transport.WrapCtxErr(teamsHandler.HandleGet))
@@ -139,15 +149,53 @@ func InitApp(ctx context.Context, application *app.App) error {
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).PATCH("/quest/:id/task-groups/bulk", transport.WrapCtxErr(taskGroupHandler.HandleBulkUpdate))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/task-groups", transport.WrapCtxErr(taskGroupHandler.HandleCreate))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/task-groups", transport.WrapCtxErr(taskGroupHandler.HandleGet))
-
-	playHandler := play.NewHandler(clientFactory)
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/export", transport.WrapCtxErr(taskGroupHandler.HandleExport))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/import", transport.WrapCtxErr(taskGroupHandler.HandleImport))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/import/quest", transport.WrapCtxErr(taskGroupHandler.HandleImportNew))
+
+	var answerFiles blobs.Store
+	if len(cfg.AnswerFiles.LocalDir) > 0 {
+		if answerFiles, err = blobs.NewStore(&cfg.AnswerFiles); err != nil {
+			return xerrors.Errorf("create answer files store: %w", err)
+		}
+	}
+	playHandler := play.NewHandler(clientFactory, gameEvents, answerFiles, &imageTypeValidator)
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/play", transport.WrapCtxErr(playHandler.HandleGet))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/play/events", transport.WrapCtxErr(playHandler.HandleEvents))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/hint", transport.WrapCtxErr(playHandler.HandleTakeHint))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/answer", transport.WrapCtxErr(playHandler.HandleTryAnswer))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/answer/file", transport.WrapCtxErr(playHandler.HandleTryAnswerFile))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/answer-file/:key", transport.WrapCtxErr(playHandler.HandleGetAnswerFile))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/skip", transport.WrapCtxErr(playHandler.HandleSkipTaskGroup))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/bonus-code", transport.WrapCtxErr(playHandler.HandleUseBonusCode))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/bonus-codes", transport.WrapCtxErr(playHandler.HandleGetBonusCodes))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/bonus-codes", transport.WrapCtxErr(playHandler.HandleCreateBonusCode))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).DELETE("/quest/:id/bonus-codes/:code_id", transport.WrapCtxErr(playHandler.HandleDeleteBonusCode))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/table", transport.WrapCtxErr(playHandler.HandleGetTableResults))
-	r.H().GET("/quest/:id/leaderboard", transport.WrapCtxErr(playHandler.HandleLeaderboard))
+	r.H().Use(jwt.AuthMiddleware(jwtParser)).GET("/quest/:id/leaderboard", transport.WrapCtxErr(playHandler.HandleLeaderboard))
+	r.H().Use(jwt.AuthMiddleware(jwtParser)).GET("/quest/:id/leaderboard/stream", transport.WrapCtxErr(playHandler.HandleLeaderboardStream))
+	r.H().Use(jwt.AuthMiddleware(jwtParser)).GET("/quest/:id/score-history", transport.WrapCtxErr(playHandler.HandleScoreHistory))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/leaderboard/reveal", transport.WrapCtxErr(questHandler.HandleRevealLeaderboard))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/penalty", transport.WrapCtxErr(playHandler.HandleAddPenalty))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/penalty", transport.WrapCtxErr(playHandler.HandleGetPenalties))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/penalty/add", transport.WrapCtxErr(playHandler.HandleAddAdjustment))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/penalty/revoke", transport.WrapCtxErr(playHandler.HandleRevokePenalty))
 	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/answer_log", transport.WrapCtxErr(playHandler.HandleAnswerLog))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/answer_log/export", transport.WrapCtxErr(playHandler.HandleAnswerLogExport))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/review", transport.WrapCtxErr(playHandler.HandleGetReviewQueue))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest/:id/review", transport.WrapCtxErr(playHandler.HandleReviewAnswer))
+	r.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).GET("/quest/:id/map.geojson", transport.WrapCtxErr(playHandler.HandleQuestMap))
+
+	if !cfg.Scheduler.Disabled {
+		lifecycleService := lifecycle.NewService(clientFactory, gameEvents)
+		scheduler := app.NewScheduler(
+			pgdb.NewAdvisoryLock(nodePicker, schedulerLockKey),
+			&cfg.Scheduler,
+			app.Job{Name: "finish-expired-quests", Run: lifecycleService.FinishExpiredQuests},
+			app.Job{Name: "close-expired-groups", Run: lifecycleService.CloseExpiredGroups},
+		)
+		application.Background(scheduler.Run)
+	}
 	return nil
 }
 
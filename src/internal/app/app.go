@@ -16,6 +16,7 @@ import (
 	"go.uber.org/zap"
 
 	"questspace/pkg/environment"
+	"questspace/pkg/logging"
 	"questspace/pkg/middleware"
 	"questspace/pkg/transport"
 )
@@ -26,9 +27,10 @@ var (
 )
 
 type App struct {
-	router   *transport.Router
-	logger   *zap.Logger
-	cleanups []func() error
+	router     *transport.Router
+	logger     *zap.Logger
+	cleanups   []func() error
+	background []func(context.Context)
 }
 
 func (a *App) Router() *transport.Router {
@@ -90,6 +92,12 @@ func (a *App) Cleanup(c func() error) {
 	a.cleanups = append(a.cleanups, c)
 }
 
+// Background registers function, which is run in separate goroutine while application is running.
+// Function should return when context is canceled
+func (a *App) Background(fn func(context.Context)) {
+	a.background = append(a.background, fn)
+}
+
 func (a *App) Close() error {
 	errs := make([]error, 0, len(a.cleanups))
 	for _, c := range a.cleanups {
@@ -112,6 +120,11 @@ func (a *App) Run(ctx context.Context) error {
 		},
 	}
 
+	bgCtx := logging.WithLogger(ctx, a.logger)
+	for _, fn := range a.background {
+		go fn(bgCtx)
+	}
+
 	shutdown, listen := make(chan error), make(chan error)
 	go func() {
 		<-ctx.Done()
@@ -6,6 +6,7 @@ import (
 	"github.com/yandex/perforator/library/go/core/xerrors"
 	"gopkg.in/yaml.v3"
 
+	"questspace/internal/blobs"
 	"questspace/internal/handlers/teams"
 	"questspace/internal/images"
 	"questspace/internal/pgdb/pgconfig"
@@ -15,13 +16,15 @@ import (
 )
 
 type Config struct {
-	DB        pgconfig.Config      `yaml:"db"`
-	HashCost  int                  `yaml:"hash-cost"`
-	CORS      cors.Config          `yaml:"cors"`
-	JWT       jwt.Config           `yaml:"jwt"`
-	Teams     teams.Config         `yaml:"teams"`
-	Google    googleservice.Config `yaml:"google-oauth"`
-	Validator images.Config        `yaml:"validator"`
+	DB          pgconfig.Config      `yaml:"db"`
+	HashCost    int                  `yaml:"hash-cost"`
+	CORS        cors.Config          `yaml:"cors"`
+	JWT         jwt.Config           `yaml:"jwt"`
+	Teams       teams.Config         `yaml:"teams"`
+	Google      googleservice.Config `yaml:"google-oauth"`
+	Validator   images.Config        `yaml:"validator"`
+	AnswerFiles blobs.Config         `yaml:"answer-files"`
+	Scheduler   SchedulerConfig      `yaml:"scheduler"`
 }
 
 func UnmarshallConfigFromFile(path string) (*Config, error) {
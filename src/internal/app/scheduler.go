@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"questspace/pkg/logging"
+)
+
+const defaultSchedulerInterval = 30 * time.Second
+
+type SchedulerConfig struct {
+	Disabled bool          `yaml:"disabled"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// Leader tells whether application instance is elected to run background jobs
+type Leader interface {
+	TryLead(ctx context.Context) (bool, error)
+	Resign(ctx context.Context) error
+}
+
+// Job is run by scheduler periodically. Jobs should be idempotent, since they may be interrupted
+// at any moment and run again by another instance
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Scheduler runs jobs one by one with fixed interval. Only the instance, which is elected as leader, runs jobs,
+// while others keep trying to become leader on every tick
+type Scheduler struct {
+	leader   Leader
+	interval time.Duration
+	jobs     []Job
+}
+
+func NewScheduler(leader Leader, cfg *SchedulerConfig, jobs ...Job) *Scheduler {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultSchedulerInterval
+	}
+	return &Scheduler{
+		leader:   leader,
+		interval: interval,
+		jobs:     jobs,
+	}
+}
+
+// Run runs jobs until context is canceled, then resigns leadership
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	defer func() {
+		resignCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := s.leader.Resign(resignCtx); err != nil {
+			logging.Error(ctx, "could not resign leadership", zap.Error(err))
+		}
+	}()
+
+	for {
+		s.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick runs every job once if instance is a leader
+func (s *Scheduler) Tick(ctx context.Context) {
+	isLeader, err := s.leader.TryLead(ctx)
+	if err != nil {
+		logging.Error(ctx, "could not elect leader", zap.Error(err))
+		return
+	}
+	if !isLeader {
+		return
+	}
+	for _, job := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err = job.Run(ctx); err != nil {
+			logging.Error(ctx, "scheduled job failed", zap.String("job", job.Name), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLeader struct {
+	leader  bool
+	resigns int
+}
+
+func (l *fakeLeader) TryLead(context.Context) (bool, error) {
+	return l.leader, nil
+}
+
+func (l *fakeLeader) Resign(context.Context) error {
+	l.resigns++
+	return nil
+}
+
+func TestScheduler_Tick(t *testing.T) {
+	var runs []string
+	job := func(name string, err error) Job {
+		return Job{Name: name, Run: func(context.Context) error {
+			runs = append(runs, name)
+			return err
+		}}
+	}
+	leader := &fakeLeader{}
+	s := NewScheduler(leader, &SchedulerConfig{}, job("failing", errors.New("oops")), job("next", nil))
+
+	s.Tick(context.Background())
+	assert.Empty(t, runs)
+
+	leader.leader = true
+	s.Tick(context.Background())
+	assert.Equal(t, []string{"failing", "next"}, runs)
+}
+
+func TestScheduler_RunResigns(t *testing.T) {
+	leader := &fakeLeader{leader: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs int
+	s := NewScheduler(leader, &SchedulerConfig{}, Job{Name: "job", Run: func(context.Context) error {
+		runs++
+		cancel()
+		return nil
+	}})
+
+	s.Run(ctx)
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, 1, leader.resigns)
+}
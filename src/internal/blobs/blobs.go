@@ -0,0 +1,30 @@
+package blobs
+
+import (
+	"context"
+	"io"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+)
+
+var ErrNotFound = xerrors.NewSentinel("blob not found")
+
+// Store keeps uploaded files by keys. Keys consist of slash-separated segments of letters, digits, dashes and underscores
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config of blob store. Store is not created when config is empty
+type Config struct {
+	// LocalDir is a directory where local store keeps files
+	LocalDir string `yaml:"local-dir"`
+}
+
+func NewStore(config *Config) (Store, error) {
+	if len(config.LocalDir) == 0 {
+		return nil, xerrors.New("blob store is not configured: local-dir is empty")
+	}
+	return NewLocal(config.LocalDir)
+}
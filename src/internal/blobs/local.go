@@ -0,0 +1,89 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+)
+
+var _ Store = &Local{}
+
+var keyRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+(/[a-zA-Z0-9_-]+)*$`)
+
+// Local keeps files in directory of local filesystem
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, xerrors.Errorf("create blob directory: %w", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	if !keyRegexp.MatchString(key) {
+		return "", xerrors.Errorf("bad blob key %q", key)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes file to temporary location first, so that readers never see partially written blob
+func (l *Local) Put(_ context.Context, key string, r io.Reader) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return xerrors.Errorf("create blob directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return xerrors.Errorf("create temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return xerrors.Errorf("write blob: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return xerrors.Errorf("close blob: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return xerrors.Errorf("rename blob: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, xerrors.Errorf("open blob: %w", err)
+	}
+	return f, nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return xerrors.Errorf("remove blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package blobs
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "quest/photo", strings.NewReader("content")))
+	r, err := store.Get(ctx, "quest/photo")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "content", string(data))
+
+	require.NoError(t, store.Delete(ctx, "quest/photo"))
+	_, err = store.Get(ctx, "quest/photo")
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, store.Delete(ctx, "quest/photo"))
+
+	for _, key := range []string{"", "../secret", "quest/../../secret", "/etc/passwd", "quest/"} {
+		assert.Error(t, store.Put(ctx, key, strings.NewReader("content")), key)
+	}
+}
@@ -0,0 +1,223 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/events"
+	"questspace/internal/questspace/game"
+	"questspace/pkg/auth/jwt"
+	"questspace/pkg/dbnode"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+	"questspace/pkg/transport"
+)
+
+// HandleUseBonusCode handles POST quest/:id/bonus-code request
+//
+// @Summary		Enter bonus code in play-mode
+// @Description	Every team can enter each bonus code once. Value of the code is added to team score.
+// @Tags		PlayMode
+// @Param		quest_id	path		string						true	"Quest ID"
+// @Param		request		body		game.UseBonusCodeRequest	true	"Bonus code"
+// @Success		200			{object}	game.UseBonusCodeResponse
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Failure 	406
+// @Router		/quest/{id}/bonus-code [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleUseBonusCode(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req, err := transport.UnmarshalRequestData[game.UseBonusCodeRequest](r)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req.QuestID = questID
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	batch := events.NewBatch(h.events)
+	srv := game.NewService(s, s, s, s, game.WithEvents(batch))
+	resp, err := srv.UseBonusCode(ctx, uauth, &req)
+	if err != nil {
+		return xerrors.Errorf("use bonus code: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return xerrors.Errorf("commit tx: %w", err)
+	}
+	batch.Publish()
+
+	if err = transport.ServeJSONResponse(w, http.StatusOK, resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// HandleCreateBonusCode handles POST quest/:id/bonus-codes request
+//
+// @Summary		Create bonus code for quest
+// @Tags		PlayMode
+// @Param		quest_id	path		string							true	"Quest ID"
+// @Param		request		body		storage.CreateBonusCodeRequest	true	"Bonus code"
+// @Success		200			{object}	storage.BonusCode
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Failure 	409
+// @Router		/quest/{id}/bonus-codes [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleCreateBonusCode(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req, err := transport.UnmarshalRequestData[storage.CreateBonusCodeRequest](r)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req.QuestID = questID
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Master)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can manage bonus codes")
+	}
+
+	srv := game.NewService(s, s, s, s)
+	code, err := srv.CreateBonusCode(ctx, &req)
+	if err != nil {
+		return xerrors.Errorf("create bonus code: %w", err)
+	}
+	if err = transport.ServeJSONResponse(w, http.StatusOK, code); err != nil {
+		return err
+	}
+	return nil
+}
+
+// HandleGetBonusCodes handles GET quest/:id/bonus-codes request
+//
+// @Summary		Get bonus codes of quest with number of their uses
+// @Tags		PlayMode
+// @Param		quest_id	path		string	true	"Quest ID"
+// @Success		200			{object}	game.BonusCodesResponse
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Router		/quest/{id}/bonus-codes [get]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleGetBonusCodes(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can manage bonus codes")
+	}
+
+	srv := game.NewService(s, s, s, s)
+	resp, err := srv.GetBonusCodes(ctx, questID)
+	if err != nil {
+		return xerrors.Errorf("get bonus codes: %w", err)
+	}
+	if err = transport.ServeJSONResponse(w, http.StatusOK, resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// HandleDeleteBonusCode handles DELETE quest/:id/bonus-codes/:code_id request
+//
+// @Summary		Delete bonus code, so that points earned with it are removed from results
+// @Tags		PlayMode
+// @Param		quest_id	path		string	true	"Quest ID"
+// @Param		code_id		path		string	true	"Bonus code ID"
+// @Success		200
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Router		/quest/{id}/bonus-codes/{code_id} [delete]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleDeleteBonusCode(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	codeID, err := transport.UUIDParam(r, "code_id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Master)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can manage bonus codes")
+	}
+
+	srv := game.NewService(s, s, s, s)
+	if err = srv.DeleteBonusCode(ctx, &storage.DeleteBonusCodeRequest{QuestID: questID, ID: codeID}); err != nil {
+		return xerrors.Errorf("delete bonus code: %w", err)
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
@@ -0,0 +1,186 @@
+package play
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+	"go.uber.org/zap"
+
+	"questspace/internal/blobs"
+	"questspace/internal/questspace/game"
+	"questspace/pkg/auth/jwt"
+	"questspace/pkg/dbnode"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/logging"
+	"questspace/pkg/storage"
+	"questspace/pkg/transport"
+)
+
+// maxUploadSize limits size of multipart request, while actual file size is checked by FileValidator
+const maxUploadSize = 64 << 20
+
+type FileValidator interface {
+	ValidateFile(data []byte) (string, error)
+}
+
+// answerFileKey returns key of blob, so that files of different quests never mix up
+func answerFileKey(questID storage.ID, key string) string {
+	return questID.String() + "/" + key
+}
+
+// HandleTryAnswerFile handles POST quest/:id/answer/file request
+//
+// @Summary		Answer task with uploaded file in play-mode
+// @Description	File answers are verified by quest creator, so response is always pending for review.
+// @Tags		PlayMode
+// @Accept		multipart/form-data
+// @Param		quest_id	path		string	true	"Quest ID"
+// @Param		task_id		formData	string	true	"Task ID"
+// @Param		text		formData	string	false	"Comment to the file"
+// @Param		file		formData	file	true	"Answer file"
+// @Success		200			{object}	game.TryAnswerResponse
+// @Failure		400
+// @Failure		401
+// @Failure 	404
+// @Failure 	406
+// @Failure 	413
+// @Failure 	415
+// @Router		/quest/{id}/answer/file [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleTryAnswerFile(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	if h.files == nil {
+		return httperrors.New(http.StatusNotImplemented, "file answers are not configured")
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return httperrors.Errorf(http.StatusBadRequest, "read file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return httperrors.Errorf(http.StatusBadRequest, "read file: %w", err)
+	}
+	contentType, err := h.fileValidator.ValidateFile(data)
+	if err != nil {
+		return xerrors.Errorf("validate file: %w", err)
+	}
+
+	answerFile := &storage.AnswerFile{Key: storage.NewID().String(), ContentType: contentType}
+	blobKey := answerFileKey(questID, answerFile.Key)
+	srvReq := game.TryAnswerRequest{
+		QuestID: questID,
+		TaskID:  storage.ID(r.FormValue("task_id")),
+		Text:    r.FormValue("text"),
+		File:    answerFile,
+	}
+	// file is stored only after quest, team and task are checked, and is deleted if try is not committed
+	var stored bool
+	try, err := h.tryAnswer(ctx, uauth, &srvReq, func(try *game.TryAnswerResponse) error {
+		if try.Accepted {
+			// task is already solved, so the file is not referenced by any try
+			return nil
+		}
+		if err := h.files.Put(ctx, blobKey, bytes.NewReader(data)); err != nil {
+			return xerrors.Errorf("put file: %w", err)
+		}
+		stored = true
+		return nil
+	})
+	if err != nil {
+		if stored {
+			if deleteErr := h.files.Delete(ctx, blobKey); deleteErr != nil {
+				logging.Error(ctx, "could not delete answer file", zap.String("key", blobKey), zap.Error(deleteErr))
+			}
+		}
+		return err
+	}
+	if err = transport.ServeJSONResponse(w, http.StatusOK, try); err != nil {
+		return err
+	}
+	return nil
+}
+
+// HandleGetAnswerFile handles GET quest/:id/answer-file/:key request
+//
+// @Summary		Download file uploaded by team as an answer
+// @Tags		PlayMode
+// @Produce		octet-stream
+// @Param		quest_id	path		string	true	"Quest ID"
+// @Param		key			path		string	true	"File key from answer log"
+// @Success		200
+// @Failure		400
+// @Failure		401
+// @Failure 	403
+// @Failure 	404
+// @Router		/quest/{id}/answer-file/{key} [get]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleGetAnswerFile(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	key, err := transport.UUIDParam(r, "key")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	if h.files == nil {
+		return httperrors.New(http.StatusNotImplemented, "file answers are not configured")
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can view answer files")
+	}
+
+	file, err := h.files.Get(ctx, answerFileKey(questID, key.String()))
+	if err != nil {
+		if errors.Is(err, blobs.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "file %q not found", key)
+		}
+		return xerrors.Errorf("get file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	// content type is sniffed the same way as on upload
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return xerrors.Errorf("read file: %w", err)
+	}
+	head = head[:n]
+	if err = transport.StartFileStream(w, http.DetectContentType(head), key.String()); err != nil {
+		return xerrors.Errorf("start file stream: %w", err)
+	}
+	if _, err = io.Copy(w, io.MultiReader(bytes.NewReader(head), file)); err != nil {
+		logging.Error(ctx, "could not write answer file", zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/game"
+	"questspace/pkg/auth/jwt"
+	"questspace/pkg/dbnode"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+	"questspace/pkg/transport"
+)
+
+// HandleQuestMap handles GET quest/:id/map.geojson request
+//
+// @Summary		Export locations of task groups and tasks as GeoJSON
+// @Description	Features have properties kind (task_group or task), id, name, radius in meters and task_group_id for tasks.
+// @Description	When team is set, tasks also have solved property.
+// @Tags		PlayMode
+// @Produce		json
+// @Param		quest_id	path		string	true	"Quest ID"
+// @Param		team		query		string	false	"Team ID to mark solved tasks"
+// @Success		200			{object}	geo.FeatureCollection
+// @Failure		400
+// @Failure		401
+// @Failure 	403
+// @Failure 	404
+// @Router		/quest/{id}/map.geojson [get]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleQuestMap(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can view quest map")
+	}
+
+	srv := game.NewService(s, s, s, s)
+	questMap, err := srv.GetQuestMap(ctx, questID, storage.ID(transport.Query(r, "team")))
+	if err != nil {
+		return xerrors.Errorf("get quest map: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	if err = transport.ServeJSONResponse(w, http.StatusOK, questMap); err != nil {
+		return err
+	}
+	return nil
+}
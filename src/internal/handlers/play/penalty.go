@@ -0,0 +1,181 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/events"
+	"questspace/internal/questspace/game"
+	"questspace/pkg/auth/jwt"
+	"questspace/pkg/dbnode"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+	"questspace/pkg/transport"
+)
+
+// HandleGetPenalties handles GET quest/:id/penalty request
+//
+// @Summary		Get penalty ledger of quest including revoked penalties
+// @Tags		PlayMode
+// @Param		quest_id	path		string	true	"Quest ID"
+// @Param		team		query		string	false	"Team ID"
+// @Success		200			{object}	game.AdjustmentsResponse
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Router		/quest/{id}/penalty [get]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleGetPenalties(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can view penalties")
+	}
+
+	srv := game.NewService(s, s, s, s)
+	resp, err := srv.GetAdjustments(ctx, questID, storage.ID(transport.Query(r, "team")))
+	if err != nil {
+		return xerrors.Errorf("get adjustments: %w", err)
+	}
+	if err = transport.ServeJSONResponse(w, http.StatusOK, resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// HandleAddAdjustment handles POST quest/:id/penalty/add request
+//
+// @Summary		Add penalty or bonus with a reason to team ledger
+// @Description	Unlike POST /quest/{id}/penalty, entry is added to the ledger without revoking previous ones. Negative value gives bonus points to team.
+// @Tags		PlayMode
+// @Param		quest_id	path		string						true	"Quest ID"
+// @Param		request		body		game.AddAdjustmentRequest	true	"Ledger entry"
+// @Success		200
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Router		/quest/{id}/penalty/add [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleAddAdjustment(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req, err := transport.UnmarshalRequestData[game.AddAdjustmentRequest](r)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req.QuestID = questID
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can add penalty to teams")
+	}
+
+	batch := events.NewBatch(h.events)
+	srv := game.NewService(s, s, s, s, game.WithEvents(batch))
+	if err = srv.AddAdjustment(ctx, uauth, &req); err != nil {
+		return xerrors.Errorf("add adjustment: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return xerrors.Errorf("commit tx: %w", err)
+	}
+	batch.Publish()
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// HandleRevokePenalty handles POST quest/:id/penalty/revoke request
+//
+// @Summary		Revoke penalty, so that it is not counted in team score anymore
+// @Tags		PlayMode
+// @Param		quest_id	path		string						true	"Quest ID"
+// @Param		request		body		game.RevokePenaltyRequest	true	"Penalty to revoke"
+// @Success		200
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Router		/quest/{id}/penalty/revoke [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleRevokePenalty(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req, err := transport.UnmarshalRequestData[game.RevokePenaltyRequest](r)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req.QuestID = questID
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can revoke penalties")
+	}
+
+	batch := events.NewBatch(h.events)
+	srv := game.NewService(s, s, s, s, game.WithEvents(batch))
+	if err = srv.RevokePenalty(ctx, uauth, &req); err != nil {
+		return xerrors.Errorf("revoke penalty: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return xerrors.Errorf("commit tx: %w", err)
+	}
+	batch.Publish()
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
@@ -1,30 +1,46 @@
 package play
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/yandex/perforator/library/go/core/xerrors"
+	"go.uber.org/zap"
 
+	"questspace/internal/blobs"
 	"questspace/internal/pgdb"
+	"questspace/internal/questspace/events"
 	"questspace/internal/questspace/game"
 	"questspace/internal/questspace/quests"
 	"questspace/pkg/auth/jwt"
 	"questspace/pkg/dbnode"
+	"questspace/pkg/geo"
 	"questspace/pkg/httperrors"
+	"questspace/pkg/logging"
+	"questspace/pkg/spreadsheet"
 	"questspace/pkg/storage"
 	"questspace/pkg/transport"
 )
 
 type Handler struct {
 	clientFactory pgdb.QuestspaceClientFactory
+	events        *events.Broker
+	files         blobs.Store
+	fileValidator FileValidator
 }
 
-func NewHandler(clientFactory pgdb.QuestspaceClientFactory) *Handler {
+// NewHandler creates play-mode handler. File answers are disabled when files store is nil
+func NewHandler(clientFactory pgdb.QuestspaceClientFactory, broker *events.Broker, files blobs.Store, fileValidator FileValidator) *Handler {
 	return &Handler{
 		clientFactory: clientFactory,
+		events:        broker,
+		files:         files,
+		fileValidator: fileValidator,
 	}
 }
 
@@ -122,7 +138,8 @@ func (h *Handler) HandleGet(ctx context.Context, w http.ResponseWriter, r *http.
 		return xerrors.Errorf("get team: %w", err)
 	}
 
-	service := game.NewService(s, s, s, s)
+	batch := events.NewBatch(h.events)
+	service := game.NewService(s, s, s, s, game.WithEvents(batch))
 	req := game.AnswerDataRequest{Quest: quest, Team: userTeam, TaskGroups: taskGroups}
 	resp, err := service.FillAnswerData(ctx, &req)
 	if err != nil {
@@ -131,6 +148,7 @@ func (h *Handler) HandleGet(ctx context.Context, w http.ResponseWriter, r *http.
 	if err = tx.Commit(); err != nil {
 		return xerrors.Errorf("commit tx: %w", err)
 	}
+	batch.Publish()
 
 	if err = transport.ServeJSONResponse(w, http.StatusOK, resp); err != nil {
 		return err
@@ -188,7 +206,8 @@ func (h *Handler) HandleTakeHint(ctx context.Context, w http.ResponseWriter, r *
 		return httperrors.New(http.StatusNotAcceptable, "cannot take hints before quest start")
 	}
 
-	srv := game.NewService(s, s, s, s)
+	batch := events.NewBatch(h.events)
+	srv := game.NewService(s, s, s, s, game.WithEvents(batch))
 	srvReq := game.TakeHintRequest{QuestID: questID, TaskID: req.TaskID, Index: req.Index}
 	hint, err := srv.TakeHint(ctx, uauth, &srvReq)
 	if err != nil {
@@ -197,6 +216,7 @@ func (h *Handler) HandleTakeHint(ctx context.Context, w http.ResponseWriter, r *
 	if err = tx.Commit(); err != nil {
 		return xerrors.Errorf("commit tx: %w", err)
 	}
+	batch.Publish()
 
 	if err = transport.ServeJSONResponse(w, http.StatusOK, hint); err != nil {
 		return err
@@ -204,9 +224,69 @@ func (h *Handler) HandleTakeHint(ctx context.Context, w http.ResponseWriter, r *
 	return nil
 }
 
+type SkipTaskGroupRequest struct {
+	TaskGroupID storage.ID `json:"task_group_id"`
+}
+
+// HandleSkipTaskGroup handles POST quest/:id/skip request
+//
+// @Summary		Skip current task group of linear quest in play-mode
+// @Description	Closes task group which allows skipping, adds skip penalty to team and opens the next group
+// @Tags		PlayMode
+// @Param		quest_id	path		string						true	"Quest ID"
+// @Param		request		body		play.SkipTaskGroupRequest	true	"Skip task group request"
+// @Success		200			{object}	game.SkipTaskGroupResponse
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Failure 	406
+// @Router		/quest/{id}/skip [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleSkipTaskGroup(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req, err := transport.UnmarshalRequestData[SkipTaskGroupRequest](r)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	batch := events.NewBatch(h.events)
+	srv := game.NewService(s, s, s, s, game.WithEvents(batch))
+	srvReq := game.SkipTaskGroupRequest{QuestID: questID, TaskGroupID: req.TaskGroupID}
+	resp, err := srv.SkipTaskGroup(ctx, uauth, &srvReq)
+	if err != nil {
+		return xerrors.Errorf("skip task group: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return xerrors.Errorf("commit tx: %w", err)
+	}
+	batch.Publish()
+
+	if err = transport.ServeJSONResponse(w, http.StatusOK, resp); err != nil {
+		return err
+	}
+	return nil
+}
+
 type TryAnswerRequest struct {
-	TaskID storage.ID
-	Text   string
+	TaskID   storage.ID
+	Text     string
+	Choices  []int
+	Position *geo.Point
+	Part     *int
 }
 
 // HandleTryAnswer handles POST quest/:id/answer request
@@ -236,29 +316,49 @@ func (h *Handler) HandleTryAnswer(ctx context.Context, w http.ResponseWriter, r
 		return xerrors.Errorf("%w", err)
 	}
 
+	srvReq := game.TryAnswerRequest{TaskID: req.TaskID, Text: req.Text, Choices: req.Choices, Position: req.Position, Part: req.Part, QuestID: questID}
+	try, err := h.tryAnswer(ctx, uauth, &srvReq, nil)
+	if err != nil {
+		return err
+	}
+	if err = transport.ServeJSONResponse(w, http.StatusOK, try); err != nil {
+		return err
+	}
+	return nil
+}
+
+// tryAnswer checks answer and commits the try. Function beforeCommit, if set, is called with checked try
+// right before commit, so that its error rolls the try back.
+func (h *Handler) tryAnswer(
+	ctx context.Context,
+	uauth *storage.User,
+	srvReq *game.TryAnswerRequest,
+	beforeCommit func(*game.TryAnswerResponse) error,
+) (*game.TryAnswerResponse, error) {
+	questID := srvReq.QuestID
 	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
 	if err != nil {
-		return xerrors.Errorf("start tx: %w", err)
+		return nil, xerrors.Errorf("start tx: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
 
 	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
-			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+			return nil, httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
 		}
-		return xerrors.Errorf("get quest: %w", err)
+		return nil, xerrors.Errorf("get quest: %w", err)
 	}
 	quests.SetStatus(quest)
 	if quest.Status != storage.StatusRunning {
-		return httperrors.New(http.StatusNotAcceptable, "cannot take hints before quest start")
+		return nil, httperrors.New(http.StatusNotAcceptable, "cannot answer tasks before quest start")
 	}
 
-	srv := game.NewService(s, s, s, s)
-	srvReq := game.TryAnswerRequest{TaskID: req.TaskID, Text: req.Text, QuestID: questID}
-	try, err := srv.TryAnswer(ctx, uauth, &srvReq)
+	batch := events.NewBatch(h.events)
+	srv := game.NewService(s, s, s, s, game.WithEvents(batch))
+	try, err := srv.TryAnswer(ctx, uauth, srvReq)
 	if err != nil {
-		return xerrors.Errorf("try answer: %w", err)
+		return nil, xerrors.Errorf("try answer: %w", err)
 	}
 
 	if quest.QuestType == storage.TypeLinear {
@@ -268,39 +368,46 @@ func (h *Handler) HandleTryAnswer(ctx context.Context, w http.ResponseWriter, r
 			TeamData:     &storage.TeamData{UserID: &uauth.ID},
 		})
 		if err != nil {
-			return xerrors.Errorf("get task groups: %w", err)
+			return nil, xerrors.Errorf("get task groups: %w", err)
 		}
 		userTeam, err := s.GetTeam(ctx, &storage.GetTeamRequest{UserRegistration: &storage.UserRegistration{UserID: uauth.ID, QuestID: questID}})
 		if err != nil {
 			if errors.Is(err, storage.ErrNotFound) {
-				return httperrors.Errorf(http.StatusNotAcceptable, "user %q has no team", uauth.ID)
+				return nil, httperrors.Errorf(http.StatusNotAcceptable, "user %q has no team", uauth.ID)
 			}
-			return xerrors.Errorf("get team: %w", err)
+			return nil, xerrors.Errorf("get team: %w", err)
 		}
 
 		req := game.AnswerDataRequest{Quest: quest, Team: userTeam, TaskGroups: taskGroups}
 		resp, err := srv.FillAnswerData(ctx, &req)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		try.TaskGroups = resp.TaskGroups
 	}
 
-	if err = tx.Commit(); err != nil {
-		return xerrors.Errorf("commit tx: %w", err)
+	if beforeCommit != nil {
+		if err = beforeCommit(try); err != nil {
+			return nil, err
+		}
 	}
-
-	if err = transport.ServeJSONResponse(w, http.StatusOK, try); err != nil {
-		return err
+	if err = tx.Commit(); err != nil {
+		return nil, xerrors.Errorf("commit tx: %w", err)
 	}
-	return nil
+	batch.Publish()
+	return try, nil
 }
 
 // HandleGetTableResults handles GET quest/:id/table request
 //
 // @Summary		Get admin leaderboard table during quest
+// @Description	With format parameter table is served as file with human-readable headers instead of JSON.
 // @Tags		PlayMode
+// @Produce		json
+// @Produce		text/csv
+// @Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param		quest_id	path		string		true	"Quest ID"
+// @Param		format		query		string		false	"Export format"	Enums(csv, xlsx)
 // @Success		200			{object}	game.TeamResults
 // @Failure		400
 // @Failure		401
@@ -313,6 +420,12 @@ func (h *Handler) HandleGetTableResults(ctx context.Context, w http.ResponseWrit
 	if err != nil {
 		return xerrors.Errorf("%w", err)
 	}
+	var format spreadsheet.Format
+	if f := transport.Query(r, "format"); f != "" {
+		if format, err = spreadsheet.ParseFormat(f); err != nil {
+			return err
+		}
+	}
 	uauth, err := jwt.GetUserFromContext(ctx)
 	if err != nil {
 		return xerrors.Errorf("%w", err)
@@ -339,6 +452,15 @@ func (h *Handler) HandleGetTableResults(ctx context.Context, w http.ResponseWrit
 		return xerrors.Errorf("get results: %w", err)
 	}
 
+	if format != "" {
+		var buf bytes.Buffer
+		if err = spreadsheet.Write(&buf, format, leaderBoard.Sheet()); err != nil {
+			return xerrors.Errorf("write %s table: %w", format, err)
+		}
+		transport.ServeFile(w, format.ContentType(), quest.Name+" results."+string(format), buf.Bytes())
+		return nil
+	}
+
 	if err = transport.ServeJSONResponse(w, http.StatusOK, leaderBoard); err != nil {
 		return err
 	}
@@ -348,6 +470,7 @@ func (h *Handler) HandleGetTableResults(ctx context.Context, w http.ResponseWrit
 // HandleLeaderboard handles GET quest/:id/leaderboard request
 //
 // @Summary		Get leaderboard table with final results
+// @Description	While leaderboard is frozen, only quest creator sees live results and everyone else sees results as of freeze moment.
 // @Tags		PlayMode
 // @Param		quest_id	path		string		true	"Quest ID"
 // @Success		200			{object}	game.LeaderboardResponse
@@ -356,6 +479,7 @@ func (h *Handler) HandleGetTableResults(ctx context.Context, w http.ResponseWrit
 // @Failure 	403
 // @Failure 	404
 // @Router		/quest/{id}/leaderboard [get]
+// @Security 	ApiKeyAuth
 func (h *Handler) HandleLeaderboard(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	questID, err := transport.UUIDParam(r, "id")
 	if err != nil {
@@ -378,8 +502,9 @@ func (h *Handler) HandleLeaderboard(ctx context.Context, w http.ResponseWriter,
 		return httperrors.New(http.StatusNotFound, "leaderboard not ready yet")
 	}
 
+	uauth, _ := jwt.GetUserFromContext(ctx)
 	srv := game.NewService(s, s, s, s)
-	leaderBoard, err := srv.GetLeaderboard(ctx, questID)
+	leaderBoard, err := srv.GetLeaderboard(ctx, quest, uauth)
 	if err != nil {
 		return xerrors.Errorf("get leaderboard: %w", err)
 	}
@@ -390,9 +515,281 @@ func (h *Handler) HandleLeaderboard(ctx context.Context, w http.ResponseWriter,
 	return nil
 }
 
+// HandleLeaderboardStream handles GET quest/:id/leaderboard/stream request
+//
+// @Summary		Stream leaderboard with Server-Sent Events
+// @Description	Sends "leaderboard" event with current leaderboard on connect and after every accepted answer or added penalty.
+// @Description	Bursts of updates are coalesced, so slow clients always receive the latest leaderboard. Heartbeat comments are sent to idle connections.
+// @Description	Like leaderboard, stream is available for everyone after quest finish, while running quest is streamed only to quest creator.
+// @Tags		PlayMode
+// @Produce		text/event-stream
+// @Param		quest_id	path		string		true	"Quest ID"
+// @Success		200			{object}	game.LeaderboardResponse
+// @Failure		400
+// @Failure 	404
+// @Failure 	406
+// @Router		/quest/{id}/leaderboard/stream [get]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleLeaderboardStream(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	quests.SetStatus(quest)
+	if quest.Status == storage.StatusOnRegistration || quest.Status == storage.StatusRegistrationDone {
+		return httperrors.New(http.StatusNotAcceptable, "leaderboard is not available before quest start")
+	}
+	uauth, _ := jwt.GetUserFromContext(ctx)
+	if !liveResultsAllowed(quest, uauth) {
+		return httperrors.New(http.StatusNotFound, "leaderboard not ready yet")
+	}
+
+	// subscribe before reading leaderboard, so that no update is lost in between
+	sub := h.events.Subscribe(questID, 1)
+	defer sub.Close()
+	srv := game.NewService(s, s, s, s)
+	leaderBoard, err := srv.GetLeaderboard(ctx, quest, uauth)
+	if err != nil {
+		return xerrors.Errorf("get leaderboard: %w", err)
+	}
+	getLeaderboard := func(ctx context.Context) (*game.LeaderboardResponse, error) {
+		// quest is read again, since leaderboard may have been frozen or revealed since last update
+		quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+		if err != nil {
+			return nil, xerrors.Errorf("get quest: %w", err)
+		}
+		return srv.GetLeaderboard(ctx, quest, uauth)
+	}
+	stream, err := transport.NewEventStream(w)
+	if err != nil {
+		return xerrors.Errorf("start event stream: %w", err)
+	}
+	// response is already started, so errors cannot be served to client anymore
+	if err = serveLeaderboardStream(ctx, stream, sub, getLeaderboard, leaderBoard); err != nil {
+		logging.Warn(ctx, "leaderboard stream interrupted", zap.Error(err))
+	}
+	return nil
+}
+
+func serveLeaderboardStream(
+	ctx context.Context,
+	stream *transport.EventStream,
+	sub *events.Subscription,
+	getLeaderboard func(context.Context) (*game.LeaderboardResponse, error),
+	leaderBoard *game.LeaderboardResponse,
+) error {
+	heartbeat := time.NewTicker(transport.DefaultHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		if leaderBoard != nil {
+			if err := stream.Send("leaderboard", leaderBoard); err != nil {
+				return xerrors.Errorf("send leaderboard: %w", err)
+			}
+			heartbeat.Reset(transport.DefaultHeartbeatInterval)
+			leaderBoard = nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := stream.Heartbeat(); err != nil {
+				return xerrors.Errorf("send heartbeat: %w", err)
+			}
+		case e := <-sub.Events():
+			// dropped events are covered by the update, since leaderboard is always read in full
+			if dropped := sub.Dropped(); dropped == 0 && !e.Type.ChangesScore() {
+				continue
+			}
+			var err error
+			if leaderBoard, err = getLeaderboard(ctx); err != nil {
+				return xerrors.Errorf("get leaderboard: %w", err)
+			}
+		}
+	}
+}
+
+// liveResultsAllowed checks that results of the quest can be shown to the user. Results of running quest are hidden
+// from everyone except quest creator the same way as leaderboard is
+func liveResultsAllowed(quest *storage.Quest, user *storage.User) bool {
+	return quest.Status == storage.StatusFinished || (user != nil && quest.Creator != nil && quest.Creator.ID == user.ID)
+}
+
+var scoreHistoryBuckets = map[string]time.Duration{
+	"1m": time.Minute,
+	"5m": 5 * time.Minute,
+}
+
+// HandleScoreHistory handles GET quest/:id/score-history request
+//
+// @Summary		Get cumulative score of every team over time
+// @Description	Points are built from accepted answers and penalties. With bucketing, changes within bucket are merged into single point at its end.
+// @Description	Like leaderboard, history is available for everyone after quest finish, while running quest is shown only to quest creator.
+// @Description	While leaderboard is frozen, only quest creator sees changes made after freeze moment.
+// @Tags		PlayMode
+// @Param		quest_id	path		string		true	"Quest ID"
+// @Param		bucket		query		string		false	"Bucket length"	Enums(1m, 5m)
+// @Success		200			{object}	game.ScoreHistoryResponse
+// @Failure		400
+// @Failure 	404
+// @Failure 	406
+// @Router		/quest/{id}/score-history [get]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleScoreHistory(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	var bucket time.Duration
+	if b := transport.Query(r, "bucket"); len(b) > 0 {
+		var ok bool
+		if bucket, ok = scoreHistoryBuckets[b]; !ok {
+			return httperrors.Errorf(http.StatusBadRequest, "unknown bucket %q, expected one of: 1m, 5m", b)
+		}
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	quests.SetStatus(quest)
+	if quest.Status == storage.StatusOnRegistration || quest.Status == storage.StatusRegistrationDone {
+		return httperrors.New(http.StatusNotAcceptable, "score history is not available before quest start")
+	}
+	uauth, _ := jwt.GetUserFromContext(ctx)
+	if !liveResultsAllowed(quest, uauth) {
+		return httperrors.New(http.StatusNotFound, "score history not ready yet")
+	}
+
+	srv := game.NewService(s, s, s, s)
+	history, err := srv.GetScoreHistory(ctx, quest, uauth, bucket)
+	if err != nil {
+		return xerrors.Errorf("get score history: %w", err)
+	}
+
+	if err = transport.ServeJSONResponse(w, http.StatusOK, history); err != nil {
+		return err
+	}
+	return nil
+}
+
+// teamEventsBufferSize is enough to absorb bursts of events for the whole quest
+const teamEventsBufferSize = 64
+
+type ResyncEvent struct {
+	Dropped int64 `json:"dropped"`
+}
+
+// HandleEvents handles GET quest/:id/play/events request
+//
+// @Summary		Stream play-mode events of user team with Server-Sent Events
+// @Description	SSE event name is equal to event type. Events of other teams are not sent.
+// @Description	If client reads events too slowly and some of them were dropped, "resync" event is sent and play data should be reloaded.
+// @Description	Stream is closed after quest_finished event or after user leaves the team.
+// @Tags		PlayMode
+// @Produce		text/event-stream
+// @Param		quest_id	path		string		true	"Quest ID"
+// @Success		200			{object}	events.Event
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Router		/quest/{id}/play/events [get]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	team, err := s.GetTeam(ctx, &storage.GetTeamRequest{UserRegistration: &storage.UserRegistration{UserID: uauth.ID, QuestID: questID}})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "user %q has no team", uauth.ID)
+		}
+		return xerrors.Errorf("get team: %w", err)
+	}
+	if team.RegistrationStatus != storage.RegistrationStatusAccepted {
+		return httperrors.New(http.StatusForbidden, "only accepted teams can receive game events")
+	}
+
+	sub := h.events.Subscribe(questID, teamEventsBufferSize)
+	defer sub.Close()
+	stream, err := transport.NewEventStream(w)
+	if err != nil {
+		return xerrors.Errorf("start event stream: %w", err)
+	}
+	if err = serveTeamEvents(ctx, stream, sub, team.ID, uauth.ID); err != nil {
+		logging.Warn(ctx, "team event stream interrupted", zap.Error(err))
+	}
+	return nil
+}
+
+func serveTeamEvents(ctx context.Context, stream *transport.EventStream, sub *events.Subscription, teamID, userID storage.ID) error {
+	heartbeat := time.NewTicker(transport.DefaultHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := stream.Heartbeat(); err != nil {
+				return xerrors.Errorf("send heartbeat: %w", err)
+			}
+		case e := <-sub.Events():
+			if dropped := sub.Dropped(); dropped > 0 {
+				if err := stream.Send("resync", ResyncEvent{Dropped: dropped}); err != nil {
+					return xerrors.Errorf("send resync: %w", err)
+				}
+			}
+			if e.TeamID != "" && e.TeamID != teamID {
+				continue
+			}
+			if err := stream.Send(string(e.Type), e); err != nil {
+				return xerrors.Errorf("send %s event: %w", e.Type, err)
+			}
+			heartbeat.Reset(transport.DefaultHeartbeatInterval)
+			if e.Type == events.QuestFinished {
+				return nil
+			}
+			if member, ok := e.Data.(events.MemberData); ok && e.Type == events.MemberLeft && member.UserID == userID {
+				return nil
+			}
+		}
+	}
+}
+
 // HandleAddPenalty handles POST quest/:id/penalty request
 //
-// @Summary		Add penalty to team
+// @Summary		Set manual penalty of team
+// @Description	Replaces manual penalty of the team with the given value: entries previously set by this endpoint are revoked and the new one is recorded.
+// @Description	Skip penalties and itemized entries are kept. Use POST /quest/{id}/penalty/add to record itemized penalty or bonus with a reason.
 // @Tags		PlayMode
 // @Param		quest_id	path		string					true	"Quest ID"
 // @Param		request		body		game.AddPenaltyRequest	true	"Data to set penalty"
@@ -434,13 +831,15 @@ func (h *Handler) HandleAddPenalty(ctx context.Context, w http.ResponseWriter, r
 		return httperrors.New(http.StatusForbidden, "only creator can add penalty to teams")
 	}
 
-	srv := game.NewService(s, s, s, s)
-	if err = srv.AddPenalty(ctx, &req); err != nil {
+	batch := events.NewBatch(h.events)
+	srv := game.NewService(s, s, s, s, game.WithEvents(batch))
+	if err = srv.AddPenalty(ctx, uauth, &req); err != nil {
 		return xerrors.Errorf("add penalty: %w", err)
 	}
 	if err = tx.Commit(); err != nil {
 		return xerrors.Errorf("commit tx: %w", err)
 	}
+	batch.Publish()
 	w.WriteHeader(http.StatusOK)
 	return nil
 }
@@ -488,6 +887,42 @@ func (h *Handler) HandleAnswerLog(ctx context.Context, w http.ResponseWriter, r
 		return httperrors.New(http.StatusForbidden, "only creator can view answer log")
 	}
 
+	opts := answerLogFilters(r)
+	if pageSize := transport.Query(r, "page_size"); len(pageSize) > 0 {
+		size, err := strconv.Atoi(pageSize)
+		if err != nil {
+			return httperrors.Errorf(http.StatusBadRequest, "bad page size: %w", err)
+		}
+		opts = append(opts, storage.WithPageSize(size))
+	}
+	if pageNo := transport.Query(r, "page_no"); len(pageNo) > 0 {
+		num, err := strconv.Atoi(pageNo)
+		if err != nil {
+			return httperrors.Errorf(http.StatusBadRequest, "bad page_no: %w", err)
+		}
+		opts = append(opts, storage.WithPageNumber(num))
+	}
+	if pageID := transport.Query(r, "page_id"); len(pageID) > 0 {
+		id, err := strconv.ParseInt(pageID, 10, 64)
+		if err != nil {
+			return httperrors.Errorf(http.StatusBadRequest, "bad page_id: %w", err)
+		}
+		opts = append(opts, storage.WithPageToken(id))
+	}
+
+	srv := game.NewService(s, s, s, s)
+	logs, err := srv.GetAnswerLogs(ctx, uauth, questID, opts...)
+	if err != nil {
+		return xerrors.Errorf("get answer logs: %w", err)
+	}
+
+	if err = transport.ServeJSONResponse(w, http.StatusOK, &logs); err != nil {
+		return err
+	}
+	return nil
+}
+
+func answerLogFilters(r *http.Request) []storage.FilteringOption {
 	var opts []storage.FilteringOption
 	if taskGroup := transport.Query(r, "task_group"); len(taskGroup) > 0 {
 		opts = append(opts, storage.WithGroupID(storage.ID(taskGroup)))
@@ -507,35 +942,208 @@ func (h *Handler) HandleAnswerLog(ctx context.Context, w http.ResponseWriter, r
 	if desc := transport.Query(r, "desc"); len(desc) > 0 {
 		opts = append(opts, storage.WithDateDesc())
 	}
-	if pageSize := transport.Query(r, "page_size"); len(pageSize) > 0 {
-		size, err := strconv.Atoi(pageSize)
-		if err != nil {
-			return httperrors.Errorf(http.StatusBadRequest, "bad page size: %w", err)
+	return opts
+}
+
+const (
+	answerLogFormatCSV    = "csv"
+	answerLogFormatNDJSON = "ndjson"
+)
+
+// HandleAnswerLogExport handles GET /quest/:id/answer_log/export request
+//
+// @Summary		Export full answer log
+// @Description	Streams every answer matching filters as CSV table or newline-delimited JSON objects without pagination.
+// @Tags		PlayMode
+// @Produce		text/csv
+// @Produce		application/x-ndjson
+// @Param      	task_group		query		string		false  "Task group ID"
+// @Param		task			query		string		false  "Task ID"
+// @Param		team			query		string		false  "Team ID"
+// @Param      	user			query		string		false  "User ID"
+// @Param      	accepted_only	query		bool		false  "Return only accepted answers"
+// @Param		desc			query		bool        false  "Return new answers first (descending)"
+// @Param		format			query		string		false  "Export format"	Enums(csv, ndjson)	default(csv)
+// @Success		200				{object}	game.AnswerLog
+// @Failure		400
+// @Failure		403
+// @Failure		404
+// @Router		/quest/{id}/answer_log/export [get]
+// @Security	ApiKeyAuth
+func (h *Handler) HandleAnswerLogExport(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	format := transport.Query(r, "format")
+	if len(format) == 0 {
+		format = answerLogFormatCSV
+	}
+	if format != answerLogFormatCSV && format != answerLogFormatNDJSON {
+		return httperrors.Errorf(http.StatusBadRequest, "unknown format %q, expected one of: csv, ndjson", format)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID.String())
 		}
-		opts = append(opts, storage.WithPageSize(size))
+		return xerrors.Errorf("get quest: %w", err)
 	}
-	if pageNo := transport.Query(r, "page_no"); len(pageNo) > 0 {
-		num, err := strconv.Atoi(pageNo)
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can view answer log")
+	}
+
+	var (
+		write func(*game.AnswerLog) error
+		flush = func() error { return nil }
+	)
+	fileName := quest.Name + " answer log." + format
+	switch format {
+	case answerLogFormatCSV:
+		if err = transport.StartFileStream(w, spreadsheet.FormatCSV.ContentType(), fileName); err != nil {
+			return xerrors.Errorf("start file stream: %w", err)
+		}
+		cw, err := spreadsheet.NewCSVWriter(w, game.AnswerLogHeader)
 		if err != nil {
-			return httperrors.Errorf(http.StatusBadRequest, "bad page_no: %w", err)
+			logging.Warn(ctx, "answer log export interrupted", zap.Error(err))
+			return nil
 		}
-		opts = append(opts, storage.WithPageNumber(num))
+		write = func(l *game.AnswerLog) error { return cw.WriteRow(l.Row()) }
+		flush = cw.Flush
+	case answerLogFormatNDJSON:
+		if err = transport.StartFileStream(w, "application/x-ndjson", fileName); err != nil {
+			return xerrors.Errorf("start file stream: %w", err)
+		}
+		enc := json.NewEncoder(w)
+		write = func(l *game.AnswerLog) error { return enc.Encode(l) }
 	}
-	if pageID := transport.Query(r, "page_id"); len(pageID) > 0 {
-		id, err := strconv.ParseInt(pageID, 10, 64)
-		if err != nil {
-			return httperrors.Errorf(http.StatusBadRequest, "bad page_id: %w", err)
+
+	// response is already started, so errors cannot be served to client anymore
+	srv := game.NewService(s, s, s, s)
+	if err = srv.IterAnswerLogs(ctx, questID, write, answerLogFilters(r)...); err == nil {
+		err = flush()
+	}
+	if err != nil {
+		logging.Warn(ctx, "answer log export interrupted", zap.Error(err))
+	}
+	return nil
+}
+
+// HandleGetReviewQueue handles GET /quest/:id/review request
+//
+// @Summary		Get manually verified answers waiting for review
+// @Tags		PlayMode
+// @Param		quest_id	path		string		true	"Quest ID"
+// @Success		200			{object}	game.ReviewQueueResponse
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure		404
+// @Router		/quest/{id}/review [get]
+// @Security	ApiKeyAuth
+func (h *Handler) HandleGetReviewQueue(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
 		}
-		opts = append(opts, storage.WithPageToken(id))
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can review answers")
 	}
 
 	srv := game.NewService(s, s, s, s)
-	logs, err := srv.GetAnswerLogs(ctx, uauth, questID, opts...)
+	queue, err := srv.GetReviewQueue(ctx, questID)
 	if err != nil {
-		return xerrors.Errorf("get answer logs: %w", err)
+		return xerrors.Errorf("get review queue: %w", err)
 	}
 
-	if err = transport.ServeJSONResponse(w, http.StatusOK, &logs); err != nil {
+	if err = transport.ServeJSONResponse(w, http.StatusOK, queue); err != nil {
+		return err
+	}
+	return nil
+}
+
+// HandleReviewAnswer handles POST /quest/:id/review request
+//
+// @Summary		Accept or reject manually verified answer
+// @Tags		PlayMode
+// @Param		quest_id	path		string						true	"Quest ID"
+// @Param		request		body		game.ReviewAnswerRequest	true	"Review verdict"
+// @Success		200			{object}	game.ReviewAnswerResponse
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure		404
+// @Failure		406
+// @Router		/quest/{id}/review [post]
+// @Security	ApiKeyAuth
+func (h *Handler) HandleReviewAnswer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req, err := transport.UnmarshalRequestData[game.ReviewAnswerRequest](r)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req.QuestID = questID
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can review answers")
+	}
+
+	batch := events.NewBatch(h.events)
+	srv := game.NewService(s, s, s, s, game.WithEvents(batch))
+	resp, err := srv.ReviewAnswer(ctx, &req)
+	if err != nil {
+		return xerrors.Errorf("review answer: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return xerrors.Errorf("commit tx: %w", err)
+	}
+	batch.Publish()
+
+	if err = transport.ServeJSONResponse(w, http.StatusOK, resp); err != nil {
 		return err
 	}
 	return nil
@@ -11,8 +11,11 @@ import (
 
 	"questspace/internal/accesscontrol"
 	"questspace/internal/pgdb"
+	"questspace/internal/questspace/events"
 	"questspace/internal/questspace/game"
 	"questspace/internal/questspace/quests"
+	"questspace/internal/questspace/taskgroups"
+	"questspace/internal/questspace/taskgroups/requests"
 	"questspace/internal/validate"
 	"questspace/pkg/auth/jwt"
 	"questspace/pkg/dbnode"
@@ -26,13 +29,15 @@ type Handler struct {
 	clientFactory    pgdb.QuestspaceClientFactory
 	fetcher          http.Client
 	inviteLinkPrefix string
+	events           events.Publisher
 }
 
-func NewHandler(cf pgdb.QuestspaceClientFactory, f http.Client, p string) *Handler {
+func NewHandler(cf pgdb.QuestspaceClientFactory, f http.Client, p string, e events.Publisher) *Handler {
 	return &Handler{
 		clientFactory:    cf,
 		fetcher:          f,
 		inviteLinkPrefix: p,
+		events:           e,
 	}
 }
 
@@ -65,6 +70,15 @@ func (h *Handler) HandleCreate(ctx context.Context, w http.ResponseWriter, r *ht
 			return err
 		}
 	}
+	if err = validate.AnswerLimits(req.AnswerLimits); err != nil {
+		return err
+	}
+	if err = validate.LeaderboardFreeze(req.LeaderboardFreeze); err != nil {
+		return err
+	}
+	if err = validate.Penalty("default hint penalty", req.DefaultHintPenalty); err != nil {
+		return err
+	}
 
 	s, err := h.clientFactory.NewStorage(ctx, dbnode.Master)
 	if err != nil {
@@ -93,6 +107,85 @@ func (h *Handler) HandleCreate(ctx context.Context, w http.ResponseWriter, r *ht
 	return nil
 }
 
+// HandleClone handles POST /quest/:id/clone request
+//
+// @Summary		Clone quest with its task groups, tasks and hints into new quest of the caller
+// @Description	Teams and results are not copied. When only start time is overridden, other times are moved together with it.
+// @Tags		Quests
+// @Param		quest_id	path		string					true	"Quest ID"
+// @Param		request		body		quests.CloneRequest		true	"Fields of the copy to override"
+// @Success		200			{object}	storage.Quest
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure		404
+// @Router		/quest/{quest_id}/clone [post]
+// @Security	ApiKeyAuth
+func (h *Handler) HandleClone(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	req, err := transport.UnmarshalRequestData[quests.CloneRequest](r)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	req.QuestID, err = transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = accesscontrol.Check(ctx, s, uauth); err != nil {
+		return err
+	}
+	original, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: req.QuestID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", req.QuestID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if original.Creator == nil || original.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can clone their quest")
+	}
+	taskGroups, err := s.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: req.QuestID, IncludeTasks: true})
+	if err != nil {
+		return xerrors.Errorf("get task groups: %w", err)
+	}
+
+	createReq, shift := quests.CloneQuestRequest(original, uauth, &req)
+	quest, err := s.CreateQuest(ctx, createReq)
+	if err != nil {
+		return xerrors.Errorf("create quest: %w", err)
+	}
+	// media links of the original quest are already validated
+	srv := taskgroups.NewService(s, s, requests.NopValidator{}, quest.HintPenalty())
+	if _, err = srv.Clone(ctx, quest.ID, taskGroups, shift); err != nil {
+		return xerrors.Errorf("clone task groups: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return xerrors.Errorf("commit tx: %w", err)
+	}
+
+	quests.SetStatus(quest)
+	if err = transport.ServeJSONResponse(w, http.StatusOK, quest); err != nil {
+		return err
+	}
+
+	logging.Info(ctx, "cloned quest",
+		zap.Stringer("quest_id", quest.ID),
+		zap.Stringer("original_quest_id", original.ID),
+		zap.Stringer("creator_id", uauth.ID),
+	)
+	return nil
+}
+
 type TeamQuestResponse struct {
 	Quest       *storage.Quest            `json:"quest"`
 	Team        *storage.Team             `json:"team,omitempty"`
@@ -157,8 +250,9 @@ func (h *Handler) HandleGet(ctx context.Context, w http.ResponseWriter, r *http.
 	}
 
 	if quest.Status == storage.StatusFinished {
+		uauth, _ := jwt.GetUserFromContext(ctx)
 		srv := game.NewService(s, s, s, s)
-		leaderboard, err := srv.GetLeaderboard(ctx, questID)
+		leaderboard, err := srv.GetLeaderboard(ctx, quest, uauth)
 		if err == nil {
 			resp.Leaderboard = leaderboard
 		} else {
@@ -251,6 +345,15 @@ func (h *Handler) HandleUpdate(ctx context.Context, w http.ResponseWriter, r *ht
 			return err
 		}
 	}
+	if err = validate.AnswerLimits(req.AnswerLimits); err != nil {
+		return err
+	}
+	if err = validate.LeaderboardFreeze(req.LeaderboardFreeze); err != nil {
+		return err
+	}
+	if err = validate.Penalty("default hint penalty", req.DefaultHintPenalty); err != nil {
+		return err
+	}
 	uauth, err := jwt.GetUserFromContext(ctx)
 	if err != nil {
 		return xerrors.Errorf("%w", err)
@@ -363,6 +466,51 @@ func (h *Handler) HandleFinish(ctx context.Context, w http.ResponseWriter, r *ht
 	if err = s.FinishQuest(ctx, &storage.FinishQuestRequest{ID: id}); err != nil {
 		return xerrors.Errorf("finish quest: %w", err)
 	}
+	h.events.Publish(events.New(events.QuestFinished, id, "", nil))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// HandleRevealLeaderboard handles POST /quest/:id/leaderboard/reveal request
+//
+// @Summary		Reveal frozen leaderboard to teams
+// @Tags 		Quests
+// @Param		quest_id	path	string	true	"Quest ID"
+// @Success		200
+// @Failure    	401
+// @Failure    	403
+// @Failure    	404
+// @Router		/quest/{quest_id}/leaderboard/reveal [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleRevealLeaderboard(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Master)
+	if err != nil {
+		return xerrors.Errorf("get storage client: %w", err)
+	}
+
+	q, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: id})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "quest %q not found", id)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if q.Creator == nil || q.Creator.ID != uauth.ID {
+		return httperrors.New(http.StatusForbidden, "only creator can reveal leaderboard of their quests")
+	}
+
+	if err = s.RevealLeaderboard(ctx, &storage.RevealLeaderboardRequest{ID: id}); err != nil {
+		return xerrors.Errorf("reveal leaderboard: %w", err)
+	}
+	h.events.Publish(events.New(events.LeaderboardRevealed, id, "", nil))
 	w.WriteHeader(http.StatusOK)
 	return nil
 }
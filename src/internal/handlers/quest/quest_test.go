@@ -15,6 +15,7 @@ import (
 	"go.uber.org/zap/zaptest"
 
 	"questspace/internal/pgdb"
+	"questspace/internal/questspace/events"
 	"questspace/pkg/auth/jwt"
 	jwtmock "questspace/pkg/auth/jwt/mocks"
 	"questspace/pkg/middleware"
@@ -34,7 +35,7 @@ func TestHandleCreate(t *testing.T) {
 
 	router := transport.NewRouter()
 	router.Use(middleware.CtxLog(zaptest.NewLogger(t)))
-	handler := NewHandler(factory, http.Client{}, "hello/")
+	handler := NewHandler(factory, http.Client{}, "hello/", events.NewBroker())
 	router.H().Use(jwt.AuthMiddlewareStrict(jwtParser)).POST("/quest", transport.WrapCtxErr(handler.HandleCreate))
 
 	now := ptr.Time(time.Unix(time.Now().Unix(), 0))
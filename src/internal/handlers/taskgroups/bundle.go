@@ -0,0 +1,292 @@
+package taskgroups
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+	"go.uber.org/zap"
+
+	"questspace/internal/accesscontrol"
+	"questspace/internal/questspace/bundle"
+	"questspace/internal/questspace/quests"
+	"questspace/internal/questspace/taskgroups"
+	"questspace/internal/questspace/tasks"
+	"questspace/pkg/auth/jwt"
+	"questspace/pkg/dbnode"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/logging"
+	"questspace/pkg/storage"
+	"questspace/pkg/transport"
+)
+
+// maxBundleSize limits size of imported bundle, which is read into memory as a whole
+const maxBundleSize = 8 << 20
+
+func bundleFormat(r *http.Request) (bundle.Format, error) {
+	format := bundle.Format(transport.Query(r, "format"))
+	if len(format) == 0 {
+		return bundle.FormatJSON, nil
+	}
+	if format != bundle.FormatJSON && format != bundle.FormatYAML {
+		return "", httperrors.Errorf(http.StatusBadRequest, "unknown format %q, expected one of: json, yaml", format)
+	}
+	return format, nil
+}
+
+// HandleExport handles GET /quest/:id/export request
+//
+// @Summary		Export quest with task groups, tasks and hints as versioned bundle
+// @Description	Groups and tasks get their ids as keys, so that edited bundle can be imported back into the same quest.
+// @Tags		TaskGroups
+// @Produce		application/json
+// @Produce		application/yaml
+// @Param		quest_id	path		string	true	"Quest ID"
+// @Param		format		query		string	false	"Bundle format"	Enums(json, yaml)	default(json)
+// @Success		200			{object}	bundle.Bundle
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Router		/quest/{id}/export [get]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleExport(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	format, err := bundleFormat(r)
+	if err != nil {
+		return err
+	}
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+
+	s, err := h.clientFactory.NewStorage(ctx, dbnode.Alive)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "not found quest %q", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.Errorf(http.StatusForbidden, "only creator can export quest")
+	}
+	taskGroups, err := s.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID, IncludeTasks: true})
+	if err != nil {
+		return xerrors.Errorf("get task groups: %w", err)
+	}
+
+	data, err := bundle.Encode(bundle.Export(quest, taskGroups), format)
+	if err != nil {
+		return xerrors.Errorf("encode bundle: %w", err)
+	}
+	transport.ServeFile(w, format.ContentType(), quest.Name+"."+string(format), data)
+	return nil
+}
+
+type ImportResponse struct {
+	// Quest is omitted in dry run, since no changes are saved
+	Quest   *storage.Quest  `json:"quest,omitempty"`
+	Changes []bundle.Change `json:"changes"`
+	DryRun  bool            `json:"dry_run,omitempty"`
+}
+
+// readBundle reads bundle from request body and validates it
+func readBundle(w http.ResponseWriter, r *http.Request) (*bundle.Bundle, error) {
+	format, err := bundleFormat(r)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBundleSize)
+	defer func() { _ = r.Body.Close() }()
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, httperrors.Errorf(http.StatusRequestEntityTooLarge, "bundle is larger than %d bytes", maxBytesErr.Limit)
+		}
+		return nil, xerrors.Errorf("read body: %w", err)
+	}
+	b, err := bundle.Decode(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// HandleImportNew handles POST /import/quest request
+//
+// @Summary		Create new quest from bundle
+// @Description	In dry run the import is checked and its changes are returned without saving them.
+// @Tags		TaskGroups
+// @Accept		application/json
+// @Accept		application/yaml
+// @Param		request	body		bundle.Bundle	true	"Bundle of quest"
+// @Param		format	query		string			false	"Bundle format"	Enums(json, yaml)	default(json)
+// @Param		dry_run	query		bool			false	"Return changes without saving them"
+// @Success		200		{object}	taskgroups.ImportResponse
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure		413
+// @Router		/import/quest [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleImportNew(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	b, err := readBundle(w, r)
+	if err != nil {
+		return err
+	}
+	dryRun := len(transport.Query(r, "dry_run")) > 0
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	if len(b.Quest.MediaLink) > 0 {
+		if err = h.imageValidator.ValidateImageURLs(ctx, b.Quest.MediaLink); err != nil {
+			return xerrors.Errorf("validate quest media: %w", err)
+		}
+	}
+
+	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = accesscontrol.Check(ctx, s, uauth); err != nil {
+		return err
+	}
+	changes, err := bundle.Diff(nil, b)
+	if err != nil {
+		return xerrors.Errorf("diff bundle: %w", err)
+	}
+	createReq := b.Quest
+	createReq.Creator = uauth
+	quest, err := s.CreateQuest(ctx, &createReq)
+	if err != nil {
+		return xerrors.Errorf("create quest: %w", err)
+	}
+	updater := taskgroups.NewUpdater(s, tasks.NewUpdater(s, quest.HintPenalty()), h.imageValidator)
+	if _, err = bundle.Import(ctx, updater, quest.ID, nil, b); err != nil {
+		return xerrors.Errorf("import bundle: %w", err)
+	}
+
+	resp := ImportResponse{Changes: changes, DryRun: dryRun}
+	if !dryRun {
+		if err = tx.Commit(); err != nil {
+			return xerrors.Errorf("commit tx: %w", err)
+		}
+		quests.SetStatus(quest)
+		resp.Quest = quest
+		logging.Info(ctx, "imported quest",
+			zap.Stringer("quest_id", quest.ID),
+			zap.String("quest_name", quest.Name),
+			zap.Stringer("creator_id", uauth.ID),
+		)
+	}
+	if err = transport.ServeJSONResponse(w, http.StatusOK, resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// HandleImport handles POST /quest/:id/import request
+//
+// @Summary		Update quest with task groups, tasks and hints from bundle
+// @Description	Groups and tasks with keys equal to ids of existing ones are updated, others are created, and existing ones missing in bundle are deleted.
+// @Description	In dry run the import is checked and its changes are returned without saving them.
+// @Tags		TaskGroups
+// @Accept		application/json
+// @Accept		application/yaml
+// @Param		quest_id	path		string			true	"Quest ID"
+// @Param		request		body		bundle.Bundle	true	"Bundle of quest"
+// @Param		format		query		string			false	"Bundle format"	Enums(json, yaml)	default(json)
+// @Param		dry_run		query		bool			false	"Return changes without saving them"
+// @Success		200			{object}	taskgroups.ImportResponse
+// @Failure		400
+// @Failure		401
+// @Failure		403
+// @Failure 	404
+// @Failure		413
+// @Router		/quest/{id}/import [post]
+// @Security 	ApiKeyAuth
+func (h *Handler) HandleImport(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	questID, err := transport.UUIDParam(r, "id")
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	b, err := readBundle(w, r)
+	if err != nil {
+		return err
+	}
+	dryRun := len(transport.Query(r, "dry_run")) > 0
+	uauth, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("%w", err)
+	}
+	if len(b.Quest.MediaLink) > 0 {
+		if err = h.imageValidator.ValidateImageURLs(ctx, b.Quest.MediaLink); err != nil {
+			return xerrors.Errorf("validate quest media: %w", err)
+		}
+	}
+
+	s, tx, err := h.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	quest, err := s.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "not found quest %q", questID)
+		}
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	if quest.Creator.ID != uauth.ID {
+		return httperrors.Errorf(http.StatusForbidden, "cannot change others' quests")
+	}
+	taskGroups, err := s.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID, IncludeTasks: true})
+	if err != nil {
+		return xerrors.Errorf("get task groups: %w", err)
+	}
+	current := bundle.Export(quest, taskGroups)
+	changes, err := bundle.Diff(current, b)
+	if err != nil {
+		return xerrors.Errorf("diff bundle: %w", err)
+	}
+
+	quest, err = s.UpdateQuest(ctx, b.UpdateQuestRequest(questID))
+	if err != nil {
+		return xerrors.Errorf("update quest: %w", err)
+	}
+	updater := taskgroups.NewUpdater(s, tasks.NewUpdater(s, quest.HintPenalty()), h.imageValidator)
+	if _, err = bundle.Import(ctx, updater, questID, current, b); err != nil {
+		return xerrors.Errorf("import bundle: %w", err)
+	}
+
+	resp := ImportResponse{Changes: changes, DryRun: dryRun}
+	if !dryRun {
+		if err = tx.Commit(); err != nil {
+			return xerrors.Errorf("commit tx: %w", err)
+		}
+		quests.SetStatus(quest)
+		resp.Quest = quest
+	}
+	if err = transport.ServeJSONResponse(w, http.StatusOK, resp); err != nil {
+		return err
+	}
+	return nil
+}
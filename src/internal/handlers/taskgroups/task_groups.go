@@ -37,6 +37,7 @@ type TaskGroups []*storage.TaskGroup
 // HandleBulkUpdate handles PATCH quest/:id/task-groups/bulk request
 //
 // @Summary		Patch task groups by creating new ones, delete, update and reorder all ones. Returns all exising task groups.
+// @Description	Transitions can lead to groups and be taken by tasks created by the same request, referencing them by their keys instead of ids.
 // @Tags		TaskGroups
 // @Param		request	body		storage.TaskGroupsBulkUpdateRequest	true	"Requests to delete/create/update task groups"
 // @Success		200		{object}	requests.CreateFullResponse
@@ -83,7 +84,7 @@ func (h *Handler) HandleBulkUpdate(ctx context.Context, w http.ResponseWriter, r
 		return httperrors.Errorf(http.StatusForbidden, "cannot change others' quests")
 	}
 
-	taskUpdater := tasks.NewUpdater(s)
+	taskUpdater := tasks.NewUpdater(s, quest.HintPenalty())
 	updater := taskgroups.NewUpdater(s, taskUpdater, h.imageValidator)
 	tasksGroups, err := updater.BulkUpdateTaskGroups(ctx, &req)
 	if err != nil {
@@ -149,7 +150,7 @@ func (h *Handler) HandleCreate(ctx context.Context, w http.ResponseWriter, r *ht
 	if q.Status == storage.StatusRunning || q.Status == storage.StatusWaitResults || q.Status == storage.StatusFinished {
 		return httperrors.Errorf(http.StatusForbidden, "do not use create method when quest is already running")
 	}
-	serv := taskgroups.NewService(s, s, h.imageValidator)
+	serv := taskgroups.NewService(s, s, h.imageValidator, q.HintPenalty())
 	resp, err := serv.Create(ctx, &req)
 	if err != nil {
 		return xerrors.Errorf("create taskgroups: %w", err)
@@ -10,6 +10,7 @@ import (
 
 	"questspace/internal/handlers/quest"
 	"questspace/internal/pgdb"
+	"questspace/internal/questspace/events"
 	"questspace/internal/questspace/quests"
 	"questspace/internal/questspace/teams"
 	"questspace/pkg/auth/jwt"
@@ -22,12 +23,14 @@ import (
 type Handler struct {
 	factory          pgdb.QuestspaceClientFactory
 	inviteLinkPrefix string
+	events           events.Publisher
 }
 
-func NewHandler(f pgdb.QuestspaceClientFactory, prefix string) *Handler {
+func NewHandler(f pgdb.QuestspaceClientFactory, prefix string, e events.Publisher) *Handler {
 	return &Handler{
 		factory:          f,
 		inviteLinkPrefix: prefix,
+		events:           e,
 	}
 }
 
@@ -110,7 +113,8 @@ func (h *Handler) HandleJoin(ctx context.Context, w http.ResponseWriter, r *http
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	teamService := teams.NewService(s, h.inviteLinkPrefix)
+	batch := events.NewBatch(h.events)
+	teamService := teams.NewService(s, h.inviteLinkPrefix, teams.WithEvents(batch))
 	team, err := teamService.JoinTeam(ctx, &storage.JoinTeamRequest{InvitePath: invitePath, User: uauth})
 	if err != nil {
 		return xerrors.Errorf("join team: %w", err)
@@ -118,6 +122,7 @@ func (h *Handler) HandleJoin(ctx context.Context, w http.ResponseWriter, r *http
 	if err := tx.Commit(); err != nil {
 		return xerrors.Errorf("commit tx: %w", err)
 	}
+	batch.Publish()
 
 	if err = transport.ServeJSONResponse(w, http.StatusOK, team); err != nil {
 		return err
@@ -304,11 +309,13 @@ func (h *Handler) HandleChangeLeader(ctx context.Context, w http.ResponseWriter,
 	if err != nil {
 		return xerrors.Errorf("get storage: %w", err)
 	}
-	teamService := teams.NewService(s, h.inviteLinkPrefix)
+	batch := events.NewBatch(h.events)
+	teamService := teams.NewService(s, h.inviteLinkPrefix, teams.WithEvents(batch))
 	team, err := teamService.ChangeLeader(ctx, uauth, &storage.ChangeLeaderRequest{ID: teamID, CaptainID: req.NewCaptainID})
 	if err != nil {
 		return xerrors.Errorf("change captain of team %q to %q: %w", teamID, req.NewCaptainID, err)
 	}
+	batch.Publish()
 
 	if err = transport.ServeJSONResponse(w, http.StatusOK, team); err != nil {
 		return err
@@ -346,7 +353,8 @@ func (h *Handler) HandleLeave(ctx context.Context, w http.ResponseWriter, r *htt
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	teamService := teams.NewService(s, h.inviteLinkPrefix)
+	batch := events.NewBatch(h.events)
+	teamService := teams.NewService(s, h.inviteLinkPrefix, teams.WithEvents(batch))
 	team, err := teamService.LeaveTeam(ctx, uauth, teamID, storage.ID(newCaptainID))
 	if err != nil {
 		return xerrors.Errorf("leave team: %w", err)
@@ -355,6 +363,7 @@ func (h *Handler) HandleLeave(ctx context.Context, w http.ResponseWriter, r *htt
 	if err := tx.Commit(); err != nil {
 		return xerrors.Errorf("commit tx: %w", err)
 	}
+	batch.Publish()
 
 	if err = transport.ServeJSONResponse(w, http.StatusOK, team); err != nil {
 		return err
@@ -392,11 +401,13 @@ func (h *Handler) HandleRemoveUser(ctx context.Context, w http.ResponseWriter, r
 	if err != nil {
 		return xerrors.Errorf("get storage: %w", err)
 	}
-	teamService := teams.NewService(s, h.inviteLinkPrefix)
+	batch := events.NewBatch(h.events)
+	teamService := teams.NewService(s, h.inviteLinkPrefix, teams.WithEvents(batch))
 	team, err := teamService.RemoveUser(ctx, uauth, &storage.RemoveUserRequest{UserID: userID, ID: teamID})
 	if err != nil {
 		return xerrors.Errorf("remove user %q from team %q: %w", userID, teamID, err)
 	}
+	batch.Publish()
 
 	if err = transport.ServeJSONResponse(w, http.StatusOK, team); err != nil {
 		return err
@@ -490,11 +501,13 @@ func (h *Handler) HandleAcceptTeam(ctx context.Context, w http.ResponseWriter, r
 	if err != nil {
 		return xerrors.Errorf("get storage: %w", err)
 	}
-	teamService := teams.NewService(s, h.inviteLinkPrefix)
+	batch := events.NewBatch(h.events)
+	teamService := teams.NewService(s, h.inviteLinkPrefix, teams.WithEvents(batch))
 	teams, err := teamService.AcceptTeam(ctx, uauth, questID, teamID)
 	if err != nil {
 		return err
 	}
+	batch.Publish()
 	resp := ManyTeamsResponse{
 		Teams: teams,
 	}
@@ -128,6 +128,23 @@ func (v *Validator) ValidateImageURLs(ctx context.Context, imageURLs ...string)
 	return nil
 }
 
+// ValidateFile applies the same MIME type and size checks to uploaded file content and returns its detected content type
+func (v *Validator) ValidateFile(data []byte) (string, error) {
+	if size := int64(len(data)); size > v.maxSize {
+		return "", httperrors.Errorf(
+			http.StatusRequestEntityTooLarge,
+			"file too large: %s vs max allowed %s",
+			formatSize(size),
+			formatSize(v.maxSize),
+		)
+	}
+	contentType := http.DetectContentType(data)
+	if !v.suitsPrefixes(contentType) {
+		return "", httperrors.Errorf(http.StatusUnsupportedMediaType, "unsupported file type: %q", contentType)
+	}
+	return contentType, nil
+}
+
 func (v *Validator) validateImage(ctx context.Context, imageURL string) (size int64, err error) {
 	if imageURL == "" {
 		return 0, nil
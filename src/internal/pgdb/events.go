@@ -0,0 +1,127 @@
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/yandex/perforator/library/go/core/xerrors"
+	"go.uber.org/zap"
+
+	"questspace/internal/pgdb/pgclient"
+	"questspace/internal/questspace/events"
+	"questspace/pkg/dbnode"
+	"questspace/pkg/logging"
+	"questspace/pkg/storage"
+)
+
+const (
+	eventsChannel = "questspace_events"
+	// notifyTimeout limits publishing of a single event, since publishers are not bound to any context
+	notifyTimeout = 5 * time.Second
+	// primaryCheckInterval is how often listener checks that it is still connected to primary node
+	primaryCheckInterval = 30 * time.Second
+	relayRetryInterval   = time.Second
+)
+
+// EventRelay passes game events between application instances with NOTIFY and LISTEN of primary node.
+// Events sent while listener reconnects are lost, which subscribers notice as dropped events.
+type EventRelay struct {
+	picker dbnode.Picker
+}
+
+func NewEventRelay(p dbnode.Picker) *EventRelay {
+	return &EventRelay{picker: p}
+}
+
+func (r *EventRelay) Send(e events.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	db, err := r.picker.MasterNode(ctx)
+	if err != nil {
+		return xerrors.Errorf("get primary node: %w", err)
+	}
+	return NotifyEvent(ctx, pgclient.NewClient(sq.WrapStdSqlCtx(db)), e)
+}
+
+// NotifyEvent sends event to listeners of every instance. When storage runs a transaction,
+// event is sent only if it is committed together with the changes it reports.
+func NotifyEvent(ctx context.Context, s storage.QuestStorage, e events.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return xerrors.Errorf("marshal event: %w", err)
+	}
+	if err = s.Notify(ctx, &storage.NotifyRequest{Channel: eventsChannel, Payload: string(payload)}); err != nil {
+		return xerrors.Errorf("notify: %w", err)
+	}
+	return nil
+}
+
+// Listen delivers events sent by every instance to subscribers of broker until context is canceled
+func (r *EventRelay) Listen(ctx context.Context, b *events.Broker) {
+	for {
+		err := r.listen(ctx, b)
+		if ctx.Err() != nil {
+			return
+		}
+		logging.Error(ctx, "event listener failed", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(relayRetryInterval):
+		}
+	}
+}
+
+func (r *EventRelay) listen(ctx context.Context, b *events.Broker) error {
+	db, err := r.picker.MasterNode(ctx)
+	if err != nil {
+		return xerrors.Errorf("get primary node: %w", err)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return xerrors.Errorf("get connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// connection is never returned to pool, since it keeps listening to the channel
+	return conn.Raw(func(driverConn any) error {
+		pgConn := driverConn.(*stdlib.Conn).Conn()
+		if _, err := pgConn.Exec(ctx, `LISTEN `+eventsChannel); err != nil {
+			return errors.Join(xerrors.Errorf("listen: %w", err), driver.ErrBadConn)
+		}
+		for {
+			waitCtx, cancel := context.WithTimeout(ctx, primaryCheckInterval)
+			n, err := pgConn.WaitForNotification(waitCtx)
+			cancel()
+			switch {
+			case ctx.Err() != nil:
+				return driver.ErrBadConn
+			case errors.Is(err, context.DeadlineExceeded):
+				if !r.onPrimary(ctx, db) {
+					return errors.Join(xerrors.New("primary node has changed"), driver.ErrBadConn)
+				}
+				continue
+			case err != nil:
+				return errors.Join(xerrors.Errorf("wait for notification: %w", err), driver.ErrBadConn)
+			}
+			e, err := events.Decode([]byte(n.Payload))
+			if err != nil {
+				logging.Error(ctx, "could not decode event", zap.Error(err))
+				continue
+			}
+			b.Deliver(e)
+		}
+	})
+}
+
+// onPrimary reports whether db is still primary node, since events are not sent to other nodes
+func (r *EventRelay) onPrimary(ctx context.Context, db *sql.DB) bool {
+	primary, err := r.picker.MasterNode(ctx)
+	return err == nil && primary == db
+}
@@ -0,0 +1,99 @@
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/dbnode"
+)
+
+// AdvisoryLock elects leader among application instances with session level advisory lock of primary node.
+// Instance holding the lock keeps a dedicated connection, so leadership is lost as soon as the connection is closed
+// or its node stops being primary.
+type AdvisoryLock struct {
+	picker dbnode.Picker
+	key    int64
+
+	mu sync.Mutex
+	// db is the node, which conn belongs to
+	db   *sql.DB
+	conn *sql.Conn
+}
+
+func NewAdvisoryLock(p dbnode.Picker, key int64) *AdvisoryLock {
+	return &AdvisoryLock{picker: p, key: key}
+}
+
+// TryLead reports whether instance holds the lock, trying to acquire it when it does not
+func (l *AdvisoryLock) TryLead(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	db, err := l.picker.MasterNode(ctx)
+	if err != nil {
+		return false, xerrors.Errorf("get primary node: %w", err)
+	}
+	if l.conn != nil {
+		if l.db == db && l.onPrimary(ctx) {
+			return true, nil
+		}
+		// after failover another instance may take the lock on the new primary, so the old one must not be trusted.
+		// Lock is released by database together with the session
+		discard(l.conn)
+		l.db, l.conn = nil, nil
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, xerrors.Errorf("get connection: %w", err)
+	}
+	var acquired bool
+	if err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, xerrors.Errorf("try advisory lock: %w", err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+	l.db, l.conn = db, conn
+	return true, nil
+}
+
+// onPrimary reports whether connection holding the lock is alive and its node is not a replica
+func (l *AdvisoryLock) onPrimary(ctx context.Context) bool {
+	var inRecovery bool
+	if err := l.conn.QueryRowContext(ctx, `SELECT pg_is_in_recovery()`).Scan(&inRecovery); err != nil {
+		return false
+	}
+	return !inRecovery
+}
+
+// Resign releases the lock, so that another instance can become leader
+func (l *AdvisoryLock) Resign(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	conn := l.conn
+	l.db, l.conn = nil, nil
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key); err != nil {
+		// session still may hold the lock, so it must not get back to pool
+		discard(conn)
+		return xerrors.Errorf("advisory unlock: %w", err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// discard closes connection instead of returning it to pool, so that its session ends
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
@@ -0,0 +1,5 @@
+ALTER TABLE questspace.answer_try ADD COLUMN id BIGSERIAL PRIMARY KEY;
+
+ALTER TABLE questspace.answer_try ADD COLUMN review_status varchar DEFAULT NULL;
+
+CREATE INDEX answer_try_review_status_idx ON questspace.answer_try (review_status) WHERE review_status IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE questspace.task ADD COLUMN matcher varchar DEFAULT NULL;
+
+ALTER TABLE questspace.task ADD COLUMN matcher_tolerance double precision DEFAULT NULL;
+
+ALTER TABLE questspace.task ADD COLUMN matcher_threshold integer DEFAULT NULL;
@@ -0,0 +1,15 @@
+ALTER TABLE questspace.quest ADD COLUMN max_attempts integer DEFAULT NULL;
+ALTER TABLE questspace.quest ADD COLUMN cooldown_attempts integer DEFAULT NULL;
+ALTER TABLE questspace.quest ADD COLUMN cooldown bigint DEFAULT NULL;
+ALTER TABLE questspace.quest ADD COLUMN wrong_penalty_percent integer DEFAULT NULL;
+ALTER TABLE questspace.quest ADD COLUMN wrong_penalty_score integer DEFAULT NULL;
+
+ALTER TABLE questspace.task ADD COLUMN max_attempts integer DEFAULT NULL;
+ALTER TABLE questspace.task ADD COLUMN cooldown_attempts integer DEFAULT NULL;
+ALTER TABLE questspace.task ADD COLUMN cooldown bigint DEFAULT NULL;
+ALTER TABLE questspace.task ADD COLUMN wrong_penalty_percent integer DEFAULT NULL;
+ALTER TABLE questspace.task ADD COLUMN wrong_penalty_score integer DEFAULT NULL;
+
+ALTER TABLE questspace.answer_try ADD COLUMN penalty integer NOT NULL DEFAULT 0;
+
+CREATE INDEX answer_try_team_task_idx ON questspace.answer_try (team_id, task_id);
@@ -0,0 +1,6 @@
+ALTER TABLE questspace.task ADD COLUMN scoring jsonb DEFAULT NULL;
+
+ALTER TABLE questspace.answer_try ADD COLUMN bonus integer NOT NULL DEFAULT 0;
+ALTER TABLE questspace.answer_try ADD COLUMN decay integer NOT NULL DEFAULT 0;
+
+CREATE INDEX answer_try_accepted_task_idx ON questspace.answer_try (task_id) WHERE accepted;
@@ -0,0 +1,4 @@
+ALTER TABLE questspace.quest ADD COLUMN leaderboard_freeze bigint DEFAULT NULL;
+ALTER TABLE questspace.quest ADD COLUMN leaderboard_revealed bool NOT NULL DEFAULT false;
+
+ALTER TABLE questspace.team_penalty ADD COLUMN time_created timestamp NOT NULL DEFAULT NOW();
@@ -0,0 +1,12 @@
+CREATE TABLE questspace.task_group_transition (
+    group_id uuid NOT NULL REFERENCES questspace.task_group (id) ON DELETE CASCADE,
+    index integer NOT NULL,
+    next_group_id uuid NOT NULL REFERENCES questspace.task_group (id) ON DELETE CASCADE,
+    task_id uuid REFERENCES questspace.task (id) ON DELETE CASCADE,
+    answers varchar[] NOT NULL DEFAULT '{}',
+
+    UNIQUE (index, group_id)
+);
+
+ALTER TABLE questspace.task_group_team_info
+    ADD COLUMN next_group_id uuid DEFAULT NULL REFERENCES questspace.task_group (id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE questspace.task_group ADD COLUMN allow_skip boolean NOT NULL DEFAULT false;
+ALTER TABLE questspace.task_group ADD COLUMN skip_penalty_percent integer DEFAULT NULL;
+ALTER TABLE questspace.task_group ADD COLUMN skip_penalty_score integer DEFAULT NULL;
+
+ALTER TABLE questspace.team_penalty
+    ADD COLUMN group_id uuid DEFAULT NULL REFERENCES questspace.task_group (id) ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+ALTER TABLE questspace.task ADD COLUMN requires jsonb DEFAULT NULL;
+ALTER TABLE questspace.task_group ADD COLUMN requires jsonb DEFAULT NULL;
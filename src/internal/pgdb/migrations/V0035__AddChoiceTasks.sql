@@ -0,0 +1,2 @@
+ALTER TABLE questspace.task ADD COLUMN task_type varchar NOT NULL DEFAULT 'text';
+ALTER TABLE questspace.task ADD COLUMN choice jsonb DEFAULT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE questspace.answer_try ADD COLUMN file_key varchar DEFAULT NULL;
+ALTER TABLE questspace.answer_try ADD COLUMN file_type varchar DEFAULT NULL;
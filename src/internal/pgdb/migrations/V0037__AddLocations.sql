@@ -0,0 +1,2 @@
+ALTER TABLE questspace.task ADD COLUMN location jsonb DEFAULT NULL;
+ALTER TABLE questspace.task_group ADD COLUMN location jsonb DEFAULT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE questspace.task ADD COLUMN parts jsonb DEFAULT NULL;
+ALTER TABLE questspace.answer_try ADD COLUMN part_idx integer DEFAULT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE questspace.hint ADD COLUMN opens_after bigint DEFAULT NULL;
+ALTER TABLE questspace.hint ADD COLUMN auto_penalty_percent integer DEFAULT NULL;
+ALTER TABLE questspace.hint ADD COLUMN auto_penalty_score integer DEFAULT NULL;
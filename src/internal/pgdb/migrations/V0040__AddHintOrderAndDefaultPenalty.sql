@@ -0,0 +1,3 @@
+ALTER TABLE questspace.task ADD COLUMN ordered_hints bool NOT NULL DEFAULT false;
+ALTER TABLE questspace.quest ADD COLUMN default_hint_penalty_percent integer DEFAULT NULL;
+ALTER TABLE questspace.quest ADD COLUMN default_hint_penalty_score integer DEFAULT NULL;
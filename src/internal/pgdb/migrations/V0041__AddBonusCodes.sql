@@ -0,0 +1,21 @@
+CREATE TABLE questspace.bonus_code (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    quest_id uuid NOT NULL REFERENCES questspace.quest (id) ON DELETE CASCADE,
+    code varchar NOT NULL,
+    value integer NOT NULL,
+    valid_from timestamp DEFAULT NULL,
+    valid_until timestamp DEFAULT NULL,
+    max_uses integer DEFAULT NULL,
+    uses integer NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX bonus_code_quest_code_idx ON questspace.bonus_code (quest_id, lower(code));
+
+CREATE TABLE questspace.bonus_code_use (
+    code_id uuid NOT NULL REFERENCES questspace.bonus_code (id) ON DELETE CASCADE,
+    team_id uuid NOT NULL REFERENCES questspace.team (id) ON DELETE CASCADE,
+    user_id uuid REFERENCES questspace.user (id) ON DELETE SET NULL,
+    time_created timestamp NOT NULL,
+
+    UNIQUE (code_id, team_id)
+);
@@ -0,0 +1,8 @@
+ALTER TABLE questspace.team_penalty ADD COLUMN reason varchar DEFAULT NULL;
+ALTER TABLE questspace.team_penalty
+    ADD COLUMN task_id uuid DEFAULT NULL REFERENCES questspace.task (id) ON DELETE SET NULL;
+ALTER TABLE questspace.team_penalty
+    ADD COLUMN author_id uuid DEFAULT NULL REFERENCES questspace.user (id) ON DELETE SET NULL;
+ALTER TABLE questspace.team_penalty ADD COLUMN time_revoked timestamp DEFAULT NULL;
+ALTER TABLE questspace.team_penalty
+    ADD COLUMN revoked_by uuid DEFAULT NULL REFERENCES questspace.user (id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE questspace.quest ADD COLUMN auto_finish boolean NOT NULL DEFAULT false;
+
+CREATE INDEX auto_finish_quest_idx ON questspace.quest USING btree (finish_time) WHERE auto_finish AND NOT finished;
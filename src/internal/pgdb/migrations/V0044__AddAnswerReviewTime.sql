@@ -0,0 +1,2 @@
+-- answers verified by quest creator are scored at review time
+ALTER TABLE questspace.answer_try ADD COLUMN review_time timestamp DEFAULT NULL;
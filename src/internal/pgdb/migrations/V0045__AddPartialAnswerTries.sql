@@ -0,0 +1,2 @@
+-- partially correct choice answers are scored, but do not solve the task
+ALTER TABLE questspace.answer_try ADD COLUMN partial boolean NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+-- entries set through legacy penalty endpoint are replaced by its next call, while other ledger entries stay intact
+ALTER TABLE questspace.team_penalty ADD COLUMN legacy boolean NOT NULL DEFAULT false;
+-- penalties recorded before the ledger are set by legacy endpoint only
+UPDATE questspace.team_penalty SET legacy = true WHERE author_id IS NULL AND group_id IS NULL AND reason IS NULL AND task_id IS NULL;
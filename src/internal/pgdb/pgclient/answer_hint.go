@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/yandex/perforator/library/go/core/xerrors"
@@ -120,7 +122,7 @@ WITH inserted_hint AS (
 }
 
 func (c *Client) GetAcceptedTasks(ctx context.Context, req *storage.GetAcceptedTasksRequest) (storage.AcceptedTasks, error) {
-	query := sq.Select("t.id", "at.answer", "at.score").
+	query := sq.Select("t.id", "at.answer", "at.score", "at.partial", "at.part_idx", "COALESCE(jsonb_array_length(t.parts), 0)").
 		From("questspace.answer_try at").
 		LeftJoin("questspace.task t ON at.task_id = t.id").
 		LeftJoin("questspace.task_group tg ON t.group_id = tg.id").
@@ -134,27 +136,63 @@ func (c *Client) GetAcceptedTasks(ctx context.Context, req *storage.GetAcceptedT
 	defer func() { _ = rows.Close() }()
 
 	acceptedTasks := make(storage.AcceptedTasks)
+	partCounts := make(map[storage.ID]int)
 	for rows.Next() {
-		var task storage.AcceptedTask
 		var id storage.ID
-		if err = rows.Scan(&id, &task.Text, &task.Score); err != nil {
+		var text string
+		var score, partCount int
+		var partial bool
+		var part *int
+		if err = rows.Scan(&id, &text, &score, &partial, &part, &partCount); err != nil {
 			return nil, xerrors.Errorf("scan row: %w", err)
 		}
+		if part == nil {
+			acceptedTasks[id] = storage.AcceptedTask{Text: text, Score: score, Partial: partial}
+			continue
+		}
+		// solved parts of multi-part task are accumulated into single accepted task
+		task := acceptedTasks[id]
+		if task.Parts == nil {
+			task.Parts = make(map[int]string)
+		}
+		task.Parts[*part] = text
+		task.Score += score
 		acceptedTasks[id] = task
+		partCounts[id] = partCount
 	}
 	if err = rows.Err(); err != nil {
 		return nil, xerrors.Errorf("iter rows: %w", err)
 	}
+	for id, partCount := range partCounts {
+		task := acceptedTasks[id]
+		texts := make([]string, 0, len(task.Parts))
+		for i := 0; i < partCount; i++ {
+			if text, ok := task.Parts[i]; ok {
+				texts = append(texts, text)
+			}
+		}
+		task.Text = strings.Join(texts, "; ")
+		task.Partial = len(task.Parts) < partCount
+		acceptedTasks[id] = task
+	}
 
 	return acceptedTasks, nil
 }
 
 func (c *Client) CreateAnswerTry(ctx context.Context, req *storage.CreateAnswerTryRequest) error {
 	query := `
-	INSERT INTO questspace.answer_try (team_id, user_id, task_id, answer, accepted, score, try_time)
-	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	INSERT INTO questspace.answer_try (team_id, user_id, task_id, answer, accepted, score, try_time, review_status, penalty, bonus, decay, file_key, file_type, part_idx, partial)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
+	var reviewStatus *storage.ReviewStatus
+	if req.ReviewStatus != "" {
+		reviewStatus = &req.ReviewStatus
+	}
+	var fileKey, fileType *string
+	if req.File != nil {
+		fileKey, fileType = &req.File.Key, &req.File.ContentType
+	}
 	if _, err := c.runner.ExecContext(
 		ctx, query,
 		req.TeamID,
@@ -164,14 +202,217 @@ func (c *Client) CreateAnswerTry(ctx context.Context, req *storage.CreateAnswerT
 		req.Accepted,
 		req.Score,
 		qtime.Now(),
+		reviewStatus,
+		req.Penalty,
+		req.Bonus,
+		req.Decay,
+		fileKey,
+		fileType,
+		req.Part,
+		req.Partial,
 	); err != nil {
 		return xerrors.Errorf("exec query: %w", err)
 	}
 	return nil
 }
 
+// LockAnswerTries takes transaction level advisory lock, which is released on commit or rollback
+func (c *Client) LockAnswerTries(ctx context.Context, req *storage.LockAnswerTriesRequest) error {
+	query := `SELECT pg_advisory_xact_lock(hashtext($1))`
+
+	key := "answer_try:" + req.TaskID.String() + ":" + req.TeamID.String()
+	if _, err := c.runner.ExecContext(ctx, query, key); err != nil {
+		return xerrors.Errorf("exec query: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) GetAnswerTryStats(ctx context.Context, req *storage.GetAnswerTryStatsRequest) (*storage.AnswerTryStats, error) {
+	query := sq.Select("COUNT(*)", "MAX(at.try_time)").
+		From("questspace.answer_try at").
+		Where(sq.Eq{"at.team_id": req.TeamID, "at.task_id": req.TaskID, "at.accepted": false}).
+		Where(sq.Expr("at.review_status IS DISTINCT FROM ?", storage.ReviewStatusPending)).
+		PlaceholderFormat(sq.Dollar)
+
+	var stats storage.AnswerTryStats
+	if err := query.RunWith(c.runner).QueryRowContext(ctx).Scan(&stats.WrongTries, &stats.LastTryTime); err != nil {
+		return nil, xerrors.Errorf("scan row: %w", err)
+	}
+	return &stats, nil
+}
+
+func (c *Client) GetTaskSolveCount(ctx context.Context, req *storage.GetTaskSolveCountRequest) (int, error) {
+	query := sq.Select("COUNT(DISTINCT at.team_id)").
+		From("questspace.answer_try at").
+		Where(sq.Eq{"at.task_id": req.TaskID, "at.accepted": true, "at.partial": false}).
+		PlaceholderFormat(sq.Dollar)
+
+	var count int
+	if err := query.RunWith(c.runner).QueryRowContext(ctx).Scan(&count); err != nil {
+		return 0, xerrors.Errorf("scan row: %w", err)
+	}
+	return count, nil
+}
+
+func buildReviewQuery(questID storage.ID) sq.SelectBuilder {
+	return sq.Select(
+		"at.id",
+		"at.try_time",
+		"tg.id",
+		"tg.name",
+		"t.id",
+		"t.name",
+		"t.reward",
+		"tm.id",
+		"tm.name",
+		"u.id",
+		"u.username",
+		"at.accepted",
+		"at.answer",
+		"at.score",
+		"at.review_status",
+		"at.file_key",
+		"at.file_type",
+	).
+		From("questspace.answer_try at").
+		LeftJoin("questspace.team tm ON at.team_id = tm.id").
+		LeftJoin("questspace.task t ON at.task_id = t.id").
+		LeftJoin("questspace.task_group tg ON t.group_id = tg.id").
+		LeftJoin("questspace.user u ON at.user_id = u.id").
+		Where(sq.Eq{"tg.quest_id": questID}).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func scanAnswerTry(s sq.RowScanner) (*storage.AnswerTry, error) {
+	var userName, userID, reviewStatus, fileKey, fileType sql.NullString
+	var score sql.NullInt64
+	at := storage.AnswerTry{
+		TaskGroup: &storage.TaskGroup{},
+		Task:      &storage.Task{},
+		Team:      &storage.Team{},
+	}
+	if err := s.Scan(
+		&at.ID,
+		&at.AnswerTime,
+		&at.TaskGroup.ID,
+		&at.TaskGroup.Name,
+		&at.Task.ID,
+		&at.Task.Name,
+		&at.Task.Reward,
+		&at.Team.ID,
+		&at.Team.Name,
+		&userID,
+		&userName,
+		&at.Accepted,
+		&at.Answer,
+		&score,
+		&reviewStatus,
+		&fileKey,
+		&fileType,
+	); err != nil {
+		return nil, err
+	}
+	at.File = answerFile(fileKey, fileType)
+	if userName.Valid && userID.Valid {
+		at.User = &storage.User{
+			ID:       storage.ID(userID.String),
+			Username: userName.String,
+		}
+	}
+	at.Score = int(score.Int64)
+	at.ReviewStatus = storage.ReviewStatus(reviewStatus.String)
+	return &at, nil
+}
+
+func (c *Client) GetAnswerTry(ctx context.Context, req *storage.GetAnswerTryRequest) (*storage.AnswerTry, error) {
+	query := buildReviewQuery(req.QuestID).Where(sq.Eq{"at.id": req.ID})
+	row := query.RunWith(c.runner).QueryRowContext(ctx)
+
+	at, err := scanAnswerTry(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, xerrors.Errorf("scan row: %w", err)
+	}
+	return at, nil
+}
+
+func (c *Client) GetReviewAnswerTries(ctx context.Context, req *storage.GetReviewAnswerTriesRequest) ([]storage.AnswerTry, error) {
+	query := buildReviewQuery(req.QuestID).OrderBy("at.try_time ASC")
+	if req.OnlyPending {
+		query = query.Where(sq.Eq{"at.review_status": storage.ReviewStatusPending})
+	} else {
+		query = query.Where(sq.NotEq{"at.review_status": nil})
+	}
+	if req.TeamID != "" {
+		query = query.Where(sq.Eq{"at.team_id": req.TeamID})
+	}
+
+	rows, err := query.RunWith(c.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("query rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tries []storage.AnswerTry
+	for rows.Next() {
+		at, err := scanAnswerTry(rows)
+		if err != nil {
+			return nil, xerrors.Errorf("scan row: %w", err)
+		}
+		tries = append(tries, *at)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iter rows: %w", err)
+	}
+
+	return tries, nil
+}
+
+func (c *Client) ReviewAnswerTry(ctx context.Context, req *storage.ReviewAnswerTryRequest) error {
+	query := sq.Update("questspace.answer_try").
+		Set("review_status", req.ReviewStatus).
+		Set("accepted", req.ReviewStatus == storage.ReviewStatusAccepted).
+		Set("score", req.Score).
+		Set("bonus", req.Bonus).
+		Set("decay", req.Decay).
+		Set("review_time", req.ReviewTime).
+		Where(sq.Eq{"id": req.ID, "review_status": storage.ReviewStatusPending}).
+		PlaceholderFormat(sq.Dollar)
+
+	res, err := query.RunWith(c.runner).ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("exec query: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return xerrors.Errorf("get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (c *Client) RejectPendingAnswerTries(ctx context.Context, req *storage.RejectPendingAnswerTriesRequest) error {
+	query := sq.Update("questspace.answer_try").
+		Set("review_status", storage.ReviewStatusRejected).
+		Set("review_time", req.ReviewTime).
+		Where(sq.Eq{"team_id": req.TeamID, "task_id": req.TaskID, "review_status": storage.ReviewStatusPending}).
+		PlaceholderFormat(sq.Dollar)
+
+	if _, err := query.RunWith(c.runner).ExecContext(ctx); err != nil {
+		return xerrors.Errorf("exec query: %w", err)
+	}
+	return nil
+}
+
+// scoreTimeExpr is the time when answer try is scored. Answers verified by quest creator are scored at review time
+const scoreTimeExpr = "COALESCE(at.review_time, at.try_time)"
+
 func (c *Client) GetScoreResults(ctx context.Context, req *storage.GetResultsRequest) (storage.ScoreResults, error) {
-	query := sq.Select("tm.id", "tm.name", "tg.id", "tg.name", "t.id", "t.name", "at.score", "at.try_time").
+	query := sq.Select("tm.id", "tm.name", "tg.id", "tg.name", "t.id", "t.name", "at.score", scoreTimeExpr, "at.bonus", "at.decay", "at.part_idx").
 		From("questspace.team tm").
 		LeftJoin("questspace.answer_try at ON at.team_id = tm.id").
 		LeftJoin("questspace.task t ON at.task_id = t.id").
@@ -184,6 +425,9 @@ func (c *Client) GetScoreResults(ctx context.Context, req *storage.GetResultsReq
 	if len(req.TeamIDs) > 0 {
 		query = query.Where(sq.Eq{"tg.team_id": req.TeamIDs})
 	}
+	if req.Before != nil {
+		query = query.Where(sq.LtOrEq{scoreTimeExpr: *req.Before})
+	}
 
 	rows, err := query.RunWith(c.runner).QueryContext(ctx)
 	if err != nil {
@@ -194,13 +438,27 @@ func (c *Client) GetScoreResults(ctx context.Context, req *storage.GetResultsReq
 	scoreRes := make(storage.ScoreResults)
 	for rows.Next() {
 		var res storage.SingleTaskResult
-		if err = rows.Scan(&res.TeamID, &res.TeamName, &res.GroupID, &res.GroupName, &res.TaskID, &res.TaskName, &res.Score, &res.ScoreTime); err != nil {
+		var part *int
+		if err = rows.Scan(&res.TeamID, &res.TeamName, &res.GroupID, &res.GroupName, &res.TaskID, &res.TaskName, &res.Score, &res.ScoreTime, &res.Bonus, &res.Decay, &part); err != nil {
 			return nil, xerrors.Errorf("scan row: %w", err)
 		}
 		taskRes := scoreRes[res.TeamID]
 		if taskRes == nil {
 			taskRes = make(map[storage.ID]storage.SingleTaskResult)
 		}
+		if part != nil {
+			// scores of solved parts of multi-part task are summed up, while the latest answer time is kept
+			res.SolvedParts = 1
+			if prev, ok := taskRes[res.TaskID]; ok {
+				res.Score += prev.Score
+				res.Bonus += prev.Bonus
+				res.Decay += prev.Decay
+				res.SolvedParts += prev.SolvedParts
+				if res.ScoreTime == nil || prev.ScoreTime != nil && prev.ScoreTime.After(*res.ScoreTime) {
+					res.ScoreTime = prev.ScoreTime
+				}
+			}
+		}
 		taskRes[res.TaskID] = res
 		scoreRes[res.TeamID] = taskRes
 	}
@@ -211,32 +469,149 @@ func (c *Client) GetScoreResults(ctx context.Context, req *storage.GetResultsReq
 	return scoreRes, nil
 }
 
+// scoreHistoryQuery collects all changes of team scores: accepted answers, wrong answer penalties, team penalties
+// with their revocations and bonus codes, sums them within buckets and accumulates the sums per team.
+// Answers verified by quest creator change score at review time.
+const scoreHistoryQuery = `
+WITH changes AS (
+	SELECT at.team_id, COALESCE(at.review_time, at.try_time) AS change_time, CASE WHEN at.accepted THEN at.score ELSE 0 END - at.penalty AS delta
+	FROM questspace.answer_try at
+	JOIN questspace.team tm ON tm.id = at.team_id
+	WHERE tm.quest_id = $1 AND (at.accepted OR at.penalty > 0)
+	UNION ALL
+	SELECT p.team_id, p.time_created, -p.value
+	FROM questspace.team_penalty p
+	JOIN questspace.team tm ON tm.id = p.team_id
+	WHERE tm.quest_id = $1
+	UNION ALL
+	SELECT p.team_id, p.time_revoked, p.value
+	FROM questspace.team_penalty p
+	JOIN questspace.team tm ON tm.id = p.team_id
+	WHERE tm.quest_id = $1 AND p.time_revoked IS NOT NULL
+	UNION ALL
+	SELECT u.team_id, u.time_created, bc.value
+	FROM questspace.bonus_code_use u
+	JOIN questspace.bonus_code bc ON bc.id = u.code_id
+	WHERE bc.quest_id = $1
+), buckets AS (
+	SELECT team_id, %s AS bucket_time, SUM(delta) AS delta
+	FROM changes
+	WHERE $2::timestamp IS NULL OR change_time <= $2
+	GROUP BY team_id, bucket_time
+)
+SELECT team_id, bucket_time, SUM(delta) OVER (PARTITION BY team_id ORDER BY bucket_time)
+FROM buckets
+ORDER BY team_id, bucket_time
+`
+
+// bucketEndExpr rounds change time up to the end of its bucket, so that point shows score at the moment
+const bucketEndExpr = `date_bin(make_interval(secs => $3), change_time, timestamp '2000-01-01') + make_interval(secs => $3)`
+
+func (c *Client) GetScoreHistory(ctx context.Context, req *storage.GetScoreHistoryRequest) (storage.ScoreHistory, error) {
+	query, args := fmt.Sprintf(scoreHistoryQuery, "change_time"), []any{req.QuestID, req.Before}
+	if req.Bucket > 0 {
+		query, args = fmt.Sprintf(scoreHistoryQuery, bucketEndExpr), append(args, req.Bucket.Seconds())
+	}
+
+	rows, err := c.runner.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, xerrors.Errorf("query rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	res := make(storage.ScoreHistory)
+	for rows.Next() {
+		var (
+			teamID storage.ID
+			point  storage.ScorePoint
+		)
+		if err = rows.Scan(&teamID, &point.Time, &point.Score); err != nil {
+			return nil, xerrors.Errorf("scan row: %w", err)
+		}
+		res[teamID] = append(res[teamID], point)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iter rows: %w", err)
+	}
+	return res, nil
+}
+
 const countOnly = "COUNT(*)"
 
+// logEntriesQuery selects answer tries of the quest together with bonus codes entered by teams,
+// so that log filters and paging are applied to both of them
+func logEntriesQuery(questID storage.ID) sq.SelectBuilder {
+	bonusCodes := sq.Select(
+		"bu.time_created",
+		"NULL",
+		"NULL",
+		"NULL",
+		"NULL",
+		"tm.id",
+		"tm.name",
+		"u.id",
+		"u.username",
+		"true",
+		"bc.code",
+		"bc.value",
+		"NULL",
+		"NULL",
+		"true",
+	).
+		From("questspace.bonus_code_use bu").
+		Join("questspace.bonus_code bc ON bc.id = bu.code_id").
+		LeftJoin("questspace.team tm ON bu.team_id = tm.id").
+		LeftJoin("questspace.user u ON bu.user_id = u.id").
+		Where(sq.Eq{"bc.quest_id": questID})
+	bonusCodesQuery, bonusCodesArgs := bonusCodes.MustSql()
+
+	return sq.Select(
+		"at.try_time AS answer_time",
+		"tg.id AS group_id",
+		"tg.name AS group_name",
+		"t.id AS task_id",
+		"t.name AS task_name",
+		"tm.id AS team_id",
+		"tm.name AS team_name",
+		"u.id AS user_id",
+		"u.username AS user_name",
+		"at.accepted AS accepted",
+		"at.answer AS answer",
+		"at.score AS score",
+		"at.file_key AS file_key",
+		"at.file_type AS file_type",
+		"false AS bonus_code",
+	).
+		From("questspace.answer_try at").
+		LeftJoin("questspace.team tm ON at.team_id = tm.id").
+		LeftJoin("questspace.task t ON at.task_id = t.id").
+		LeftJoin("questspace.task_group tg ON t.group_id = tg.id").
+		LeftJoin("questspace.user u ON at.user_id = u.id").
+		Where(sq.Eq{"tg.quest_id": questID}).
+		Suffix("UNION ALL "+bonusCodesQuery, bonusCodesArgs...)
+}
+
 func buildLogQuery(req *storage.GetAnswerTriesRequest, opts *storage.TaskRequestLogFilterOptions, fields ...string) sq.SelectBuilder {
-	whereEq := sq.Eq{"tg.quest_id": req.QuestID}
+	// bonus codes have no task group and task, so they are filtered out by these filters
+	whereEq := sq.Eq{}
 	if len(opts.GroupID) > 0 {
-		whereEq["tg.id"] = opts.GroupID
+		whereEq["l.group_id"] = opts.GroupID
 	}
 	if len(opts.TaskID) > 0 {
-		whereEq["t.id"] = opts.TaskID
+		whereEq["l.task_id"] = opts.TaskID
 	}
 	if len(opts.TeamID) > 0 {
-		whereEq["tm.id"] = opts.TeamID
+		whereEq["l.team_id"] = opts.TeamID
 	}
 	if len(opts.UserID) > 0 {
-		whereEq["u.id"] = opts.UserID
+		whereEq["l.user_id"] = opts.UserID
 	}
 	if opts.OnlyAccepted {
-		whereEq["at.accepted"] = true
+		whereEq["l.accepted"] = true
 	}
 
 	query := sq.Select(fields...).
-		From("questspace.answer_try at").
-		LeftJoin("questspace.team tm ON at.team_id = tm.id").
-		LeftJoin("questspace.task t ON at.task_id = t.id").
-		LeftJoin("questspace.task_group tg ON t.group_id = tg.id").
-		LeftJoin("questspace.user u ON at.user_id = u.id").
+		FromSelect(logEntriesQuery(req.QuestID), "l").
 		Where(whereEq).
 		PlaceholderFormat(sq.Dollar)
 
@@ -246,14 +621,78 @@ func buildLogQuery(req *storage.GetAnswerTriesRequest, opts *storage.TaskRequest
 	}
 
 	if needsSorting && opts.DateDesc {
-		query = query.OrderBy("at.try_time DESC")
+		query = query.OrderBy("l.answer_time DESC")
 	} else if needsSorting {
-		query = query.OrderBy("at.try_time ASC")
+		query = query.OrderBy("l.answer_time ASC")
 	}
 
 	return query
 }
 
+var answerLogFields = []string{
+	"l.answer_time",
+	"l.group_id",
+	"l.group_name",
+	"l.task_id",
+	"l.task_name",
+	"l.team_id",
+	"l.team_name",
+	"l.user_id",
+	"l.user_name",
+	"l.accepted",
+	"l.answer",
+	"l.score",
+	"l.file_key",
+	"l.file_type",
+	"l.bonus_code",
+}
+
+func scanAnswerLog(rows *sql.Rows) (*storage.AnswerLog, error) {
+	var userName, userID, fileKey, fileType sql.NullString
+	var groupID, groupName, taskID, taskName sql.NullString
+	al := storage.AnswerLog{
+		TaskGroup: &storage.TaskGroup{},
+		Task:      &storage.Task{},
+		Team:      &storage.Team{},
+	}
+	if err := rows.Scan(
+		&al.AnswerTime,
+		&groupID,
+		&groupName,
+		&taskID,
+		&taskName,
+		&al.Team.ID,
+		&al.Team.Name,
+		&userID,
+		&userName,
+		&al.Accepted,
+		&al.Answer,
+		&al.Score,
+		&fileKey,
+		&fileType,
+		&al.BonusCode,
+	); err != nil {
+		return nil, err
+	}
+	al.TaskGroup.ID, al.TaskGroup.Name = storage.ID(groupID.String), groupName.String
+	al.Task.ID, al.Task.Name = storage.ID(taskID.String), taskName.String
+	al.File = answerFile(fileKey, fileType)
+	if userName.Valid && userID.Valid {
+		al.User = &storage.User{
+			ID:       storage.ID(userID.String),
+			Username: userName.String,
+		}
+	}
+	return &al, nil
+}
+
+func answerFile(key, contentType sql.NullString) *storage.AnswerFile {
+	if !key.Valid {
+		return nil
+	}
+	return &storage.AnswerFile{Key: key.String, ContentType: contentType.String}
+}
+
 func (c *Client) GetAnswerTries(ctx context.Context, req *storage.GetAnswerTriesRequest, opts ...storage.FilteringOption) (*storage.AnswerLogRecords, error) {
 	options := storage.NewDefaultLogOpts()
 	for _, opt := range opts {
@@ -271,24 +710,11 @@ func (c *Client) GetAnswerTries(ctx context.Context, req *storage.GetAnswerTries
 		return nil, xerrors.Errorf("count all answers: %w", err)
 	}
 
-	query := buildLogQuery(req, &options,
-		"at.try_time",
-		"tg.id",
-		"tg.name",
-		"t.id",
-		"t.name",
-		"tm.id",
-		"tm.name",
-		"u.id",
-		"u.username",
-		"at.accepted",
-		"at.answer",
-		"at.score",
-	)
+	query := buildLogQuery(req, &options, answerLogFields...)
 	if options.PageToken != nil && !options.DateDesc {
-		query = query.Where("extract(epoch from at.try_time)*1000 > ?", *options.PageToken)
+		query = query.Where("extract(epoch from l.answer_time)*1000 > ?", *options.PageToken)
 	} else if options.PageToken != nil && options.DateDesc {
-		query = query.Where("extract(epoch from at.try_time)*1000 < ?", *options.PageToken)
+		query = query.Where("extract(epoch from l.answer_time)*1000 < ?", *options.PageToken)
 	} else if options.PageNumber != nil {
 		query = query.Offset(uint64(options.PageSize * *options.PageNumber))
 	}
@@ -302,36 +728,11 @@ func (c *Client) GetAnswerTries(ctx context.Context, req *storage.GetAnswerTries
 
 	answerLogs := make([]storage.AnswerLog, 0, options.PageSize)
 	for rows.Next() {
-		var userName, userID sql.NullString
-		al := storage.AnswerLog{
-			TaskGroup: &storage.TaskGroup{},
-			Task:      &storage.Task{},
-			Team:      &storage.Team{},
-		}
-		if err = rows.Scan(
-			&al.AnswerTime,
-			&al.TaskGroup.ID,
-			&al.TaskGroup.Name,
-			&al.Task.ID,
-			&al.Task.Name,
-			&al.Team.ID,
-			&al.Team.Name,
-			&userID,
-			&userName,
-			&al.Accepted,
-			&al.Answer,
-			&al.Score,
-		); err != nil {
+		al, err := scanAnswerLog(rows)
+		if err != nil {
 			return nil, xerrors.Errorf("scan row: %w", err)
 		}
-		if userName.Valid && userID.Valid {
-			al.User = &storage.User{
-				ID:       storage.ID(userID.String),
-				Username: userName.String,
-			}
-		}
-
-		answerLogs = append(answerLogs, al)
+		answerLogs = append(answerLogs, *al)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, xerrors.Errorf("iter rows: %w", err)
@@ -356,8 +757,37 @@ func (c *Client) GetAnswerTries(ctx context.Context, req *storage.GetAnswerTries
 	return res, nil
 }
 
+// IterAnswerTries calls fn for every answer try matching filters. Rows are read from connection one by one,
+// so memory usage does not depend on size of the log. Paging options are ignored.
+func (c *Client) IterAnswerTries(ctx context.Context, req *storage.GetAnswerTriesRequest, fn func(*storage.AnswerLog) error, opts ...storage.FilteringOption) error {
+	options := storage.NewDefaultLogOpts()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	rows, err := buildLogQuery(req, &options, answerLogFields...).RunWith(c.runner).QueryContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("query answers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		al, err := scanAnswerLog(rows)
+		if err != nil {
+			return xerrors.Errorf("scan row: %w", err)
+		}
+		if err = fn(al); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return xerrors.Errorf("iter rows: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) GetPenalties(ctx context.Context, req *storage.GetPenaltiesRequest) (storage.TeamPenalties, error) {
-	query := sq.Select("p.team_id", "p.value").
+	query := sq.Select("p.team_id", "p.value", "p.group_id").
 		From("questspace.team_penalty p").
 		PlaceholderFormat(sq.Dollar)
 	if len(req.TeamIDs) > 0 {
@@ -366,6 +796,13 @@ func (c *Client) GetPenalties(ctx context.Context, req *storage.GetPenaltiesRequ
 	if req.QuestID != "" {
 		query = query.LeftJoin("questspace.team t ON t.id = p.team_id").Where(sq.Eq{"t.quest_id": req.QuestID})
 	}
+	if req.Before != nil {
+		// penalty revoked after the moment is still seen at that moment
+		query = query.Where(sq.LtOrEq{"p.time_created": *req.Before}).
+			Where(sq.Or{sq.Eq{"p.time_revoked": nil}, sq.Gt{"p.time_revoked": *req.Before}})
+	} else {
+		query = query.Where(sq.Eq{"p.time_revoked": nil})
+	}
 
 	rows, err := query.RunWith(c.runner).QueryContext(ctx)
 	if err != nil {
@@ -375,10 +812,14 @@ func (c *Client) GetPenalties(ctx context.Context, req *storage.GetPenaltiesRequ
 
 	res := make(storage.TeamPenalties)
 	for rows.Next() {
-		var p storage.Penalty
-		if err = rows.Scan(&p.TeamID, &p.Value); err != nil {
+		p := storage.Penalty{Source: storage.PenaltySourceLedger}
+		var groupID *storage.ID
+		if err = rows.Scan(&p.TeamID, &p.Value, &groupID); err != nil {
 			return nil, xerrors.Errorf("scan row: %w", err)
 		}
+		if groupID != nil {
+			p.TaskGroupID = *groupID
+		}
 		vals := res[p.TeamID]
 		vals = append(vals, p)
 		res[p.TeamID] = vals
@@ -387,20 +828,72 @@ func (c *Client) GetPenalties(ctx context.Context, req *storage.GetPenaltiesRequ
 		return nil, xerrors.Errorf("iter rows: %w", err)
 	}
 
+	if err = c.getWrongAnswerPenalties(ctx, req, res); err != nil {
+		return nil, xerrors.Errorf("get wrong answer penalties: %w", err)
+	}
 	return res, nil
 }
 
-func (c *Client) CreatePenalty(ctx context.Context, req *storage.CreatePenaltyRequest) error {
-	var err error
-	// TODO(svayp11): Refactor this abomination
-	deleteQuery := `DELETE FROM questspace.team_penalty WHERE team_id = $1`
-	if _, err = c.runner.ExecContext(ctx, deleteQuery, req.TeamID); err != nil {
-		return xerrors.Errorf("delete all previous penalties: %w", err)
+func (c *Client) getWrongAnswerPenalties(ctx context.Context, req *storage.GetPenaltiesRequest, res storage.TeamPenalties) error {
+	query := sq.Select("at.team_id", "at.task_id", "SUM(at.penalty)").
+		From("questspace.answer_try at").
+		Where(sq.Gt{"at.penalty": 0}).
+		GroupBy("at.team_id", "at.task_id").
+		PlaceholderFormat(sq.Dollar)
+	if len(req.TeamIDs) > 0 {
+		query = query.Where(sq.Eq{"at.team_id": req.TeamIDs})
+	}
+	if req.QuestID != "" {
+		query = query.LeftJoin("questspace.team t ON t.id = at.team_id").Where(sq.Eq{"t.quest_id": req.QuestID})
+	}
+	if req.Before != nil {
+		query = query.Where(sq.LtOrEq{"at.try_time": *req.Before})
 	}
 
-	insertQuery := `INSERT INTO questspace.team_penalty (team_id, value) VALUES ($1, $2)`
-	if _, err = c.runner.ExecContext(ctx, insertQuery, req.TeamID, req.Penalty); err != nil {
-		return xerrors.Errorf("add new penalty: %w", err)
+	rows, err := query.RunWith(c.runner).QueryContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("query rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		p := storage.Penalty{Source: storage.PenaltySourceWrongAnswer}
+		if err = rows.Scan(&p.TeamID, &p.TaskID, &p.Value); err != nil {
+			return xerrors.Errorf("scan row: %w", err)
+		}
+		res[p.TeamID] = append(res[p.TeamID], p)
+	}
+	if err = rows.Err(); err != nil {
+		return xerrors.Errorf("iter rows: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) CreatePenalty(ctx context.Context, req *storage.CreatePenaltyRequest) error {
+	columns := []string{"team_id", "value", "time_created"}
+	values := []any{req.TeamID, req.Penalty, qtime.Now()}
+	if len(req.TaskGroupID) > 0 {
+		columns, values = append(columns, "group_id"), append(values, req.TaskGroupID)
+	}
+	if len(req.TaskID) > 0 {
+		columns, values = append(columns, "task_id"), append(values, req.TaskID)
+	}
+	if len(req.Reason) > 0 {
+		columns, values = append(columns, "reason"), append(values, req.Reason)
+	}
+	if len(req.AuthorID) > 0 {
+		columns, values = append(columns, "author_id"), append(values, req.AuthorID)
+	}
+	if req.Legacy {
+		columns, values = append(columns, "legacy"), append(values, true)
+	}
+	if _, err := sq.Insert("questspace.team_penalty").
+		Columns(columns...).
+		Values(values...).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(c.runner).
+		ExecContext(ctx); err != nil {
+		return xerrors.Errorf("add penalty: %w", err)
 	}
 	return nil
 }
@@ -3,11 +3,14 @@ package pgclient
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/spkg/ptr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"questspace/internal/pgdb/pgtest"
+	"questspace/internal/qtime"
 	"questspace/pkg/storage"
 )
 
@@ -173,6 +176,107 @@ func TestAnswerHintStorage_GetScoreResults(t *testing.T) {
 	assert.Equal(t, tryReq.Score, results[team.ID][task.ID].Score)
 	assert.Nil(t, results[team2.ID])
 }
+
+func TestAnswerHintStorage_GetScoreHistory(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+	})
+	require.NoError(t, err)
+
+	taskReq1 := taskReq
+	taskReq1.GroupID = tg.ID
+	task, err := client.CreateTask(ctx, &taskReq1)
+	require.NoError(t, err)
+
+	team, user := createTestTeam(t, ctx, client, quest, "svayp22", "team1")
+	start := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	now := start
+	qtime.SetNowFunc(t, func() time.Time {
+		return now
+	})
+
+	now = start.Add(30 * time.Second)
+	require.NoError(t, client.CreateAnswerTry(ctx, &storage.CreateAnswerTryRequest{
+		Text: task.CorrectAnswers[0], Accepted: true, Score: 50, TaskID: task.ID, TeamID: team.ID, UserID: user.ID,
+	}))
+	now = start.Add(70 * time.Second)
+	require.NoError(t, client.CreateAnswerTry(ctx, &storage.CreateAnswerTryRequest{
+		Text: "wrong", Penalty: 5, TaskID: task.ID, TeamID: team.ID, UserID: user.ID,
+	}))
+	now = start.Add(90 * time.Second)
+	taskReq2 := taskReq
+	taskReq2.GroupID = tg.ID
+	taskReq2.Verification = storage.VerificationManual
+	manualTask, err := client.CreateTask(ctx, &taskReq2)
+	require.NoError(t, err)
+	require.NoError(t, client.CreateAnswerTry(ctx, &storage.CreateAnswerTryRequest{
+		Text: "manual", TaskID: manualTask.ID, TeamID: team.ID, UserID: user.ID, ReviewStatus: storage.ReviewStatusPending,
+	}))
+	now = start.Add(3 * time.Minute)
+	require.NoError(t, client.CreatePenalty(ctx, &storage.CreatePenaltyRequest{TeamID: team.ID, Penalty: 20, Legacy: true}))
+	pending, err := client.GetReviewAnswerTries(ctx, &storage.GetReviewAnswerTriesRequest{QuestID: quest.ID, OnlyPending: true})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.NoError(t, client.ReviewAnswerTry(ctx, &storage.ReviewAnswerTryRequest{
+		ID:           pending[0].ID,
+		ReviewStatus: storage.ReviewStatusAccepted,
+		Score:        10,
+		ReviewTime:   start.Add(4 * time.Minute),
+	}))
+	now = start.Add(6 * time.Minute)
+	adjustments, err := client.GetAdjustments(ctx, &storage.GetAdjustmentsRequest{QuestID: quest.ID})
+	require.NoError(t, err)
+	require.Len(t, adjustments, 1)
+	assert.True(t, adjustments[0].Legacy)
+	require.NoError(t, client.RevokeAdjustment(ctx, &storage.RevokeAdjustmentRequest{ID: adjustments[0].ID, QuestID: quest.ID, UserID: user.ID}))
+
+	history, err := client.GetScoreHistory(ctx, &storage.GetScoreHistoryRequest{QuestID: quest.ID})
+	require.NoError(t, err)
+	assert.Equal(t, storage.ScoreHistory{team.ID: {
+		{Time: start.Add(30 * time.Second), Score: 50},
+		{Time: start.Add(70 * time.Second), Score: 45},
+		{Time: start.Add(3 * time.Minute), Score: 25},
+		{Time: start.Add(4 * time.Minute), Score: 35},
+		{Time: start.Add(6 * time.Minute), Score: 55},
+	}}, history)
+
+	// results as of the moment before review and revocation
+	before := ptr.Time(start.Add(3 * time.Minute))
+	results, err := client.GetScoreResults(ctx, &storage.GetResultsRequest{QuestID: quest.ID, Before: before})
+	require.NoError(t, err)
+	assert.Contains(t, results[team.ID], task.ID)
+	assert.NotContains(t, results[team.ID], manualTask.ID)
+	penalties, err := client.GetPenalties(ctx, &storage.GetPenaltiesRequest{QuestID: quest.ID, Before: before})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []storage.Penalty{
+		{TeamID: team.ID, Value: 20, Source: storage.PenaltySourceLedger},
+		{TeamID: team.ID, TaskID: task.ID, Value: 5, Source: storage.PenaltySourceWrongAnswer},
+	}, penalties[team.ID])
+	penalties, err = client.GetPenalties(ctx, &storage.GetPenaltiesRequest{QuestID: quest.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []storage.Penalty{{TeamID: team.ID, TaskID: task.ID, Value: 5, Source: storage.PenaltySourceWrongAnswer}}, penalties[team.ID])
+
+	history, err = client.GetScoreHistory(ctx, &storage.GetScoreHistoryRequest{QuestID: quest.ID, Bucket: time.Minute, Before: ptr.Time(start.Add(2 * time.Minute))})
+	require.NoError(t, err)
+	assert.Equal(t, storage.ScoreHistory{team.ID: {
+		{Time: start.Add(time.Minute), Score: 50},
+		{Time: start.Add(2 * time.Minute), Score: 45},
+	}}, history)
+
+	history, err = client.GetScoreHistory(ctx, &storage.GetScoreHistoryRequest{QuestID: quest.ID, Bucket: 5 * time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, storage.ScoreHistory{team.ID: {
+		{Time: start.Add(5 * time.Minute), Score: 35},
+		{Time: start.Add(10 * time.Minute), Score: 55},
+	}}, history)
+}
+
 func TestAnswerHintStorage_Penalties(t *testing.T) {
 	ctx := context.Background()
 	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
@@ -205,3 +309,240 @@ func TestAnswerHintStorage_Penalties(t *testing.T) {
 	assert.Equal(t, penaltyReq.Penalty, results[team.ID][0].Value)
 	assert.Nil(t, results[team2.ID])
 }
+
+func TestAnswerHintStorage_ReviewAnswerTry(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+	})
+	require.NoError(t, err)
+
+	taskReq1 := taskReq
+	taskReq1.GroupID = tg.ID
+	taskReq1.Verification = storage.VerificationManual
+	task, err := client.CreateTask(ctx, &taskReq1)
+	require.NoError(t, err)
+
+	team, user := createTestTeam(t, ctx, client, quest, "svayp22", "team1")
+	tryReq := storage.CreateAnswerTryRequest{
+		Text:         "some answer",
+		TaskID:       task.ID,
+		TeamID:       team.ID,
+		UserID:       user.ID,
+		ReviewStatus: storage.ReviewStatusPending,
+	}
+	require.NoError(t, client.CreateAnswerTry(ctx, &tryReq))
+
+	pending, err := client.GetReviewAnswerTries(ctx, &storage.GetReviewAnswerTriesRequest{QuestID: quest.ID, OnlyPending: true})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, tryReq.Text, pending[0].Answer)
+	assert.Equal(t, team.ID, pending[0].Team.ID)
+	assert.Equal(t, task.ID, pending[0].Task.ID)
+	assert.Equal(t, storage.ReviewStatusPending, pending[0].ReviewStatus)
+
+	reviewReq := storage.ReviewAnswerTryRequest{
+		ID:           pending[0].ID,
+		ReviewStatus: storage.ReviewStatusAccepted,
+		Score:        task.Reward,
+	}
+	require.NoError(t, client.ReviewAnswerTry(ctx, &reviewReq))
+	require.ErrorIs(t, client.ReviewAnswerTry(ctx, &reviewReq), storage.ErrNotFound)
+
+	try, err := client.GetAnswerTry(ctx, &storage.GetAnswerTryRequest{ID: reviewReq.ID, QuestID: quest.ID})
+	require.NoError(t, err)
+	assert.True(t, try.Accepted)
+	assert.Equal(t, storage.ReviewStatusAccepted, try.ReviewStatus)
+
+	pending, err = client.GetReviewAnswerTries(ctx, &storage.GetReviewAnswerTriesRequest{QuestID: quest.ID, OnlyPending: true})
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	tasks, err := client.GetAcceptedTasks(ctx, &storage.GetAcceptedTasksRequest{QuestID: quest.ID, TeamID: team.ID})
+	require.NoError(t, err)
+	assert.Equal(t, task.Reward, tasks[task.ID].Score)
+}
+
+func TestAnswerHintStorage_RejectPendingAnswerTries(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+	})
+	require.NoError(t, err)
+
+	taskReq1 := taskReq
+	taskReq1.GroupID = tg.ID
+	taskReq1.Verification = storage.VerificationManual
+	task, err := client.CreateTask(ctx, &taskReq1)
+	require.NoError(t, err)
+
+	team, user := createTestTeam(t, ctx, client, quest, "svayp22", "team1")
+	otherTeam, otherUser := createTestTeam(t, ctx, client, quest, "svayp33", "team2")
+	for _, tryReq := range []storage.CreateAnswerTryRequest{
+		{Text: "first", TaskID: task.ID, TeamID: team.ID, UserID: user.ID, ReviewStatus: storage.ReviewStatusPending},
+		{Text: "second", TaskID: task.ID, TeamID: team.ID, UserID: user.ID, ReviewStatus: storage.ReviewStatusPending},
+		{Text: "other", TaskID: task.ID, TeamID: otherTeam.ID, UserID: otherUser.ID, ReviewStatus: storage.ReviewStatusPending},
+	} {
+		require.NoError(t, client.CreateAnswerTry(ctx, &tryReq))
+	}
+
+	require.NoError(t, client.RejectPendingAnswerTries(ctx, &storage.RejectPendingAnswerTriesRequest{
+		TeamID:     team.ID,
+		TaskID:     task.ID,
+		ReviewTime: time.Now(),
+	}))
+
+	pending, err := client.GetReviewAnswerTries(ctx, &storage.GetReviewAnswerTriesRequest{QuestID: quest.ID, OnlyPending: true})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "other", pending[0].Answer)
+}
+
+func TestAnswerHintStorage_GetAnswerTryStats(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+	})
+	require.NoError(t, err)
+
+	taskReq1 := taskReq
+	taskReq1.GroupID = tg.ID
+	taskReq1.Verification = storage.VerificationManual
+	task, err := client.CreateTask(ctx, &taskReq1)
+	require.NoError(t, err)
+
+	team, user := createTestTeam(t, ctx, client, quest, "svayp22", "team1")
+	require.NoError(t, client.LockAnswerTries(ctx, &storage.LockAnswerTriesRequest{TaskID: task.ID, TeamID: team.ID}))
+	tryReq := storage.CreateAnswerTryRequest{
+		Text:         "some answer",
+		TaskID:       task.ID,
+		TeamID:       team.ID,
+		UserID:       user.ID,
+		ReviewStatus: storage.ReviewStatusPending,
+	}
+	require.NoError(t, client.CreateAnswerTry(ctx, &tryReq))
+	require.NoError(t, client.CreateAnswerTry(ctx, &tryReq))
+
+	stats, err := client.GetAnswerTryStats(ctx, &storage.GetAnswerTryStatsRequest{TeamID: team.ID, TaskID: task.ID})
+	require.NoError(t, err)
+	assert.Zero(t, stats.WrongTries)
+	assert.Nil(t, stats.LastTryTime)
+
+	pending, err := client.GetReviewAnswerTries(ctx, &storage.GetReviewAnswerTriesRequest{QuestID: quest.ID, OnlyPending: true})
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.NoError(t, client.ReviewAnswerTry(ctx, &storage.ReviewAnswerTryRequest{ID: pending[0].ID, ReviewStatus: storage.ReviewStatusRejected}))
+
+	stats, err = client.GetAnswerTryStats(ctx, &storage.GetAnswerTryStatsRequest{TeamID: team.ID, TaskID: task.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.WrongTries)
+	assert.NotNil(t, stats.LastTryTime)
+}
+
+func TestAnswerHintStorage_AcceptedParts(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+	})
+	require.NoError(t, err)
+
+	taskReq1 := taskReq
+	taskReq1.GroupID = tg.ID
+	taskReq1.Type = storage.TaskTypeParts
+	taskReq1.Parts = []storage.TaskPart{
+		{Name: "first", CorrectAnswers: []string{"one"}, Share: 50},
+		{Name: "second", CorrectAnswers: []string{"two"}, Share: 50},
+	}
+	task, err := client.CreateTask(ctx, &taskReq1)
+	require.NoError(t, err)
+
+	team, user := createTestTeam(t, ctx, client, quest, "svayp22", "team1")
+	for i, text := range []string{"two", "one"} {
+		require.NoError(t, client.CreateAnswerTry(ctx, &storage.CreateAnswerTryRequest{
+			Text:     text,
+			Accepted: true,
+			Score:    10,
+			TaskID:   task.ID,
+			TeamID:   team.ID,
+			UserID:   user.ID,
+			Part:     ptr.Int(1 - i),
+		}))
+
+		tasks, err := client.GetAcceptedTasks(ctx, &storage.GetAcceptedTasksRequest{QuestID: quest.ID, TeamID: team.ID})
+		require.NoError(t, err)
+		assert.Equal(t, (i+1)*10, tasks[task.ID].Score)
+		assert.Equal(t, i == 0, tasks[task.ID].Partial)
+		assert.Len(t, tasks[task.ID].Parts, i+1)
+	}
+
+	results, err := client.GetScoreResults(ctx, &storage.GetResultsRequest{QuestID: quest.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 20, results[team.ID][task.ID].Score)
+	assert.Equal(t, 2, results[team.ID][task.ID].SolvedParts)
+
+	tasks, err := client.GetAcceptedTasks(ctx, &storage.GetAcceptedTasksRequest{QuestID: quest.ID, TeamID: team.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "one; two", tasks[task.ID].Text)
+}
+
+func TestAnswerHintStorage_PartialChoice(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+	})
+	require.NoError(t, err)
+
+	taskReq1 := taskReq
+	taskReq1.GroupID = tg.ID
+	taskReq1.Type = storage.TaskTypeChoice
+	taskReq1.Choice = &storage.Choice{Options: []string{"a", "b", "c"}, Correct: []int{0, 1}, Multiple: true, PartialScoring: true}
+	task, err := client.CreateTask(ctx, &taskReq1)
+	require.NoError(t, err)
+
+	partialTeam, partialUser := createTestTeam(t, ctx, client, quest, "svayp22", "team1")
+	require.NoError(t, client.CreateAnswerTry(ctx, &storage.CreateAnswerTryRequest{
+		Text: "a", Accepted: true, Partial: true, Score: 5, TaskID: task.ID, TeamID: partialTeam.ID, UserID: partialUser.ID,
+	}))
+
+	tasks, err := client.GetAcceptedTasks(ctx, &storage.GetAcceptedTasksRequest{QuestID: quest.ID, TeamID: partialTeam.ID})
+	require.NoError(t, err)
+	assert.Equal(t, storage.AcceptedTask{Text: "a", Score: 5, Partial: true}, tasks[task.ID])
+	assert.False(t, tasks.Solved(task.ID))
+
+	solveCount, err := client.GetTaskSolveCount(ctx, &storage.GetTaskSolveCountRequest{TaskID: task.ID})
+	require.NoError(t, err)
+	assert.Zero(t, solveCount)
+
+	team, user := createTestTeam(t, ctx, client, quest, "svayp33", "team2")
+	require.NoError(t, client.CreateAnswerTry(ctx, &storage.CreateAnswerTryRequest{
+		Text: "a, b", Accepted: true, Score: 10, TaskID: task.ID, TeamID: team.ID, UserID: user.ID,
+	}))
+	solveCount, err = client.GetTaskSolveCount(ctx, &storage.GetTaskSolveCountRequest{TaskID: task.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, solveCount)
+}
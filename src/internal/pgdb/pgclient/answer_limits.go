@@ -0,0 +1,67 @@
+package pgclient
+
+import (
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/storage"
+)
+
+// answerLimitsColumns are stored in the same way both for quest defaults and task overrides
+var answerLimitsColumns = []string{
+	"max_attempts",
+	"cooldown_attempts",
+	"cooldown",
+	"wrong_penalty_percent",
+	"wrong_penalty_score",
+}
+
+func answerLimitsValues(l *storage.AnswerLimits) []any {
+	var percent, score *int
+	if l.WrongAnswerPenalty != nil {
+		percent = l.WrongAnswerPenalty.PercentOpt()
+		score = l.WrongAnswerPenalty.ScoreOpt()
+	}
+	return []any{l.MaxAttempts, l.CooldownAttempts, l.Cooldown, percent, score}
+}
+
+type answerLimitsRow struct {
+	maxAttempts      *int
+	cooldownAttempts *int
+	cooldown         *storage.Duration
+	penaltyPercent   *int
+	penaltyScore     *int
+}
+
+func (r *answerLimitsRow) dest() []any {
+	return []any{&r.maxAttempts, &r.cooldownAttempts, &r.cooldown, &r.penaltyPercent, &r.penaltyScore}
+}
+
+func (r *answerLimitsRow) limits() (*storage.AnswerLimits, error) {
+	if r.maxAttempts == nil && r.cooldownAttempts == nil && r.cooldown == nil && r.penaltyPercent == nil && r.penaltyScore == nil {
+		return nil, nil
+	}
+	l := &storage.AnswerLimits{
+		MaxAttempts:      r.maxAttempts,
+		CooldownAttempts: r.cooldownAttempts,
+		Cooldown:         r.cooldown,
+	}
+	if r.penaltyPercent != nil {
+		penalty, err := storage.NewPercentagePenalty(*r.penaltyPercent)
+		if err != nil {
+			return nil, xerrors.Errorf("bad penalty: %w", err)
+		}
+		l.WrongAnswerPenalty = &penalty
+	} else if r.penaltyScore != nil {
+		penalty := storage.NewScorePenalty(*r.penaltyScore)
+		l.WrongAnswerPenalty = &penalty
+	}
+	return l, nil
+}
+
+func prefixed(prefix string, columns []string) []string {
+	res := make([]string, 0, len(columns))
+	for _, c := range columns {
+		res = append(res, prefix+c)
+	}
+	return res
+}
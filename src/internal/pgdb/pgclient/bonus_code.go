@@ -0,0 +1,169 @@
+package pgclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/qtime"
+	"questspace/pkg/storage"
+)
+
+var bonusCodeFields = []string{"id", "code", "value", "valid_from", "valid_until", "max_uses", "uses"}
+
+func scanBonusCode(row sq.RowScanner) (*storage.BonusCode, error) {
+	var code storage.BonusCode
+	if err := row.Scan(&code.ID, &code.Code, &code.Value, &code.ValidFrom, &code.ValidUntil, &code.MaxUses, &code.Uses); err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (c *Client) CreateBonusCode(ctx context.Context, req *storage.CreateBonusCodeRequest) (*storage.BonusCode, error) {
+	row := sq.Insert("questspace.bonus_code").
+		Columns("quest_id", "code", "value", "valid_from", "valid_until", "max_uses").
+		Values(req.QuestID, req.Code, req.Value, req.ValidFrom, req.ValidUntil, req.MaxUses).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(c.runner).
+		QueryRowContext(ctx)
+	code := storage.BonusCode{
+		Code:       req.Code,
+		Value:      req.Value,
+		ValidFrom:  req.ValidFrom,
+		ValidUntil: req.ValidUntil,
+		MaxUses:    req.MaxUses,
+	}
+	if err := row.Scan(&code.ID); err != nil {
+		if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, storage.ErrExists
+		}
+		return nil, xerrors.Errorf("scan row: %w", err)
+	}
+	return &code, nil
+}
+
+func (c *Client) GetBonusCode(ctx context.Context, req *storage.GetBonusCodeRequest) (*storage.BonusCode, error) {
+	row := sq.Select(bonusCodeFields...).
+		From("questspace.bonus_code").
+		Where(sq.Eq{"quest_id": req.QuestID}).
+		Where("lower(code) = lower(?)", req.Code).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(c.runner).
+		QueryRowContext(ctx)
+	code, err := scanBonusCode(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, xerrors.Errorf("scan row: %w", err)
+	}
+	return code, nil
+}
+
+func (c *Client) GetBonusCodes(ctx context.Context, req *storage.GetBonusCodesRequest) ([]storage.BonusCode, error) {
+	rows, err := sq.Select(bonusCodeFields...).
+		From("questspace.bonus_code").
+		Where(sq.Eq{"quest_id": req.QuestID}).
+		OrderBy("code").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(c.runner).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("query rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var res []storage.BonusCode
+	for rows.Next() {
+		code, err := scanBonusCode(rows)
+		if err != nil {
+			return nil, xerrors.Errorf("scan row: %w", err)
+		}
+		res = append(res, *code)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iter rows: %w", err)
+	}
+	return res, nil
+}
+
+func (c *Client) DeleteBonusCode(ctx context.Context, req *storage.DeleteBonusCodeRequest) error {
+	res, err := sq.Delete("questspace.bonus_code").
+		Where(sq.Eq{"id": req.ID, "quest_id": req.QuestID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(c.runner).
+		ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("delete bonus code: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return xerrors.Errorf("get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// UseBonusCode records use of the code by team. Counter of uses is incremented under row lock,
+// so that concurrent teams cannot exceed the limit of uses.
+func (c *Client) UseBonusCode(ctx context.Context, req *storage.UseBonusCodeRequest) error {
+	insertQuery := `INSERT INTO questspace.bonus_code_use (code_id, team_id, user_id, time_created) VALUES ($1, $2, $3, $4)`
+	if _, err := c.runner.ExecContext(ctx, insertQuery, req.CodeID, req.TeamID, req.UserID, qtime.Now()); err != nil {
+		if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return storage.ErrExists
+		}
+		return xerrors.Errorf("add bonus code use: %w", err)
+	}
+
+	updateQuery := `UPDATE questspace.bonus_code SET uses = uses + 1 WHERE id = $1 AND (max_uses IS NULL OR uses < max_uses)`
+	res, err := c.runner.ExecContext(ctx, updateQuery, req.CodeID)
+	if err != nil {
+		return xerrors.Errorf("update bonus code uses: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return xerrors.Errorf("get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return storage.ErrUsesExhausted
+	}
+	return nil
+}
+
+func (c *Client) GetBonusCodeUses(ctx context.Context, req *storage.GetBonusCodeUsesRequest) (storage.TeamBonusCodes, error) {
+	query := sq.Select("u.team_id", "u.code_id", "bc.value", "u.time_created").
+		From("questspace.bonus_code_use u").
+		Join("questspace.bonus_code bc ON bc.id = u.code_id").
+		Where(sq.Eq{"bc.quest_id": req.QuestID}).
+		OrderBy("u.time_created").
+		PlaceholderFormat(sq.Dollar)
+	if req.Before != nil {
+		query = query.Where(sq.LtOrEq{"u.time_created": *req.Before})
+	}
+
+	rows, err := query.RunWith(c.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("query rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	res := make(storage.TeamBonusCodes)
+	for rows.Next() {
+		var use storage.BonusCodeUse
+		if err = rows.Scan(&use.TeamID, &use.CodeID, &use.Value, &use.UseTime); err != nil {
+			return nil, xerrors.Errorf("scan row: %w", err)
+		}
+		res[use.TeamID] = append(res[use.TeamID], use)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iter rows: %w", err)
+	}
+	return res, nil
+}
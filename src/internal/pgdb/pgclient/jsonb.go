@@ -0,0 +1,43 @@
+package pgclient
+
+import (
+	"encoding/json"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+)
+
+// jsonbValue encodes value to be stored in jsonb column. Nil values, empty slices and values reporting
+// that they are Empty are stored as NULL
+func jsonbValue[T any](v T) (any, error) {
+	if e, ok := any(v).(interface{ Empty() bool }); ok && e.Empty() {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal %T: %w", v, err)
+	}
+	if s := string(data); s == "null" || s == "[]" {
+		return nil, nil
+	}
+	return string(data), nil
+}
+
+// jsonbRow decodes jsonb column, NULL is decoded as zero value of T
+type jsonbRow[T any] struct {
+	data []byte
+}
+
+func (r *jsonbRow[T]) dest() any {
+	return &r.data
+}
+
+func (r *jsonbRow[T]) value() (T, error) {
+	var v T
+	if len(r.data) == 0 {
+		return v, nil
+	}
+	if err := json.Unmarshal(r.data, &v); err != nil {
+		return v, xerrors.Errorf("unmarshal %T: %w", v, err)
+	}
+	return v, nil
+}
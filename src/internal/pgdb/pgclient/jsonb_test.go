@@ -0,0 +1,39 @@
+package pgclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+)
+
+func TestJSONB_EmptyIsNull(t *testing.T) {
+	values := []func() (any, error){
+		func() (any, error) { return jsonbValue[*storage.Scoring](nil) },
+		func() (any, error) { return jsonbValue(&storage.Prerequisites{}) },
+		func() (any, error) { return jsonbValue([]storage.TaskPart{}) },
+	}
+	for _, value := range values {
+		v, err := value()
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	}
+
+	var row jsonbRow[*storage.Location]
+	location, err := row.value()
+	require.NoError(t, err)
+	assert.Nil(t, location)
+}
+
+func TestJSONB_RoundTrip(t *testing.T) {
+	parts := []storage.TaskPart{{Name: "first", CorrectAnswers: []string{"a"}, Share: 100}}
+	value, err := jsonbValue(parts)
+	require.NoError(t, err)
+
+	row := jsonbRow[[]storage.TaskPart]{data: []byte(value.(string))}
+	decoded, err := row.value()
+	require.NoError(t, err)
+	assert.Equal(t, parts, decoded)
+}
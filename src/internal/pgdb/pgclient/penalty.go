@@ -0,0 +1,116 @@
+package pgclient
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/qtime"
+	"questspace/pkg/storage"
+)
+
+func (c *Client) GetAdjustments(ctx context.Context, req *storage.GetAdjustmentsRequest) ([]storage.Adjustment, error) {
+	query := sq.Select(
+		"p.id",
+		"p.team_id",
+		"p.value",
+		"p.reason",
+		"p.task_id",
+		"p.group_id",
+		"p.legacy",
+		"p.time_created",
+		"p.time_revoked",
+		"a.id",
+		"a.username",
+		"a.avatar_url",
+		"r.id",
+		"r.username",
+		"r.avatar_url",
+	).
+		From("questspace.team_penalty p").
+		Join("questspace.team t ON t.id = p.team_id").
+		LeftJoin("questspace.user a ON a.id = p.author_id").
+		LeftJoin("questspace.user r ON r.id = p.revoked_by").
+		Where(sq.Eq{"t.quest_id": req.QuestID}).
+		OrderBy("p.time_created", "p.id").
+		PlaceholderFormat(sq.Dollar)
+	if len(req.TeamID) > 0 {
+		query = query.Where(sq.Eq{"p.team_id": req.TeamID})
+	}
+	if req.ActiveOnly {
+		query = query.Where(sq.Eq{"p.time_revoked": nil})
+	}
+
+	rows, err := query.RunWith(c.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("query rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var res []storage.Adjustment
+	for rows.Next() {
+		var (
+			a                                     storage.Adjustment
+			reason, taskID, groupID               sql.NullString
+			authorID, authorName, authorAvatar    sql.NullString
+			revokerID, revokerName, revokerAvatar sql.NullString
+		)
+		if err = rows.Scan(
+			&a.ID,
+			&a.TeamID,
+			&a.Value,
+			&reason,
+			&taskID,
+			&groupID,
+			&a.Legacy,
+			&a.CreateTime,
+			&a.RevokeTime,
+			&authorID,
+			&authorName,
+			&authorAvatar,
+			&revokerID,
+			&revokerName,
+			&revokerAvatar,
+		); err != nil {
+			return nil, xerrors.Errorf("scan row: %w", err)
+		}
+		a.Reason = reason.String
+		a.TaskID = storage.ID(taskID.String)
+		a.TaskGroupID = storage.ID(groupID.String)
+		if authorID.Valid {
+			a.Author = &storage.User{ID: storage.ID(authorID.String), Username: authorName.String, AvatarURL: authorAvatar.String}
+		}
+		if revokerID.Valid {
+			a.RevokedBy = &storage.User{ID: storage.ID(revokerID.String), Username: revokerName.String, AvatarURL: revokerAvatar.String}
+		}
+		res = append(res, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iter rows: %w", err)
+	}
+	return res, nil
+}
+
+// RevokeAdjustment marks adjustment as revoked, so that it is not counted in team score anymore.
+// Returns storage.ErrNotFound when there is no such adjustment in quest or it is already revoked.
+func (c *Client) RevokeAdjustment(ctx context.Context, req *storage.RevokeAdjustmentRequest) error {
+	query := `
+	UPDATE questspace.team_penalty p SET time_revoked = $1, revoked_by = $2
+	FROM questspace.team t
+	WHERE t.id = p.team_id AND p.id = $3 AND t.quest_id = $4 AND p.time_revoked IS NULL
+`
+	res, err := c.runner.ExecContext(ctx, query, qtime.Now(), req.UserID, req.ID, req.QuestID)
+	if err != nil {
+		return xerrors.Errorf("revoke adjustment: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return xerrors.Errorf("get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
@@ -57,6 +57,22 @@ func (c *Client) CreateQuest(ctx context.Context, req *storage.CreateQuestReques
 		values = append(values, *req.FeedbackLink)
 		query = query.Columns("feedback_link")
 	}
+	if req.AnswerLimits != nil {
+		values = append(values, answerLimitsValues(req.AnswerLimits)...)
+		query = query.Columns(answerLimitsColumns...)
+	}
+	if req.LeaderboardFreeze != nil && *req.LeaderboardFreeze > 0 {
+		values = append(values, *req.LeaderboardFreeze)
+		query = query.Columns("leaderboard_freeze")
+	}
+	if req.DefaultHintPenalty != nil {
+		values = append(values, req.DefaultHintPenalty.PercentOpt(), req.DefaultHintPenalty.ScoreOpt())
+		query = query.Columns("default_hint_penalty_percent", "default_hint_penalty_score")
+	}
+	if req.AutoFinish {
+		values = append(values, true)
+		query = query.Columns("auto_finish")
+	}
 
 	row := query.Values(values...).RunWith(c.runner).QueryRowContext(ctx)
 	quest := storage.Quest{
@@ -79,6 +95,12 @@ func (c *Client) CreateQuest(ctx context.Context, req *storage.CreateQuestReques
 		RegistrationType:     req.RegistrationType,
 		QuestType:            req.QuestType,
 		FeedbackLink:         req.FeedbackLink,
+		AnswerLimits:         req.AnswerLimits,
+		DefaultHintPenalty:   req.DefaultHintPenalty,
+		AutoFinish:           req.AutoFinish,
+	}
+	if req.LeaderboardFreeze != nil && *req.LeaderboardFreeze > 0 {
+		quest.LeaderboardFreeze = req.LeaderboardFreeze
 	}
 	if err := row.Scan(&quest.ID); err != nil {
 		return nil, xerrors.Errorf("scan row: %w", err)
@@ -107,6 +129,16 @@ SELECT
 	q.registration_type,
 	q.quest_type,
 	q.feedback_link,
+	q.max_attempts,
+	q.cooldown_attempts,
+	q.cooldown,
+	q.wrong_penalty_percent,
+	q.wrong_penalty_score,
+	q.leaderboard_freeze,
+	q.leaderboard_revealed,
+	q.default_hint_penalty_percent,
+	q.default_hint_penalty_score,
+	q.auto_finish,
 	u.id,
 	u.username,
 	u.avatar_url
@@ -121,8 +153,10 @@ func (c *Client) GetQuest(ctx context.Context, req *storage.GetQuestRequest) (*s
 		creatorName           sql.NullString
 		userId, userAvatarURL sql.NullString
 		finished              bool
+		limits                answerLimitsRow
+		hintPenalty           penaltyRow
 	)
-	if err := row.Scan(
+	dest := []any{
 		&q.ID,
 		&q.Name,
 		&q.Description,
@@ -139,15 +173,22 @@ func (c *Client) GetQuest(ctx context.Context, req *storage.GetQuestRequest) (*s
 		&q.RegistrationType,
 		&q.QuestType,
 		&q.FeedbackLink,
-		&userId,
-		&creatorName,
-		&userAvatarURL,
-	); err != nil {
+	}
+	dest = append(dest, limits.dest()...)
+	dest = append(dest, &q.LeaderboardFreeze, &q.LeaderboardRevealed, &hintPenalty.percent, &hintPenalty.score, &q.AutoFinish, &userId, &creatorName, &userAvatarURL)
+	if err := row.Scan(dest...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrNotFound
 		}
 		return nil, xerrors.Errorf("scan row: %w", err)
 	}
+	var err error
+	if q.AnswerLimits, err = limits.limits(); err != nil {
+		return nil, xerrors.Errorf("answer limits: %w", err)
+	}
+	if q.DefaultHintPenalty, err = hintPenalty.penalty(); err != nil {
+		return nil, xerrors.Errorf("default hint penalty: %w", err)
+	}
 	if userId.Valid {
 		q.Creator = &storage.User{ID: storage.ID(userId.String)}
 	}
@@ -202,6 +243,8 @@ func (c *Client) GetQuests(ctx context.Context, req *storage.GetQuestsRequest) (
 		"q.finished",
 		"q.quest_type",
 		"q.feedback_link",
+		"q.leaderboard_freeze",
+		"q.leaderboard_revealed",
 		"q.creator_id",
 		"u.username",
 		"u.avatar_url",
@@ -255,6 +298,8 @@ func (c *Client) GetQuests(ctx context.Context, req *storage.GetQuestsRequest) (
 			&finished,
 			&q.QuestType,
 			&q.FeedbackLink,
+			&q.LeaderboardFreeze,
+			&q.LeaderboardRevealed,
 			&userId, &username, &userAvatarURL,
 		); err != nil {
 			return nil, xerrors.Errorf("scan row: %w", err)
@@ -311,7 +356,17 @@ func (c *Client) UpdateQuest(ctx context.Context, req *storage.UpdateQuestReques
 		max_teams_amount,
 		registration_type,
 		quest_type,
-		feedback_link`).
+		feedback_link,
+		max_attempts,
+		cooldown_attempts,
+		cooldown,
+		wrong_penalty_percent,
+		wrong_penalty_score,
+		leaderboard_freeze,
+		leaderboard_revealed,
+		default_hint_penalty_percent,
+		default_hint_penalty_score,
+		auto_finish`).
 		PlaceholderFormat(sq.Dollar)
 	if len(req.Name) > 0 {
 		query = query.Set("name", req.Name)
@@ -357,14 +412,33 @@ func (c *Client) UpdateQuest(ctx context.Context, req *storage.UpdateQuestReques
 	if req.FeedbackLink != nil {
 		query = query.Set("feedback_link", req.FeedbackLink)
 	}
+	if req.AnswerLimits != nil {
+		for i, value := range answerLimitsValues(req.AnswerLimits) {
+			query = query.Set(answerLimitsColumns[i], value)
+		}
+	}
+	if req.LeaderboardFreeze != nil && *req.LeaderboardFreeze > 0 {
+		query = query.Set("leaderboard_freeze", *req.LeaderboardFreeze)
+	} else if req.LeaderboardFreeze != nil {
+		query = query.Set("leaderboard_freeze", nil)
+	}
+	if req.DefaultHintPenalty != nil {
+		query = query.Set("default_hint_penalty_percent", req.DefaultHintPenalty.PercentOpt()).
+			Set("default_hint_penalty_score", req.DefaultHintPenalty.ScoreOpt())
+	}
+	if req.AutoFinish != nil {
+		query = query.Set("auto_finish", *req.AutoFinish)
+	}
 
 	row := query.RunWith(c.runner).QueryRowContext(ctx)
 	var (
-		q         storage.Quest
-		creatorID sql.NullString
-		finished  bool
+		q           storage.Quest
+		creatorID   sql.NullString
+		finished    bool
+		limits      answerLimitsRow
+		hintPenalty penaltyRow
 	)
-	if err := row.Scan(
+	dest := []any{
 		&q.ID,
 		&q.Name,
 		&q.Description,
@@ -382,12 +456,21 @@ func (c *Client) UpdateQuest(ctx context.Context, req *storage.UpdateQuestReques
 		&q.RegistrationType,
 		&q.QuestType,
 		&q.FeedbackLink,
-	); err != nil {
+	}
+	dest = append(dest, limits.dest()...)
+	if err := row.Scan(append(dest, &q.LeaderboardFreeze, &q.LeaderboardRevealed, &hintPenalty.percent, &hintPenalty.score, &q.AutoFinish)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrNotFound
 		}
 		return nil, xerrors.Errorf("scan row: %w", err)
 	}
+	var err error
+	if q.AnswerLimits, err = limits.limits(); err != nil {
+		return nil, xerrors.Errorf("answer limits: %w", err)
+	}
+	if q.DefaultHintPenalty, err = hintPenalty.penalty(); err != nil {
+		return nil, xerrors.Errorf("default hint penalty: %w", err)
+	}
 	if creatorID.Valid {
 		q.Creator = &storage.User{ID: storage.ID(creatorID.String)}
 	}
@@ -421,3 +504,75 @@ func (c *Client) FinishQuest(ctx context.Context, req *storage.FinishQuestReques
 	}
 	return nil
 }
+
+// GetExpiredQuests returns not finished quests with auto finish, which finish time has already passed
+func (c *Client) GetExpiredQuests(ctx context.Context, req *storage.GetExpiredQuestsRequest) ([]storage.ID, error) {
+	query := sq.Select("q.id").
+		From("questspace.quest q").
+		Where(sq.Eq{"q.auto_finish": true, "q.finished": false}).
+		Where(sq.LtOrEq{"q.finish_time": req.Now}).
+		PlaceholderFormat(sq.Dollar)
+	return c.queryQuestIDs(ctx, query)
+}
+
+// GetRunningQuests returns not finished quests, which are already started and not expired yet
+func (c *Client) GetRunningQuests(ctx context.Context, req *storage.GetRunningQuestsRequest) ([]storage.ID, error) {
+	query := sq.Select("q.id").
+		From("questspace.quest q").
+		Where(sq.Eq{"q.finished": false}).
+		Where(sq.LtOrEq{"q.start_time": req.Now}).
+		Where(sq.Or{sq.Eq{"q.finish_time": nil}, sq.Gt{"q.finish_time": req.Now}}).
+		PlaceholderFormat(sq.Dollar)
+	if len(req.QuestType) > 0 {
+		query = query.Where(sq.Eq{"q.quest_type": req.QuestType})
+	}
+	return c.queryQuestIDs(ctx, query)
+}
+
+func (c *Client) queryQuestIDs(ctx context.Context, query sq.SelectBuilder) ([]storage.ID, error) {
+	rows, err := query.RunWith(c.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("query rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []storage.ID
+	for rows.Next() {
+		var id storage.ID
+		if err = rows.Scan(&id); err != nil {
+			return nil, xerrors.Errorf("scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iter rows: %w", err)
+	}
+	return ids, nil
+}
+
+func (c *Client) RevealLeaderboard(ctx context.Context, req *storage.RevealLeaderboardRequest) error {
+	query := `
+	UPDATE questspace.quest SET leaderboard_revealed = true
+		WHERE id = $1
+`
+
+	res, err := c.runner.ExecContext(ctx, query, req.ID)
+	if err != nil {
+		return xerrors.Errorf("exec query: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return xerrors.Errorf("get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (c *Client) Notify(ctx context.Context, req *storage.NotifyRequest) error {
+	if _, err := c.runner.ExecContext(ctx, `SELECT pg_notify($1, $2)`, req.Channel, req.Payload); err != nil {
+		return xerrors.Errorf("exec query: %w", err)
+	}
+	return nil
+}
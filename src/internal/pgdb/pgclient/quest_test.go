@@ -134,6 +134,7 @@ func TestQuestStorage_GetQuests(t *testing.T) {
 	})
 	require.NoError(t, err)
 	now := time.Now().UTC()
+	freeze := storage.Duration(30 * time.Minute)
 
 	q1, err := client.CreateQuest(ctx, &storage.CreateQuestRequest{
 		Name:      "q1",
@@ -143,12 +144,14 @@ func TestQuestStorage_GetQuests(t *testing.T) {
 	})
 	require.NoError(t, err)
 	q2, err := client.CreateQuest(ctx, &storage.CreateQuestRequest{
-		Name:      "q2",
-		Creator:   user,
-		Access:    storage.AccessPublic,
-		StartTime: ptr.Time(now.Add(time.Hour * 48)),
+		Name:              "q2",
+		Creator:           user,
+		Access:            storage.AccessPublic,
+		StartTime:         ptr.Time(now.Add(time.Hour * 48)),
+		LeaderboardFreeze: &freeze,
 	})
 	require.NoError(t, err)
+	require.NoError(t, client.RevealLeaderboard(ctx, &storage.RevealLeaderboardRequest{ID: q2.ID}))
 
 	allQuests, err := client.GetQuests(ctx, &storage.GetQuestsRequest{
 		User:     user,
@@ -159,6 +162,8 @@ func TestQuestStorage_GetQuests(t *testing.T) {
 	require.Len(t, allQuests.Quests, 2)
 	assert.Equal(t, q1.ID, allQuests.Quests[0].ID)
 	assert.Equal(t, q2.ID, allQuests.Quests[1].ID)
+	assert.Equal(t, q2.LeaderboardFreeze, allQuests.Quests[1].LeaderboardFreeze)
+	assert.True(t, allQuests.Quests[1].LeaderboardRevealed)
 
 	rest, err := client.GetQuests(ctx, &storage.GetQuestsRequest{
 		User:     user,
@@ -170,3 +175,48 @@ func TestQuestStorage_GetQuests(t *testing.T) {
 	assert.Empty(t, rest.Quests)
 	assert.Nil(t, rest.NextPage)
 }
+
+func TestQuestStorage_GetExpiredAndRunningQuests(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+	user, err := client.CreateUser(ctx, &storage.CreateUserRequest{Username: "svayp11"})
+	require.NoError(t, err)
+	now := time.Now().UTC()
+
+	createQuest := func(name string, questType storage.QuestType, start time.Time, finish *time.Time, autoFinish bool) storage.ID {
+		q, err := client.CreateQuest(ctx, &storage.CreateQuestRequest{
+			Name:       name,
+			Creator:    user,
+			Access:     storage.AccessPublic,
+			StartTime:  &start,
+			FinishTime: finish,
+			QuestType:  questType,
+			AutoFinish: autoFinish,
+		})
+		require.NoError(t, err)
+		return q.ID
+	}
+	expired := createQuest("expired", storage.TypeAssault, now.Add(-2*time.Hour), ptr.Time(now.Add(-time.Hour)), true)
+	createQuest("waits for results", storage.TypeAssault, now.Add(-2*time.Hour), ptr.Time(now.Add(-time.Hour)), false)
+	running := createQuest("running", storage.TypeLinear, now.Add(-time.Hour), ptr.Time(now.Add(time.Hour)), true)
+	endless := createQuest("endless", storage.TypeLinear, now.Add(-time.Hour), nil, false)
+	createQuest("future", storage.TypeLinear, now.Add(time.Hour), nil, false)
+	createQuest("assault", storage.TypeAssault, now.Add(-time.Hour), nil, false)
+
+	q, err := client.GetQuest(ctx, &storage.GetQuestRequest{ID: expired})
+	require.NoError(t, err)
+	assert.True(t, q.AutoFinish)
+
+	ids, err := client.GetExpiredQuests(ctx, &storage.GetExpiredQuestsRequest{Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, []storage.ID{expired}, ids)
+
+	ids, err = client.GetRunningQuests(ctx, &storage.GetRunningQuestsRequest{Now: now, QuestType: storage.TypeLinear})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []storage.ID{running, endless}, ids)
+
+	require.NoError(t, client.FinishQuest(ctx, &storage.FinishQuestRequest{ID: expired}))
+	ids, err = client.GetExpiredQuests(ctx, &storage.GetExpiredQuestsRequest{Now: now})
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
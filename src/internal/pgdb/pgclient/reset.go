@@ -0,0 +1,70 @@
+package pgclient
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/storage"
+)
+
+type resetColumn struct {
+	name  string
+	value any
+}
+
+var taskResetColumns = map[storage.ResetField][]resetColumn{
+	storage.ResetQuestion:       {{"question", ""}},
+	storage.ResetReward:         {{"reward", 0}},
+	storage.ResetCorrectAnswers: {{"correct_answers", pgtype.FlatArray[string]{}}},
+	storage.ResetMatcher:        {{"matcher", nil}, {"matcher_tolerance", nil}, {"matcher_threshold", nil}},
+	storage.ResetAnswerLimits:   nullColumns(answerLimitsColumns...),
+	storage.ResetScoring:        {{"scoring", nil}},
+	storage.ResetChoice:         {{"choice", nil}},
+	storage.ResetLocation:       {{"location", nil}},
+	storage.ResetParts:          {{"parts", nil}},
+	storage.ResetPubTime:        {{"pub_time", nil}},
+	storage.ResetMediaLinks:     {{"media_url", ""}, {"media_urls", pgtype.FlatArray[string]{}}},
+}
+
+var taskGroupResetColumns = map[storage.ResetField][]resetColumn{
+	storage.ResetPubTime:     {{"pub_time", nil}},
+	storage.ResetTimeLimit:   {{"time_limit", nil}},
+	storage.ResetSkipPenalty: {{"skip_penalty_percent", nil}, {"skip_penalty_score", nil}},
+	storage.ResetLocation:    {{"location", nil}},
+}
+
+func nullColumns(names ...string) []resetColumn {
+	columns := make([]resetColumn, 0, len(names))
+	for _, name := range names {
+		columns = append(columns, resetColumn{name: name})
+	}
+	return columns
+}
+
+// setResets sets default values to columns of reset fields. Field is rejected when it is unknown or set by the same request
+func setResets(
+	query sq.UpdateBuilder,
+	reset []storage.ResetField,
+	columns map[storage.ResetField][]resetColumn,
+	isSet func(storage.ResetField) bool,
+) (sq.UpdateBuilder, error) {
+	seen := make(map[storage.ResetField]struct{}, len(reset))
+	for _, field := range reset {
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		fieldColumns, ok := columns[field]
+		if !ok {
+			return query, xerrors.Errorf("unknown field %q to reset: %w", field, storage.ErrValidation)
+		}
+		if isSet(field) {
+			return query, xerrors.Errorf("field %q cannot be both set and reset: %w", field, storage.ErrValidation)
+		}
+		for _, c := range fieldColumns {
+			query = query.Set(c.name, c.value)
+		}
+	}
+	return query, nil
+}
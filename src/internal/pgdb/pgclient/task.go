@@ -26,10 +26,17 @@ func (c *Client) createHints(ctx context.Context, taskID storage.ID, hints []sto
 			"text",
 			"penalty_percent",
 			"penalty_score",
+			"opens_after",
+			"auto_penalty_percent",
+			"auto_penalty_score",
 		).
 		PlaceholderFormat(sq.Dollar)
 
 	for i, hintReq := range hints {
+		var autoPercent, autoScore *int
+		if hintReq.AutoPenalty != nil {
+			autoPercent, autoScore = hintReq.AutoPenalty.PercentOpt(), hintReq.AutoPenalty.ScoreOpt()
+		}
 		hintArgs := []any{
 			taskID,
 			i,
@@ -37,6 +44,9 @@ func (c *Client) createHints(ctx context.Context, taskID storage.ID, hints []sto
 			hintReq.Text,
 			hintReq.Penalty.PercentOpt(),
 			hintReq.Penalty.ScoreOpt(),
+			hintReq.OpensAfter,
+			autoPercent,
+			autoScore,
 		}
 		query = query.Values(hintArgs...)
 
@@ -48,11 +58,13 @@ func (c *Client) createHints(ctx context.Context, taskID storage.ID, hints []sto
 		}
 
 		hintsRes = append(hintsRes, storage.Hint{
-			TaskID:  taskID,
-			Index:   i,
-			Name:    hintReq.Name,
-			Text:    hintReq.Text,
-			Penalty: hintReq.Penalty,
+			TaskID:      taskID,
+			Index:       i,
+			Name:        hintReq.Name,
+			Text:        hintReq.Text,
+			Penalty:     hintReq.Penalty,
+			OpensAfter:  hintReq.OpensAfter,
+			AutoPenalty: hintReq.AutoPenalty,
 		})
 	}
 
@@ -72,6 +84,9 @@ func (c *Client) getHints(ctx context.Context, taskIDs []storage.ID) (hintsByTas
 		"text",
 		"penalty_percent",
 		"penalty_score",
+		"opens_after",
+		"auto_penalty_percent",
+		"auto_penalty_score",
 	).From("questspace.hint").
 		Where(sq.Eq{"task_id": taskIDs}).
 		OrderBy("index").
@@ -87,6 +102,7 @@ func (c *Client) getHints(ctx context.Context, taskIDs []storage.ID) (hintsByTas
 	for rows.Next() {
 		var hint storage.Hint
 		var percent, score *int
+		var autoPenalty penaltyRow
 		if err = rows.Scan(
 			&hint.TaskID,
 			&hint.Index,
@@ -94,6 +110,9 @@ func (c *Client) getHints(ctx context.Context, taskIDs []storage.ID) (hintsByTas
 			&hint.Text,
 			&percent,
 			&score,
+			&hint.OpensAfter,
+			&autoPenalty.percent,
+			&autoPenalty.score,
 		); err != nil {
 			return nil, xerrors.Errorf("scan row: %w", err)
 		}
@@ -106,6 +125,9 @@ func (c *Client) getHints(ctx context.Context, taskIDs []storage.ID) (hintsByTas
 		if score != nil {
 			hint.Penalty = storage.NewScorePenalty(*score)
 		}
+		if hint.AutoPenalty, err = autoPenalty.penalty(); err != nil {
+			return nil, xerrors.Errorf("bad auto penalty: %w", err)
+		}
 
 		taskHints := hintsByTaskID[hint.TaskID]
 		taskHints = append(taskHints, hint)
@@ -128,6 +150,28 @@ func (c *Client) updateHints(ctx context.Context, taskID storage.ID, hints []sto
 	return c.createHints(ctx, taskID, hints)
 }
 
+func newMatcher(matcherType *storage.MatcherType, tolerance *float64, threshold *int) *storage.AnswerMatcher {
+	if matcherType == nil {
+		return nil
+	}
+	return &storage.AnswerMatcher{
+		Type:      *matcherType,
+		Tolerance: tolerance,
+		Threshold: threshold,
+	}
+}
+
+var matcherColumns = []string{"matcher", "matcher_tolerance", "matcher_threshold"}
+
+// matcherValues returns column values of matcher. Exact matching is the default one, so it is stored
+// as absent matcher, and setting it clears parameters of previous matcher
+func matcherValues(m *storage.AnswerMatcher) []any {
+	if m.Type == storage.MatcherExact {
+		return []any{nil, nil, nil}
+	}
+	return []any{m.Type, m.Tolerance, m.Threshold}
+}
+
 func (c *Client) CreateTask(ctx context.Context, req *storage.CreateTaskRequest) (*storage.Task, error) {
 	values := []any{
 		req.OrderIdx,
@@ -166,6 +210,62 @@ func (c *Client) CreateTask(ctx context.Context, req *storage.CreateTaskRequest)
 		query = query.Columns("pub_time")
 		values = append(values, req.PubTime)
 	}
+	if req.Matcher != nil {
+		query = query.Columns(matcherColumns...)
+		values = append(values, matcherValues(req.Matcher)...)
+	}
+	if req.AnswerLimits != nil {
+		query = query.Columns(answerLimitsColumns...)
+		values = append(values, answerLimitsValues(req.AnswerLimits)...)
+	}
+	if req.Scoring != nil {
+		scoring, err := jsonbValue(req.Scoring)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Columns("scoring")
+		values = append(values, scoring)
+	}
+	if !req.Requires.Empty() {
+		requires, err := jsonbValue(req.Requires)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Columns("requires")
+		values = append(values, requires)
+	}
+	if len(req.Type) > 0 {
+		query = query.Columns("task_type")
+		values = append(values, req.Type)
+	}
+	if req.Choice != nil {
+		choice, err := jsonbValue(req.Choice)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Columns("choice")
+		values = append(values, choice)
+	}
+	if req.Location != nil {
+		location, err := jsonbValue(req.Location)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Columns("location")
+		values = append(values, location)
+	}
+	if len(req.Parts) > 0 {
+		parts, err := jsonbValue(req.Parts)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Columns("parts")
+		values = append(values, parts)
+	}
+	if req.OrderedHints {
+		query = query.Columns("ordered_hints")
+		values = append(values, true)
+	}
 	query = query.Values(values...)
 
 	row := query.RunWith(c.runner).QueryRowContext(ctx)
@@ -177,6 +277,9 @@ func (c *Client) CreateTask(ctx context.Context, req *storage.CreateTaskRequest)
 		Question:        req.Question,
 		Reward:          req.Reward,
 		CorrectAnswers:  slices.Clone(req.CorrectAnswers),
+		Matcher:         req.Matcher,
+		AnswerLimits:    req.AnswerLimits,
+		Scoring:         req.Scoring,
 		Verification:    req.Verification,
 		VerificationNew: req.Verification,
 		Hints:           append([]string{}, req.Hints...),
@@ -184,16 +287,22 @@ func (c *Client) CreateTask(ctx context.Context, req *storage.CreateTaskRequest)
 		MediaLinks:      req.MediaLinks,
 		MediaLink:       req.MediaLink,
 		PubTime:         req.PubTime,
+		Type:            req.Type,
+		Choice:          req.Choice,
+		Location:        req.Location,
+		Parts:           req.Parts,
+		OrderedHints:    req.OrderedHints,
+	}
+	if !req.Requires.Empty() {
+		task.Requires = req.Requires
+	}
+	if len(task.Type) == 0 {
+		task.Type = storage.TaskTypeText
 	}
 	if err := row.Scan(&task.ID); err != nil {
 		return nil, xerrors.Errorf("scan row: %w", err)
 	}
 
-	if len(req.FullHints) == 0 {
-		for _, hintText := range task.Hints {
-			req.FullHints = append(req.FullHints, storage.CreateHintRequest{Text: hintText, Penalty: storage.DefaultPenalty})
-		}
-	}
 	var err error
 	task.FullHints, err = c.createHints(ctx, task.ID, req.FullHints)
 	if err != nil {
@@ -215,7 +324,22 @@ SELECT
 	media_url,
 	media_urls,
 	pub_time,
-	group_id
+	group_id,
+	matcher,
+	matcher_tolerance,
+	matcher_threshold,
+	max_attempts,
+	cooldown_attempts,
+	cooldown,
+	wrong_penalty_percent,
+	wrong_penalty_score,
+	scoring,
+	requires,
+	task_type,
+	choice,
+	location,
+	parts,
+	ordered_hints
 FROM questspace.task
 	WHERE id = $1
 `
@@ -224,7 +348,16 @@ func (c *Client) GetTask(ctx context.Context, req *storage.GetTaskRequest) (*sto
 	row := c.runner.QueryRowContext(ctx, getTaskQuery, req.ID)
 	task := storage.Task{ID: req.ID, Group: &storage.TaskGroup{}}
 	pgMap := pgtype.NewMap()
-	if err := row.Scan(
+	var matcherType *storage.MatcherType
+	var tolerance *float64
+	var threshold *int
+	var limits answerLimitsRow
+	var scoring jsonbRow[*storage.Scoring]
+	var requires jsonbRow[*storage.Prerequisites]
+	var choice jsonbRow[*storage.Choice]
+	var location jsonbRow[*storage.Location]
+	var parts jsonbRow[[]storage.TaskPart]
+	dest := []any{
 		&task.OrderIdx,
 		&task.Name,
 		&task.Question,
@@ -236,7 +369,11 @@ func (c *Client) GetTask(ctx context.Context, req *storage.GetTaskRequest) (*sto
 		pgMap.SQLScanner(&task.MediaLinks),
 		&task.PubTime,
 		&task.Group.ID,
-	); err != nil {
+		&matcherType,
+		&tolerance,
+		&threshold,
+	}
+	if err := row.Scan(append(dest, append(limits.dest(), scoring.dest(), requires.dest(), &task.Type, choice.dest(), location.dest(), parts.dest(), &task.OrderedHints)...)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrNotFound
 		}
@@ -248,6 +385,26 @@ func (c *Client) GetTask(ctx context.Context, req *storage.GetTaskRequest) (*sto
 	task.Hints = append([]string{}, task.Hints...)
 	task.VerificationNew = task.Verification
 	task.FullHints = []storage.Hint{}
+	task.Matcher = newMatcher(matcherType, tolerance, threshold)
+	var err error
+	if task.AnswerLimits, err = limits.limits(); err != nil {
+		return nil, xerrors.Errorf("answer limits: %w", err)
+	}
+	if task.Scoring, err = scoring.value(); err != nil {
+		return nil, xerrors.Errorf("scoring: %w", err)
+	}
+	if task.Requires, err = requires.value(); err != nil {
+		return nil, xerrors.Errorf("prerequisites: %w", err)
+	}
+	if task.Choice, err = choice.value(); err != nil {
+		return nil, xerrors.Errorf("choice: %w", err)
+	}
+	if task.Location, err = location.value(); err != nil {
+		return nil, xerrors.Errorf("location: %w", err)
+	}
+	if task.Parts, err = parts.value(); err != nil {
+		return nil, xerrors.Errorf("parts: %w", err)
+	}
 
 	hintsByID, err := c.getHints(ctx, []storage.ID{req.ID})
 	if err != nil {
@@ -261,7 +418,19 @@ func (c *Client) GetTask(ctx context.Context, req *storage.GetTaskRequest) (*sto
 }
 
 func (c *Client) GetAnswerData(ctx context.Context, req *storage.GetTaskRequest) (*storage.Task, error) {
-	query := sq.Select("group_id", "correct_answers", "reward", "verification", "hints").
+	query := sq.Select(
+		"group_id",
+		"correct_answers",
+		"reward",
+		"verification",
+		"hints",
+		"matcher",
+		"matcher_tolerance",
+		"matcher_threshold",
+		"pub_time",
+	).
+		Columns(answerLimitsColumns...).
+		Columns("scoring", "requires", "task_type", "choice", "location", "parts", "ordered_hints").
 		From("questspace.task").
 		Where(sq.Eq{"id": req.ID}).
 		PlaceholderFormat(sq.Dollar)
@@ -269,13 +438,27 @@ func (c *Client) GetAnswerData(ctx context.Context, req *storage.GetTaskRequest)
 	row := query.RunWith(c.runner).QueryRowContext(ctx)
 	task := storage.Task{ID: req.ID, Group: &storage.TaskGroup{}}
 	pgMap := pgtype.NewMap()
-	if err := row.Scan(
+	var matcherType *storage.MatcherType
+	var tolerance *float64
+	var threshold *int
+	var limits answerLimitsRow
+	var scoring jsonbRow[*storage.Scoring]
+	var requires jsonbRow[*storage.Prerequisites]
+	var choice jsonbRow[*storage.Choice]
+	var location jsonbRow[*storage.Location]
+	var parts jsonbRow[[]storage.TaskPart]
+	dest := []any{
 		&task.Group.ID,
 		pgMap.SQLScanner(&task.CorrectAnswers),
 		&task.Reward,
 		&task.Verification,
 		pgMap.SQLScanner(&task.Hints),
-	); err != nil {
+		&matcherType,
+		&tolerance,
+		&threshold,
+		&task.PubTime,
+	}
+	if err := row.Scan(append(dest, append(limits.dest(), scoring.dest(), requires.dest(), &task.Type, choice.dest(), location.dest(), parts.dest(), &task.OrderedHints)...)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrNotFound
 		}
@@ -283,6 +466,26 @@ func (c *Client) GetAnswerData(ctx context.Context, req *storage.GetTaskRequest)
 	}
 	task.VerificationNew = task.Verification
 	task.FullHints = []storage.Hint{}
+	task.Matcher = newMatcher(matcherType, tolerance, threshold)
+	var err error
+	if task.AnswerLimits, err = limits.limits(); err != nil {
+		return nil, xerrors.Errorf("answer limits: %w", err)
+	}
+	if task.Scoring, err = scoring.value(); err != nil {
+		return nil, xerrors.Errorf("scoring: %w", err)
+	}
+	if task.Requires, err = requires.value(); err != nil {
+		return nil, xerrors.Errorf("prerequisites: %w", err)
+	}
+	if task.Choice, err = choice.value(); err != nil {
+		return nil, xerrors.Errorf("choice: %w", err)
+	}
+	if task.Location, err = location.value(); err != nil {
+		return nil, xerrors.Errorf("location: %w", err)
+	}
+	if task.Parts, err = parts.value(); err != nil {
+		return nil, xerrors.Errorf("parts: %w", err)
+	}
 
 	hintsByID, err := c.getHints(ctx, []storage.ID{req.ID})
 	if err != nil {
@@ -309,7 +512,12 @@ func (c *Client) GetTasks(ctx context.Context, req *storage.GetTasksRequest) (st
 		"t.media_url",
 		"t.media_urls",
 		"t.pub_time",
+		"t.matcher",
+		"t.matcher_tolerance",
+		"t.matcher_threshold",
 	).
+		Columns(prefixed("t.", answerLimitsColumns)...).
+		Columns("t.scoring", "t.requires", "t.task_type", "t.choice", "t.location", "t.parts", "t.ordered_hints").
 		From("questspace.task t").
 		OrderBy("t.group_id", "t.order_idx ASC").
 		PlaceholderFormat(sq.Dollar)
@@ -333,7 +541,16 @@ func (c *Client) GetTasks(ctx context.Context, req *storage.GetTasksRequest) (st
 	var ids []storage.ID
 	for rows.Next() {
 		task := storage.Task{Group: &storage.TaskGroup{}}
-		if err := rows.Scan(
+		var matcherType *storage.MatcherType
+		var tolerance *float64
+		var threshold *int
+		var limits answerLimitsRow
+		var scoring jsonbRow[*storage.Scoring]
+		var requires jsonbRow[*storage.Prerequisites]
+		var choice jsonbRow[*storage.Choice]
+		var location jsonbRow[*storage.Location]
+		var parts jsonbRow[[]storage.TaskPart]
+		dest := []any{
 			&task.ID,
 			&task.OrderIdx,
 			&task.Name,
@@ -346,7 +563,11 @@ func (c *Client) GetTasks(ctx context.Context, req *storage.GetTasksRequest) (st
 			&task.MediaLink,
 			pgMap.SQLScanner(&task.MediaLinks),
 			&task.PubTime,
-		); err != nil {
+			&matcherType,
+			&tolerance,
+			&threshold,
+		}
+		if err := rows.Scan(append(dest, append(limits.dest(), scoring.dest(), requires.dest(), &task.Type, choice.dest(), location.dest(), parts.dest(), &task.OrderedHints)...)...); err != nil {
 			return nil, xerrors.Errorf("scan row: %w", err)
 		}
 		if len(task.MediaLinks) == 0 && len(task.MediaLink) > 0 {
@@ -355,6 +576,25 @@ func (c *Client) GetTasks(ctx context.Context, req *storage.GetTasksRequest) (st
 		task.VerificationNew = task.Verification
 		task.Hints = append([]string{}, task.Hints...)
 		task.FullHints = []storage.Hint{}
+		task.Matcher = newMatcher(matcherType, tolerance, threshold)
+		if task.AnswerLimits, err = limits.limits(); err != nil {
+			return nil, xerrors.Errorf("answer limits: %w", err)
+		}
+		if task.Scoring, err = scoring.value(); err != nil {
+			return nil, xerrors.Errorf("scoring: %w", err)
+		}
+		if task.Requires, err = requires.value(); err != nil {
+			return nil, xerrors.Errorf("prerequisites: %w", err)
+		}
+		if task.Choice, err = choice.value(); err != nil {
+			return nil, xerrors.Errorf("choice: %w", err)
+		}
+		if task.Location, err = location.value(); err != nil {
+			return nil, xerrors.Errorf("location: %w", err)
+		}
+		if task.Parts, err = parts.value(); err != nil {
+			return nil, xerrors.Errorf("parts: %w", err)
+		}
 
 		group := tasks[task.Group.ID]
 		group = append(group, task)
@@ -382,9 +622,27 @@ func (c *Client) GetTasks(ctx context.Context, req *storage.GetTasksRequest) (st
 	return tasks, nil
 }
 
+func (c *Client) checkNoPendingReviews(ctx context.Context, taskID storage.ID) error {
+	query := `SELECT EXISTS (SELECT 1 FROM questspace.answer_try WHERE task_id = $1 AND review_status = $2)`
+	var pending bool
+	if err := c.runner.QueryRowContext(ctx, query, taskID, storage.ReviewStatusPending).Scan(&pending); err != nil {
+		return xerrors.Errorf("check pending reviews: %w", err)
+	}
+	if pending {
+		return xerrors.Errorf("task has answers pending review, so its verification cannot become auto: %w", storage.ErrValidation)
+	}
+	return nil
+}
+
 // UpdateTask godoc
 // TODO: unit-tests
 func (c *Client) UpdateTask(ctx context.Context, req *storage.UpdateTaskRequest) (*storage.Task, error) {
+	if req.Verification == storage.VerificationAuto {
+		// answers pending review would never be reviewed after task becomes verified automatically
+		if err := c.checkNoPendingReviews(ctx, req.ID); err != nil {
+			return nil, err
+		}
+	}
 	query := sq.Update("questspace.task").
 		Set("order_idx", req.OrderIdx).
 		Where(sq.Eq{"id": req.ID}).
@@ -398,7 +656,22 @@ func (c *Client) UpdateTask(ctx context.Context, req *storage.UpdateTaskRequest)
 			hints, 
 			media_url, 
 			media_urls, 
-			pub_time`).
+			pub_time,
+			matcher,
+			matcher_tolerance,
+			matcher_threshold,
+			max_attempts,
+			cooldown_attempts,
+			cooldown,
+			wrong_penalty_percent,
+			wrong_penalty_score,
+			scoring,
+			requires,
+			task_type,
+			choice,
+			location,
+			parts,
+			ordered_hints`).
 		PlaceholderFormat(sq.Dollar)
 	if len(req.Name) > 0 {
 		query = query.Set("name", req.Name)
@@ -414,14 +687,9 @@ func (c *Client) UpdateTask(ctx context.Context, req *storage.UpdateTaskRequest)
 	}
 	if len(req.Hints) > 0 {
 		query = query.Set("hints", pgtype.FlatArray[string](req.Hints))
-
-		if req.FullHints == nil {
-			fullHints := make([]storage.CreateHintRequest, 0, len(req.Hints))
-			for _, hintText := range req.Hints {
-				fullHints = append(fullHints, storage.CreateHintRequest{Text: hintText, Penalty: storage.DefaultPenalty})
-			}
-			req.FullHints = &fullHints
-		}
+	}
+	if len(req.Verification) > 0 {
+		query = query.Set("verification", req.Verification)
 	}
 	if req.MediaLink != nil {
 		query = query.Set("media_url", *req.MediaLink)
@@ -432,11 +700,101 @@ func (c *Client) UpdateTask(ctx context.Context, req *storage.UpdateTaskRequest)
 	if req.PubTime != nil {
 		query = query.Set("pub_time", req.PubTime)
 	}
+	if req.Matcher != nil {
+		for i, value := range matcherValues(req.Matcher) {
+			query = query.Set(matcherColumns[i], value)
+		}
+	}
+	if req.AnswerLimits != nil {
+		for i, value := range answerLimitsValues(req.AnswerLimits) {
+			query = query.Set(answerLimitsColumns[i], value)
+		}
+	}
+	if req.Scoring != nil {
+		scoring, err := jsonbValue(req.Scoring)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Set("scoring", scoring)
+	}
+	if req.Requires != nil {
+		requires, err := jsonbValue(req.Requires)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Set("requires", requires)
+	}
+	if len(req.Type) > 0 {
+		query = query.Set("task_type", req.Type)
+	}
+	if req.Choice != nil {
+		choice, err := jsonbValue(req.Choice)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Set("choice", choice)
+	}
+	if req.Location != nil {
+		location, err := jsonbValue(req.Location)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Set("location", location)
+	}
+	if req.Parts != nil {
+		parts, err := jsonbValue(req.Parts)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Set("parts", parts)
+	}
+	if req.OrderedHints != nil {
+		query = query.Set("ordered_hints", *req.OrderedHints)
+	}
+	query, err := setResets(query, req.Reset, taskResetColumns, func(field storage.ResetField) bool {
+		switch field {
+		case storage.ResetQuestion:
+			return len(req.Question) > 0
+		case storage.ResetReward:
+			return req.Reward != 0
+		case storage.ResetCorrectAnswers:
+			return len(req.CorrectAnswers) > 0
+		case storage.ResetMatcher:
+			return req.Matcher != nil
+		case storage.ResetAnswerLimits:
+			return req.AnswerLimits != nil
+		case storage.ResetScoring:
+			return req.Scoring != nil
+		case storage.ResetChoice:
+			return req.Choice != nil
+		case storage.ResetLocation:
+			return req.Location != nil
+		case storage.ResetParts:
+			return req.Parts != nil
+		case storage.ResetPubTime:
+			return req.PubTime != nil
+		case storage.ResetMediaLinks:
+			return req.MediaLink != nil || req.MediaLinks != nil
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	row := query.RunWith(c.runner).QueryRowContext(ctx)
 	task := storage.Task{ID: req.ID}
 	pgMap := pgtype.NewMap()
-	if err := row.Scan(
+	var matcherType *storage.MatcherType
+	var tolerance *float64
+	var threshold *int
+	var limits answerLimitsRow
+	var scoring jsonbRow[*storage.Scoring]
+	var requires jsonbRow[*storage.Prerequisites]
+	var choice jsonbRow[*storage.Choice]
+	var location jsonbRow[*storage.Location]
+	var parts jsonbRow[[]storage.TaskPart]
+	dest := []any{
 		&task.OrderIdx,
 		&task.Name,
 		&task.Question,
@@ -447,7 +805,11 @@ func (c *Client) UpdateTask(ctx context.Context, req *storage.UpdateTaskRequest)
 		&task.MediaLink,
 		pgMap.SQLScanner(&task.MediaLinks),
 		&task.PubTime,
-	); err != nil {
+		&matcherType,
+		&tolerance,
+		&threshold,
+	}
+	if err := row.Scan(append(dest, append(limits.dest(), scoring.dest(), requires.dest(), &task.Type, choice.dest(), location.dest(), parts.dest(), &task.OrderedHints)...)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrNotFound
 		}
@@ -459,9 +821,27 @@ func (c *Client) UpdateTask(ctx context.Context, req *storage.UpdateTaskRequest)
 	task.Hints = append([]string{}, task.Hints...)
 	task.VerificationNew = task.Verification
 	task.FullHints = []storage.Hint{}
+	task.Matcher = newMatcher(matcherType, tolerance, threshold)
+	if task.AnswerLimits, err = limits.limits(); err != nil {
+		return nil, xerrors.Errorf("answer limits: %w", err)
+	}
+	if task.Scoring, err = scoring.value(); err != nil {
+		return nil, xerrors.Errorf("scoring: %w", err)
+	}
+	if task.Requires, err = requires.value(); err != nil {
+		return nil, xerrors.Errorf("prerequisites: %w", err)
+	}
+	if task.Choice, err = choice.value(); err != nil {
+		return nil, xerrors.Errorf("choice: %w", err)
+	}
+	if task.Location, err = location.value(); err != nil {
+		return nil, xerrors.Errorf("location: %w", err)
+	}
+	if task.Parts, err = parts.value(); err != nil {
+		return nil, xerrors.Errorf("parts: %w", err)
+	}
 
 	if req.FullHints != nil {
-		var err error
 		task.FullHints, err = c.updateHints(ctx, task.ID, *req.FullHints)
 		if err != nil {
 			return nil, xerrors.Errorf("update hints: %w", err)
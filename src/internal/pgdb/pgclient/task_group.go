@@ -3,6 +3,7 @@ package pgclient
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"net/http"
 
 	sq "github.com/Masterminds/squirrel"
@@ -15,9 +16,9 @@ import (
 var _ storage.TaskGroupStorage = &Client{}
 
 func (c *Client) CreateTaskGroup(ctx context.Context, req *storage.CreateTaskGroupRequest) (*storage.TaskGroup, error) {
-	values := []interface{}{req.Name, req.OrderIdx, req.Sticky, req.QuestID, req.HasTimeLimit}
+	values := []interface{}{req.Name, req.OrderIdx, req.Sticky, req.QuestID, req.HasTimeLimit, req.AllowSkip}
 	query := sq.Insert("questspace.task_group").
-		Columns("name", "order_idx", "sticky", "quest_id", "has_time_limit").
+		Columns("name", "order_idx", "sticky", "quest_id", "has_time_limit", "allow_skip").
 		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar)
 	if req.PubTime != nil {
@@ -34,6 +35,26 @@ func (c *Client) CreateTaskGroup(ctx context.Context, req *storage.CreateTaskGro
 	} else if req.HasTimeLimit {
 		return nil, httperrors.New(http.StatusBadRequest, "task group has `has_time_limit` without actual time limit")
 	}
+	if req.SkipPenalty != nil {
+		values = append(values, req.SkipPenalty.PercentOpt(), req.SkipPenalty.ScoreOpt())
+		query = query.Columns("skip_penalty_percent", "skip_penalty_score")
+	}
+	if !req.Requires.Empty() {
+		requires, err := jsonbValue(req.Requires)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, requires)
+		query = query.Columns("requires")
+	}
+	if req.Location != nil {
+		location, err := jsonbValue(req.Location)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, location)
+		query = query.Columns("location")
+	}
 	query = query.Values(values...)
 
 	row := query.RunWith(c.runner).QueryRowContext(ctx)
@@ -46,17 +67,28 @@ func (c *Client) CreateTaskGroup(ctx context.Context, req *storage.CreateTaskGro
 		PubTime:      req.PubTime,
 		HasTimeLimit: req.HasTimeLimit,
 		TimeLimit:    req.TimeLimit,
+		AllowSkip:    req.AllowSkip,
+		SkipPenalty:  req.SkipPenalty,
+		Location:     req.Location,
+	}
+	if !req.Requires.Empty() {
+		taskGroup.Requires = req.Requires
 	}
 	if err := row.Scan(&taskGroup.ID); err != nil {
 		return nil, xerrors.Errorf("scan row: %w", err)
 	}
+	var err error
+	if taskGroup.Transitions, err = c.createTransitions(ctx, taskGroup.ID, req.Transitions); err != nil {
+		return nil, xerrors.Errorf("create transitions: %w", err)
+	}
 
 	return &taskGroup, nil
 }
 
 func (c *Client) GetTaskGroup(ctx context.Context, req *storage.GetTaskGroupRequest) (*storage.TaskGroup, error) {
 	query := `
-	SELECT id, name, description, order_idx, sticky, pub_time, quest_id, has_time_limit, time_limit
+	SELECT id, name, description, order_idx, sticky, pub_time, quest_id, has_time_limit, time_limit,
+		allow_skip, skip_penalty_percent, skip_penalty_score, requires, location
 	FROM questspace.task_group
 	WHERE id = $1
 `
@@ -64,6 +96,9 @@ func (c *Client) GetTaskGroup(ctx context.Context, req *storage.GetTaskGroupRequ
 
 	taskGroup := storage.TaskGroup{Quest: &storage.Quest{}}
 	var descr sql.NullString
+	var skipPenalty penaltyRow
+	var requires jsonbRow[*storage.Prerequisites]
+	var location jsonbRow[*storage.Location]
 	if err := row.Scan(
 		&taskGroup.ID,
 		&taskGroup.Name,
@@ -74,12 +109,35 @@ func (c *Client) GetTaskGroup(ctx context.Context, req *storage.GetTaskGroupRequ
 		&taskGroup.Quest.ID,
 		&taskGroup.HasTimeLimit,
 		&taskGroup.TimeLimit,
+		&taskGroup.AllowSkip,
+		&skipPenalty.percent,
+		&skipPenalty.score,
+		requires.dest(),
+		location.dest(),
 	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
 		return nil, xerrors.Errorf("scan row: %w", err)
 	}
 	if descr.Valid {
 		taskGroup.Description = descr.String
 	}
+	var err error
+	if taskGroup.SkipPenalty, err = skipPenalty.penalty(); err != nil {
+		return nil, xerrors.Errorf("skip penalty: %w", err)
+	}
+	if taskGroup.Requires, err = requires.value(); err != nil {
+		return nil, xerrors.Errorf("prerequisites: %w", err)
+	}
+	if taskGroup.Location, err = location.value(); err != nil {
+		return nil, xerrors.Errorf("location: %w", err)
+	}
+	transitions, err := c.getTransitions(ctx, []storage.ID{req.ID})
+	if err != nil {
+		return nil, xerrors.Errorf("get transitions: %w", err)
+	}
+	taskGroup.Transitions = transitions[req.ID]
 	if req.IncludeTasks {
 		tasks, err := c.GetTasks(ctx, &storage.GetTasksRequest{GroupIDs: []storage.ID{req.ID}})
 		if err != nil {
@@ -91,7 +149,12 @@ func (c *Client) GetTaskGroup(ctx context.Context, req *storage.GetTaskGroupRequ
 		}
 	}
 	if req.TeamData != nil {
-		teamInfos, err := c.fillTeamInfos(ctx, []storage.TaskGroup{taskGroup}, *req.TeamData)
+		// opening of linear task group depends on the groups team has passed before
+		questTaskGroups, err := c.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: taskGroup.Quest.ID})
+		if err != nil {
+			return nil, xerrors.Errorf("get quest task groups: %w", err)
+		}
+		teamInfos, err := c.fillTeamInfos(ctx, questTaskGroups, *req.TeamData)
 		if err != nil {
 			return nil, xerrors.Errorf("get team info: %w", err)
 		}
@@ -102,7 +165,21 @@ func (c *Client) GetTaskGroup(ctx context.Context, req *storage.GetTaskGroupRequ
 }
 
 func (c *Client) GetTaskGroups(ctx context.Context, req *storage.GetTaskGroupsRequest) ([]storage.TaskGroup, error) {
-	query := sq.Select("id", "name", "description", "order_idx", "sticky", "pub_time", "has_time_limit", "time_limit").
+	query := sq.Select(
+		"id",
+		"name",
+		"description",
+		"order_idx",
+		"sticky",
+		"pub_time",
+		"has_time_limit",
+		"time_limit",
+		"allow_skip",
+		"skip_penalty_percent",
+		"skip_penalty_score",
+		"requires",
+		"location",
+	).
 		From("questspace.task_group").
 		OrderBy("order_idx").
 		PlaceholderFormat(sq.Dollar)
@@ -123,6 +200,9 @@ func (c *Client) GetTaskGroups(ctx context.Context, req *storage.GetTaskGroupsRe
 	var groupIDs []storage.ID
 	for rows.Next() {
 		var descr sql.NullString
+		var skipPenalty penaltyRow
+		var requires jsonbRow[*storage.Prerequisites]
+		var location jsonbRow[*storage.Location]
 		if err := rows.Err(); err != nil {
 			return nil, xerrors.Errorf("iter rows: %w", err)
 		}
@@ -136,12 +216,26 @@ func (c *Client) GetTaskGroups(ctx context.Context, req *storage.GetTaskGroupsRe
 			&taskGroup.PubTime,
 			&taskGroup.HasTimeLimit,
 			&taskGroup.TimeLimit,
+			&taskGroup.AllowSkip,
+			&skipPenalty.percent,
+			&skipPenalty.score,
+			requires.dest(),
+			location.dest(),
 		); err != nil {
 			return nil, xerrors.Errorf("scan row: %w", err)
 		}
 		if descr.Valid {
 			taskGroup.Description = descr.String
 		}
+		if taskGroup.SkipPenalty, err = skipPenalty.penalty(); err != nil {
+			return nil, xerrors.Errorf("skip penalty: %w", err)
+		}
+		if taskGroup.Requires, err = requires.value(); err != nil {
+			return nil, xerrors.Errorf("prerequisites: %w", err)
+		}
+		if taskGroup.Location, err = location.value(); err != nil {
+			return nil, xerrors.Errorf("location: %w", err)
+		}
 		taskGroups = append(taskGroups, taskGroup)
 		groupIDs = append(groupIDs, taskGroup.ID)
 	}
@@ -149,6 +243,13 @@ func (c *Client) GetTaskGroups(ctx context.Context, req *storage.GetTaskGroupsRe
 		return nil, xerrors.Errorf("iter rows: %w", err)
 	}
 
+	transitions, err := c.getTransitions(ctx, groupIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get transitions: %w", err)
+	}
+	for i := range len(taskGroups) {
+		taskGroups[i].Transitions = transitions[taskGroups[i].ID]
+	}
 	if req.IncludeTasks {
 		tasks, err := c.GetTasks(ctx, &storage.GetTasksRequest{GroupIDs: groupIDs})
 		if err != nil {
@@ -181,7 +282,7 @@ func (c *Client) UpdateTaskGroup(ctx context.Context, req *storage.UpdateTaskGro
 	query := sq.Update("questspace.task_group").
 		Where(sq.Eq{"id": req.ID}).
 		Set("order_idx", req.OrderIdx).
-		Suffix("RETURNING id, name, order_idx, pub_time, quest_id, has_time_limit, time_limit").
+		Suffix("RETURNING id, name, order_idx, pub_time, quest_id, has_time_limit, time_limit, allow_skip, skip_penalty_percent, skip_penalty_score, requires, location").
 		PlaceholderFormat(sq.Dollar)
 	if len(req.Name) > 0 {
 		query = query.Set("name", req.Name)
@@ -201,9 +302,49 @@ func (c *Client) UpdateTaskGroup(ctx context.Context, req *storage.UpdateTaskGro
 	if req.TimeLimit != nil {
 		query = query.Set("time_limit", *req.TimeLimit)
 	}
+	if req.AllowSkip != nil {
+		query = query.Set("allow_skip", *req.AllowSkip)
+	}
+	if req.SkipPenalty != nil {
+		query = query.Set("skip_penalty_percent", req.SkipPenalty.PercentOpt()).
+			Set("skip_penalty_score", req.SkipPenalty.ScoreOpt())
+	}
+	if req.Requires != nil {
+		requires, err := jsonbValue(req.Requires)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Set("requires", requires)
+	}
+	if req.Location != nil {
+		location, err := jsonbValue(req.Location)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Set("location", location)
+	}
+	query, err := setResets(query, req.Reset, taskGroupResetColumns, func(field storage.ResetField) bool {
+		switch field {
+		case storage.ResetPubTime:
+			return req.PubTime != nil
+		case storage.ResetTimeLimit:
+			return req.TimeLimit != nil
+		case storage.ResetSkipPenalty:
+			return req.SkipPenalty != nil
+		case storage.ResetLocation:
+			return req.Location != nil
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	row := query.RunWith(c.runner).QueryRowContext(ctx)
 	taskGroup := storage.TaskGroup{Quest: &storage.Quest{}}
+	var skipPenalty penaltyRow
+	var requires jsonbRow[*storage.Prerequisites]
+	var location jsonbRow[*storage.Location]
 	if err := row.Scan(
 		&taskGroup.ID,
 		&taskGroup.Name,
@@ -212,9 +353,28 @@ func (c *Client) UpdateTaskGroup(ctx context.Context, req *storage.UpdateTaskGro
 		&taskGroup.Quest.ID,
 		&taskGroup.HasTimeLimit,
 		&taskGroup.TimeLimit,
+		&taskGroup.AllowSkip,
+		&skipPenalty.percent,
+		&skipPenalty.score,
+		requires.dest(),
+		location.dest(),
 	); err != nil {
 		return nil, xerrors.Errorf("scan row: %w", err)
 	}
+	if taskGroup.SkipPenalty, err = skipPenalty.penalty(); err != nil {
+		return nil, xerrors.Errorf("skip penalty: %w", err)
+	}
+	if taskGroup.Requires, err = requires.value(); err != nil {
+		return nil, xerrors.Errorf("prerequisites: %w", err)
+	}
+	if taskGroup.Location, err = location.value(); err != nil {
+		return nil, xerrors.Errorf("location: %w", err)
+	}
+	if req.Transitions != nil {
+		if taskGroup.Transitions, err = c.updateTransitions(ctx, taskGroup.ID, *req.Transitions); err != nil {
+			return nil, xerrors.Errorf("update transitions: %w", err)
+		}
+	}
 
 	return &taskGroup, nil
 }
@@ -229,3 +389,25 @@ func (c *Client) DeleteTaskGroup(ctx context.Context, req *storage.DeleteTaskGro
 	}
 	return nil
 }
+
+// penaltyRow reads penalty stored in percent and score columns, only one of which is set
+type penaltyRow struct {
+	percent *int
+	score   *int
+}
+
+func (r *penaltyRow) penalty() (*storage.PenaltyOneOf, error) {
+	switch {
+	case r.percent != nil:
+		penalty, err := storage.NewPercentagePenalty(*r.percent)
+		if err != nil {
+			return nil, xerrors.Errorf("bad penalty: %w", err)
+		}
+		return &penalty, nil
+	case r.score != nil:
+		penalty := storage.NewScorePenalty(*r.score)
+		return &penalty, nil
+	default:
+		return nil, nil
+	}
+}
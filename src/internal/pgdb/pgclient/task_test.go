@@ -155,3 +155,78 @@ func TestTaskStorage_GetTasks(t *testing.T) {
 	assert.Equal(t, []storage.Task{*task2, *task1}, allTasks[tg1.ID])
 	assert.Equal(t, []storage.Task{*task3}, allTasks[tg2.ID])
 }
+
+func TestTaskStorage_UpdateTask_PendingReviews(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+	})
+	require.NoError(t, err)
+
+	taskReq1 := taskReq
+	taskReq1.GroupID = tg.ID
+	taskReq1.Verification = storage.VerificationManual
+	task, err := client.CreateTask(ctx, &taskReq1)
+	require.NoError(t, err)
+
+	team, user := createTestTeam(t, ctx, client, quest, "svayp22", "team1")
+	require.NoError(t, client.CreateAnswerTry(ctx, &storage.CreateAnswerTryRequest{
+		Text:         "some answer",
+		TaskID:       task.ID,
+		TeamID:       team.ID,
+		UserID:       user.ID,
+		ReviewStatus: storage.ReviewStatusPending,
+	}))
+
+	updateReq := storage.UpdateTaskRequest{ID: task.ID, OrderIdx: task.OrderIdx, Verification: storage.VerificationAuto}
+	_, err = client.UpdateTask(ctx, &updateReq)
+	require.ErrorIs(t, err, storage.ErrValidation)
+
+	pending, err := client.GetReviewAnswerTries(ctx, &storage.GetReviewAnswerTriesRequest{QuestID: quest.ID, OnlyPending: true})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.NoError(t, client.ReviewAnswerTry(ctx, &storage.ReviewAnswerTryRequest{ID: pending[0].ID, ReviewStatus: storage.ReviewStatusRejected}))
+
+	updated, err := client.UpdateTask(ctx, &updateReq)
+	require.NoError(t, err)
+	assert.Equal(t, storage.VerificationAuto, updated.Verification)
+}
+
+func TestTaskStorage_UpdateTask_ExactMatcher(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+	})
+	require.NoError(t, err)
+
+	tolerance := 0.5
+	taskReq1 := taskReq
+	taskReq1.GroupID = tg.ID
+	taskReq1.Matcher = &storage.AnswerMatcher{Type: storage.MatcherNumeric, Tolerance: &tolerance}
+	task, err := client.CreateTask(ctx, &taskReq1)
+	require.NoError(t, err)
+	require.Equal(t, taskReq1.Matcher, task.Matcher)
+
+	updated, err := client.UpdateTask(ctx, &storage.UpdateTaskRequest{
+		ID:           task.ID,
+		OrderIdx:     task.OrderIdx,
+		Verification: task.Verification,
+		Matcher:      &storage.AnswerMatcher{Type: storage.MatcherExact},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, updated.Matcher)
+
+	got, err := client.GetTask(ctx, &storage.GetTaskRequest{ID: task.ID})
+	require.NoError(t, err)
+	assert.Nil(t, got.Matcher)
+}
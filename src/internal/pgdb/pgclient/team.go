@@ -85,11 +85,16 @@ func (c *Client) GetTeam(ctx context.Context, req *storage.GetTeamRequest) (*sto
 		"q.max_teams_amount",
 		"q.registration_type",
 		"q.quest_type",
+		"q.registration_deadline",
+		"q.start_time",
+		"q.finish_time",
+		"q.finished",
 		"u.id",
 		"u.username",
 		"u.avatar_url",
 		"cr.id",
 	).
+		Columns(prefixed("q.", answerLimitsColumns)...).
 		From("questspace.team t").
 		LeftJoin("questspace.quest q ON q.id = t.quest_id").
 		LeftJoin("questspace.user u ON t.cap_id = u.id").
@@ -116,7 +121,11 @@ func (c *Client) GetTeam(ctx context.Context, req *storage.GetTeamRequest) (*sto
 
 	row := query.RunWith(c.runner).QueryRowContext(ctx)
 	team := &storage.Team{Quest: &storage.Quest{Creator: &storage.User{}}, Captain: &storage.User{}}
-	if err := row.Scan(
+	var (
+		limits   answerLimitsRow
+		finished bool
+	)
+	dest := []any{
 		&team.ID,
 		&team.Name,
 		&team.InviteLink,
@@ -127,18 +136,29 @@ func (c *Client) GetTeam(ctx context.Context, req *storage.GetTeamRequest) (*sto
 		&team.Quest.MaxTeamsAmount,
 		&team.Quest.RegistrationType,
 		&team.Quest.QuestType,
+		&team.Quest.RegistrationDeadline,
+		&team.Quest.StartTime,
+		&team.Quest.FinishTime,
+		&finished,
 		&team.Captain.ID,
 		&team.Captain.Username,
 		&team.Captain.AvatarURL,
 		&team.Quest.Creator.ID,
-	); err != nil {
+	}
+	if err := row.Scan(append(dest, limits.dest()...)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrNotFound
 		}
 		return nil, xerrors.Errorf("scan row: %w", err)
 	}
+	var err error
+	if team.Quest.AnswerLimits, err = limits.limits(); err != nil {
+		return nil, xerrors.Errorf("answer limits: %w", err)
+	}
+	if finished {
+		team.Quest.Status = storage.StatusFinished
+	}
 	if req.IncludeMembers {
-		var err error
 		team.Members, err = c.getTeamMembers(ctx, team.ID)
 		if err != nil {
 			return nil, xerrors.Errorf("get team members: %w", err)
@@ -13,19 +13,33 @@ import (
 
 func (c *Client) UpsertTeamInfo(ctx context.Context, req *storage.UpsertTeamInfoRequest) (*storage.TaskGroupTeamInfo, error) {
 	query := `
-	INSERT INTO questspace.task_group_team_info (team_id, group_id, opening_time, closing_time)
-	VALUES ($1, $2, $3, $4) 
-	ON CONFLICT (team_id, group_id) DO UPDATE SET opening_time = $3, closing_time = $4
+	INSERT INTO questspace.task_group_team_info (team_id, group_id, opening_time, closing_time, next_group_id)
+	VALUES ($1, $2, $3, $4, $5) 
+	ON CONFLICT (team_id, group_id) DO UPDATE SET opening_time = $3, closing_time = $4, next_group_id = $5
 	`
+	if req.OnlyOpen {
+		// concurrent update waits for the row and rechecks the condition, so only one of them closes the group
+		query += ` WHERE task_group_team_info.closing_time IS NULL`
+	}
 
-	_, err := c.runner.ExecContext(ctx, query, req.TeamID, req.TaskGroupID, req.OpeningTime, req.ClosingTime)
+	res, err := c.runner.ExecContext(ctx, query, req.TeamID, req.TaskGroupID, req.OpeningTime, req.ClosingTime, req.NextGroupID)
 	if err != nil {
 		return nil, err
 	}
+	if req.OnlyOpen {
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, xerrors.Errorf("get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return nil, storage.ErrNotFound
+		}
+	}
 
 	resp := &storage.TaskGroupTeamInfo{
 		OpeningTime: req.OpeningTime,
 		ClosingTime: req.ClosingTime,
+		NextGroupID: req.NextGroupID,
 	}
 	return resp, nil
 }
@@ -70,7 +84,7 @@ func (c *Client) fillTeamInfos(ctx context.Context, taskGroups []storage.TaskGro
 		tgIDs = append(tgIDs, tg.ID)
 	}
 
-	query := sq.Select("tg.id", "ti.opening_time", "ti.closing_time").
+	query := sq.Select("tg.id", "ti.opening_time", "ti.closing_time", "ti.next_group_id").
 		From("questspace.task_group_team_info ti").
 		LeftJoin("questspace.task_group tg ON ti.group_id = tg.id").
 		Where(sq.Eq{"tg.id": tgIDs}).
@@ -95,12 +109,16 @@ func (c *Client) fillTeamInfos(ctx context.Context, taskGroups []storage.TaskGro
 	groupToTeams := make(map[storage.ID]*storage.TaskGroupTeamInfo, len(taskGroups))
 	var openingTime, closingTime sql.NullTime
 	var tgID storage.ID
+	// routedOpenings contains opening times of groups chosen by transitions of closed groups
+	routedOpenings := make(map[storage.ID]time.Time)
 	for rows.Next() {
 		var teamInfo *storage.TaskGroupTeamInfo
+		var nextGroupID *storage.ID
 		if err = rows.Scan(
 			&tgID,
 			&openingTime,
 			&closingTime,
+			&nextGroupID,
 		); err != nil {
 			return nil, xerrors.Errorf("scan rows: %w", err)
 		}
@@ -112,6 +130,10 @@ func (c *Client) fillTeamInfos(ctx context.Context, taskGroups []storage.TaskGro
 		}
 		if closingTime.Valid {
 			teamInfo.ClosingTime = &closingTime.Time
+			if nextGroupID != nil {
+				teamInfo.NextGroupID = nextGroupID
+				routedOpenings[*nextGroupID] = closingTime.Time
+			}
 		}
 
 		groupToTeams[tgID] = teamInfo
@@ -139,7 +161,11 @@ func (c *Client) fillTeamInfos(ctx context.Context, taskGroups []storage.TaskGro
 		}
 
 		ti, ok := groupToTeams[tg.ID]
-		if !ok && prevClosingTime != nil {
+		if routedOpening, routed := routedOpenings[tg.ID]; !ok && routed {
+			ti = &storage.TaskGroupTeamInfo{
+				OpeningTime: routedOpening,
+			}
+		} else if !ok && prevClosingTime != nil {
 			ti = &storage.TaskGroupTeamInfo{
 				OpeningTime: *prevClosingTime,
 			}
@@ -158,6 +184,10 @@ func (c *Client) fillTeamInfos(ctx context.Context, taskGroups []storage.TaskGro
 
 		if ti.ClosingTime != nil {
 			prevClosingTime = ti.ClosingTime
+			if ti.NextGroupID != nil {
+				// team follows the transition instead of the next group by order
+				prevClosingTime = nil
+			}
 		}
 
 		groupToTeamInfo[tg.ID] = ti
@@ -93,3 +93,112 @@ func TestGetTeamInfos(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, tg.TeamInfo)
 }
+
+func TestGetTeamInfos_Route(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	var taskGroups []*storage.TaskGroup
+	for i, name := range []string{"fork", "left", "right"} {
+		tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+			Name:     name,
+			OrderIdx: i,
+			QuestID:  quest.ID,
+		})
+		require.NoError(t, err)
+		taskGroups = append(taskGroups, tg)
+	}
+	fork, right := taskGroups[0], taskGroups[2]
+	transitions := []storage.Transition{{NextGroupID: right.ID}}
+	_, err := client.UpdateTaskGroup(ctx, &storage.UpdateTaskGroupRequest{ID: fork.ID, Transitions: &transitions})
+	require.NoError(t, err)
+
+	user, err := client.CreateUser(ctx, &storage.CreateUserRequest{
+		Username:  "sv11",
+		Password:  "123",
+		AvatarURL: "123",
+	})
+	require.NoError(t, err)
+	team, err := client.CreateTeam(ctx, &storage.CreateTeamRequest{
+		Name:               "team",
+		QuestID:            quest.ID,
+		Creator:            user,
+		RegistrationStatus: storage.RegistrationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	_, err = client.UpsertTeamInfo(ctx, &storage.UpsertTeamInfoRequest{
+		TeamID:      team.ID,
+		TaskGroupID: fork.ID,
+		OpeningTime: *quest.StartTime,
+		ClosingTime: &now,
+		NextGroupID: &right.ID,
+	})
+	require.NoError(t, err)
+
+	tgs, err := client.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{
+		QuestID:  quest.ID,
+		TeamData: &storage.TeamData{TeamID: &team.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, tgs, 3)
+	assert.Equal(t, transitions, tgs[0].Transitions)
+	assert.Equal(t, &right.ID, tgs[0].TeamInfo.NextGroupID)
+	assert.Nil(t, tgs[1].TeamInfo)
+	require.NotNil(t, tgs[2].TeamInfo)
+	assert.Equal(t, now.Unix(), tgs[2].TeamInfo.OpeningTime.Unix())
+}
+
+func TestUpsertTeamInfo_OnlyOpen(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(pgtest.NewEmbeddedQuestspaceDB(t))
+
+	quest := createTestQuest(t, ctx, client, "svayp11", "quest1")
+	tg, err := client.CreateTaskGroup(ctx, &storage.CreateTaskGroupRequest{
+		Name:     "tg1",
+		OrderIdx: 0,
+		QuestID:  quest.ID,
+		PubTime:  ptr.Time(time.Now()),
+	})
+	require.NoError(t, err)
+	user, err := client.CreateUser(ctx, &storage.CreateUserRequest{
+		Username:  "sv11",
+		Password:  "123",
+		AvatarURL: "123",
+	})
+	require.NoError(t, err)
+	team, err := client.CreateTeam(ctx, &storage.CreateTeamRequest{
+		Name:               "team",
+		QuestID:            quest.ID,
+		Creator:            user,
+		RegistrationStatus: storage.RegistrationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	req := &storage.UpsertTeamInfoRequest{
+		TeamID:      team.ID,
+		TaskGroupID: tg.ID,
+		OpeningTime: *quest.StartTime,
+		ClosingTime: &now,
+		OnlyOpen:    true,
+	}
+	_, err = client.UpsertTeamInfo(ctx, req)
+	require.NoError(t, err)
+
+	later := now.Add(time.Minute)
+	req.ClosingTime = &later
+	_, err = client.UpsertTeamInfo(ctx, req)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	tgs, err := client.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{
+		QuestID:  quest.ID,
+		TeamData: &storage.TeamData{TeamID: &team.ID},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, tgs[0].TeamInfo)
+	require.NotNil(t, tgs[0].TeamInfo.ClosingTime)
+	assert.Equal(t, now.Unix(), tgs[0].TeamInfo.ClosingTime.Unix())
+}
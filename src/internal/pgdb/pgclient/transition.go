@@ -0,0 +1,87 @@
+package pgclient
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/storage"
+)
+
+func (c *Client) createTransitions(ctx context.Context, groupID storage.ID, transitions []storage.Transition) ([]storage.Transition, error) {
+	if len(transitions) == 0 {
+		return nil, nil
+	}
+	query := sq.Insert("questspace.task_group_transition").
+		Columns("group_id", "index", "next_group_id", "task_id", "answers").
+		PlaceholderFormat(sq.Dollar)
+	for i, t := range transitions {
+		var taskID *storage.ID
+		if len(t.TaskID) > 0 {
+			taskID = &t.TaskID
+		}
+		query = query.Values(groupID, i, t.NextGroupID, taskID, pgtype.FlatArray[string](t.Answers))
+	}
+	if _, err := query.RunWith(c.runner).ExecContext(ctx); err != nil {
+		return nil, xerrors.Errorf("exec query: %w", err)
+	}
+	return transitions, nil
+}
+
+func (c *Client) getTransitions(ctx context.Context, groupIDs []storage.ID) (map[storage.ID][]storage.Transition, error) {
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+	query := sq.Select("group_id", "next_group_id", "task_id", "answers").
+		From("questspace.task_group_transition").
+		Where(sq.Eq{"group_id": groupIDs}).
+		OrderBy("index").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := query.RunWith(c.runner).QueryContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("query rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	pgMap := pgtype.NewMap()
+	transitionsByGroupID := make(map[storage.ID][]storage.Transition, len(groupIDs))
+	for rows.Next() {
+		var (
+			groupID    storage.ID
+			taskID     *storage.ID
+			transition storage.Transition
+		)
+		if err = rows.Scan(
+			&groupID,
+			&transition.NextGroupID,
+			&taskID,
+			pgMap.SQLScanner(&transition.Answers),
+		); err != nil {
+			return nil, xerrors.Errorf("scan row: %w", err)
+		}
+		if taskID != nil {
+			transition.TaskID = *taskID
+		}
+		if len(transition.Answers) == 0 {
+			transition.Answers = nil
+		}
+		transitionsByGroupID[groupID] = append(transitionsByGroupID[groupID], transition)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iter rows: %w", err)
+	}
+
+	return transitionsByGroupID, nil
+}
+
+func (c *Client) updateTransitions(ctx context.Context, groupID storage.ID, transitions []storage.Transition) ([]storage.Transition, error) {
+	const query = `DELETE FROM questspace.task_group_transition WHERE group_id = $1`
+	if _, err := c.runner.ExecContext(ctx, query, groupID); err != nil {
+		return nil, xerrors.Errorf("delete previous transitions: %w", err)
+	}
+
+	return c.createTransitions(ctx, groupID, transitions)
+}
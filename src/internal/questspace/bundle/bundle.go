@@ -0,0 +1,247 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// Version of bundle format. Bundles of other versions are rejected on import
+const Version = 1
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+func (f Format) ContentType() string {
+	if f == FormatYAML {
+		return "application/yaml"
+	}
+	return "application/json"
+}
+
+// Bundle is a portable content of quest. Groups and tasks are referenced by their keys,
+// which are ids of groups and tasks in exported bundle and any unique strings in hand-written one.
+type Bundle struct {
+	Version    int                        `json:"version"`
+	Quest      storage.CreateQuestRequest `json:"quest"`
+	TaskGroups []TaskGroup                `json:"task_groups"`
+}
+
+type TaskGroup struct {
+	Key          string                `json:"key,omitempty"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description,omitempty"`
+	PubTime      *time.Time            `json:"pub_time,omitempty"`
+	Sticky       bool                  `json:"sticky,omitempty"`
+	HasTimeLimit bool                  `json:"has_time_limit,omitempty"`
+	TimeLimit    *storage.Duration     `json:"time_limit,omitempty" swaggertype:"integer" example:"300"`
+	AllowSkip    bool                  `json:"allow_skip,omitempty"`
+	SkipPenalty  *storage.PenaltyOneOf `json:"skip_penalty,omitempty"`
+	Location     *storage.Location     `json:"location,omitempty"`
+	Transitions  []Transition          `json:"transitions,omitempty"`
+	Requires     *Prerequisites        `json:"requires,omitempty"`
+	Tasks        []Task                `json:"tasks"`
+}
+
+type Task struct {
+	Key            string                      `json:"key,omitempty"`
+	Name           string                      `json:"name"`
+	Question       string                      `json:"question"`
+	Reward         int                         `json:"reward"`
+	CorrectAnswers []string                    `json:"correct_answers,omitempty"`
+	Matcher        *storage.AnswerMatcher      `json:"matcher,omitempty"`
+	AnswerLimits   *storage.AnswerLimits       `json:"answer_limits,omitempty"`
+	Scoring        *storage.Scoring            `json:"scoring,omitempty"`
+	Requires       *Prerequisites              `json:"requires,omitempty"`
+	Type           storage.TaskType            `json:"type,omitempty" enums:"text,choice,file,location,parts"`
+	Choice         *storage.Choice             `json:"choice,omitempty"`
+	Location       *storage.Location           `json:"location,omitempty"`
+	Parts          []storage.TaskPart          `json:"parts,omitempty"`
+	OrderedHints   bool                        `json:"ordered_hints,omitempty"`
+	Verification   storage.VerificationType    `json:"verification,omitempty" enums:"auto,manual"`
+	Hints          []storage.CreateHintRequest `json:"hints,omitempty"`
+	PubTime        *time.Time                  `json:"pub_time,omitempty"`
+	MediaLinks     []string                    `json:"media_links,omitempty"`
+}
+
+// Transition leads to group with NextGroup key, when task with Task key is solved
+type Transition struct {
+	NextGroup string   `json:"next_group"`
+	Task      string   `json:"task,omitempty"`
+	Answers   []string `json:"answers,omitempty"`
+}
+
+type Prerequisites struct {
+	SolvedTasks    []string        `json:"solved_tasks,omitempty"`
+	SolvedInGroups []SolvedInGroup `json:"solved_in_groups,omitempty"`
+}
+
+type SolvedInGroup struct {
+	Group string `json:"group"`
+	Count int    `json:"count"`
+}
+
+// Export returns bundle with content of quest and its task groups with tasks
+func Export(q *storage.Quest, taskGroups []storage.TaskGroup) *Bundle {
+	b := &Bundle{
+		Version: Version,
+		Quest: storage.CreateQuestRequest{
+			Name:                 q.Name,
+			Description:          q.Description,
+			Access:               q.Access,
+			RegistrationDeadline: q.RegistrationDeadline,
+			StartTime:            q.StartTime,
+			FinishTime:           q.FinishTime,
+			MediaLink:            q.MediaLink,
+			MaxTeamCap:           q.MaxTeamCap,
+			HasBrief:             q.HasBrief,
+			Brief:                q.Brief,
+			MaxTeamsAmount:       q.MaxTeamsAmount,
+			RegistrationType:     q.RegistrationType,
+			QuestType:            q.QuestType,
+			FeedbackLink:         q.FeedbackLink,
+			AnswerLimits:         q.AnswerLimits,
+			LeaderboardFreeze:    q.LeaderboardFreeze,
+			DefaultHintPenalty:   q.DefaultHintPenalty,
+			AutoFinish:           q.AutoFinish,
+		},
+		TaskGroups: make([]TaskGroup, 0, len(taskGroups)),
+	}
+	for _, tg := range taskGroups {
+		group := TaskGroup{
+			Key:          tg.ID.String(),
+			Name:         tg.Name,
+			Description:  tg.Description,
+			PubTime:      tg.PubTime,
+			Sticky:       tg.Sticky,
+			HasTimeLimit: tg.HasTimeLimit,
+			TimeLimit:    tg.TimeLimit,
+			AllowSkip:    tg.AllowSkip,
+			SkipPenalty:  tg.SkipPenalty,
+			Location:     tg.Location,
+			Requires:     exportPrerequisites(tg.Requires),
+			Tasks:        make([]Task, 0, len(tg.Tasks)),
+		}
+		for _, t := range tg.Transitions {
+			group.Transitions = append(group.Transitions, Transition{NextGroup: t.NextGroupID.String(), Task: t.TaskID.String(), Answers: t.Answers})
+		}
+		for _, t := range tg.Tasks {
+			task := Task{
+				Key:            t.ID.String(),
+				Name:           t.Name,
+				Question:       t.Question,
+				Reward:         t.Reward,
+				CorrectAnswers: t.CorrectAnswers,
+				Matcher:        t.Matcher,
+				AnswerLimits:   t.AnswerLimits,
+				Scoring:        t.Scoring,
+				Requires:       exportPrerequisites(t.Requires),
+				Type:           t.Type,
+				Choice:         t.Choice,
+				Location:       t.Location,
+				Parts:          t.Parts,
+				OrderedHints:   t.OrderedHints,
+				Verification:   t.VerificationNew,
+				PubTime:        t.PubTime,
+				MediaLinks:     t.MediaLinks,
+			}
+			if len(task.MediaLinks) == 0 && len(t.MediaLink) > 0 {
+				task.MediaLinks = []string{t.MediaLink}
+			}
+			for _, h := range t.FullHints {
+				task.Hints = append(task.Hints, storage.CreateHintRequest{
+					Name:        h.Name,
+					Text:        h.Text,
+					Penalty:     h.Penalty,
+					OpensAfter:  h.OpensAfter,
+					AutoPenalty: h.AutoPenalty,
+				})
+			}
+			group.Tasks = append(group.Tasks, task)
+		}
+		b.TaskGroups = append(b.TaskGroups, group)
+	}
+	return b
+}
+
+func exportPrerequisites(p *storage.Prerequisites) *Prerequisites {
+	if p.Empty() {
+		return nil
+	}
+	res := &Prerequisites{}
+	for _, taskID := range p.SolvedTasks {
+		res.SolvedTasks = append(res.SolvedTasks, taskID.String())
+	}
+	for _, solved := range p.SolvedInGroups {
+		res.SolvedInGroups = append(res.SolvedInGroups, SolvedInGroup{Group: solved.TaskGroupID.String(), Count: solved.Count})
+	}
+	return res
+}
+
+// Encode marshals bundle in format. YAML document has the same fields as JSON one
+func Encode(b *Bundle, format Format) ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, xerrors.Errorf("marshal json: %w", err)
+	}
+	if format != FormatYAML {
+		return data, nil
+	}
+
+	// JSON document is valid YAML, so it is parsed into nodes keeping order of fields and printed in block style
+	var doc yaml.Node
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return nil, xerrors.Errorf("parse json as yaml: %w", err)
+	}
+	resetStyle(&doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&doc); err != nil {
+		return nil, xerrors.Errorf("marshal yaml: %w", err)
+	}
+	if err = enc.Close(); err != nil {
+		return nil, xerrors.Errorf("close yaml encoder: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, child := range n.Content {
+		resetStyle(child)
+	}
+}
+
+// Decode unmarshals bundle in format. Unknown fields are rejected, so that typos do not silently drop content
+func Decode(data []byte, format Format) (*Bundle, error) {
+	if format == FormatYAML {
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, httperrors.Errorf(http.StatusBadRequest, "parse yaml: %w", err)
+		}
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, httperrors.Errorf(http.StatusBadRequest, "convert yaml to json: %w", err)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var b Bundle
+	if err := dec.Decode(&b); err != nil {
+		return nil, httperrors.Errorf(http.StatusBadRequest, "parse bundle: %w", err)
+	}
+	return &b, nil
+}
@@ -0,0 +1,501 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/internal/questspace/taskgroups"
+	"questspace/internal/questspace/taskgroups/requests"
+	"questspace/internal/questspace/tasks"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+	storagemock "questspace/pkg/storage/mocks"
+)
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+	startTime := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	timeLimit := storage.Duration(5 * time.Minute)
+	opensAfter := storage.Duration(10 * time.Minute)
+	skipPenalty, err := storage.NewPercentagePenalty(50)
+	require.NoError(t, err)
+	hintPenalty := storage.NewScorePenalty(3)
+
+	return &Bundle{
+		Version: Version,
+		Quest: storage.CreateQuestRequest{
+			Name:               "Quest",
+			Access:             storage.AccessPublic,
+			StartTime:          &startTime,
+			QuestType:          storage.TypeLinear,
+			DefaultHintPenalty: &hintPenalty,
+		},
+		TaskGroups: []TaskGroup{
+			{
+				Key:          "A",
+				Name:         "First",
+				HasTimeLimit: true,
+				TimeLimit:    &timeLimit,
+				AllowSkip:    true,
+				SkipPenalty:  &skipPenalty,
+				Transitions:  []Transition{{NextGroup: "B", Task: "a", Answers: []string{"left"}}},
+				Tasks: []Task{{
+					Key:            "a",
+					Name:           "a",
+					Question:       "Where to go?",
+					Reward:         10,
+					CorrectAnswers: []string{"left", "right"},
+					Hints: []storage.CreateHintRequest{
+						{Text: "look around", Penalty: storage.NewScorePenalty(5), OpensAfter: &opensAfter},
+						{Name: ptr.String("last"), Text: "go left", Penalty: skipPenalty},
+					},
+				}},
+			},
+			{
+				Key:      "B",
+				Name:     "Second",
+				Requires: &Prerequisites{SolvedInGroups: []SolvedInGroup{{Group: "A", Count: 1}}},
+				Tasks: []Task{{
+					Key:            "b",
+					Name:           "b",
+					Question:       "Which floor?",
+					Reward:         20,
+					CorrectAnswers: []string{"123"},
+					Requires:       &Prerequisites{SolvedTasks: []string{"a"}},
+				}},
+			},
+			{
+				Key:    "C",
+				Name:   "Bonus",
+				Sticky: true,
+			},
+		},
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			b := newTestBundle(t)
+			data, err := Encode(b, format)
+			require.NoError(t, err)
+
+			decoded, err := Decode(data, format)
+			require.NoError(t, err)
+			assert.Equal(t, b, decoded)
+		})
+	}
+}
+
+func TestEncode_YAML(t *testing.T) {
+	data, err := Encode(newTestBundle(t), FormatYAML)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "version: 1\nquest:\n  name: Quest\n")
+	assert.Contains(t, string(data), "time_limit: 300\n")
+	assert.Contains(t, string(data), "skip_penalty:\n      percent: 50\n")
+	assert.Contains(t, string(data), "- \"123\"\n")
+}
+
+func TestDecode_UnknownField(t *testing.T) {
+	_, err := Decode([]byte("version: 1\nquest:\n  name: Quest\n  nmae: Typo\n"), FormatYAML)
+	var httpErr *httperrors.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(b *Bundle)
+		errMsg string
+	}{
+		{
+			name:   "ok",
+			modify: func(b *Bundle) {},
+		},
+		{
+			name:   "unsupported version",
+			modify: func(b *Bundle) { b.Version = 2 },
+			errMsg: "unsupported bundle version 2",
+		},
+		{
+			name:   "no start time",
+			modify: func(b *Bundle) { b.Quest.StartTime = nil },
+			errMsg: "quest start time is required",
+		},
+		{
+			name:   "unknown task type",
+			modify: func(b *Bundle) { b.TaskGroups[1].Tasks[0].Type = "riddle" },
+			errMsg: `task #1 of task group #2: unknown type "riddle"`,
+		},
+		{
+			name:   "duplicate key",
+			modify: func(b *Bundle) { b.TaskGroups[1].Tasks[0].Key = "a" },
+			errMsg: `task #1 of task group #2: duplicate key "a"`,
+		},
+		{
+			name:   "task key used by group",
+			modify: func(b *Bundle) { b.TaskGroups[1].Tasks[0].Key = "A" },
+			errMsg: `key "A" is already used by task group`,
+		},
+		{
+			name:   "group key used by task",
+			modify: func(b *Bundle) { b.TaskGroups[1].Key = "a" },
+			errMsg: `task group #2: key "a" is already used by task`,
+		},
+		{
+			name:   "unknown required task",
+			modify: func(b *Bundle) { b.TaskGroups[1].Tasks[0].Requires.SolvedTasks = []string{"c"} },
+			errMsg: `task #1 of task group #2 requires unknown task "c"`,
+		},
+		{
+			name:   "transition by task of other group",
+			modify: func(b *Bundle) { b.TaskGroups[0].Transitions[0].Task = "b" },
+			errMsg: `task group #1 has transition by task "b", which is not in the group`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newTestBundle(t)
+			tc.modify(b)
+			err := b.Validate()
+			if len(tc.errMsg) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var httpErr *httperrors.HTTPError
+			require.True(t, errors.As(err, &httpErr))
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("new quest", func(t *testing.T) {
+		changes, err := Diff(nil, newTestBundle(t))
+		require.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Action: ActionCreate, Item: ItemQuest, Name: "Quest"},
+			{Action: ActionCreate, Item: ItemTaskGroup, Key: "A", Name: "First"},
+			{Action: ActionCreate, Item: ItemTask, Key: "a", Name: "a"},
+			{Action: ActionCreate, Item: ItemTaskGroup, Key: "B", Name: "Second"},
+			{Action: ActionCreate, Item: ItemTask, Key: "b", Name: "b"},
+			{Action: ActionCreate, Item: ItemTaskGroup, Key: "C", Name: "Bonus"},
+		}, changes)
+	})
+
+	t.Run("existing quest", func(t *testing.T) {
+		current := newTestBundle(t)
+		b := newTestBundle(t)
+		b.Quest.Description = "Updated"
+		b.TaskGroups[0], b.TaskGroups[1] = b.TaskGroups[1], b.TaskGroups[0]
+		b.TaskGroups[1].Tasks[0].Reward = 15
+		b.TaskGroups[1].Tasks = append(b.TaskGroups[1].Tasks, Task{Name: "c", Question: "Why?"})
+		b.TaskGroups[0].Tasks = nil
+
+		changes, err := Diff(current, b)
+		require.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Action: ActionUpdate, Item: ItemQuest, Name: "Quest", Fields: []string{"description"}},
+			{Action: ActionUpdate, Item: ItemTaskGroup, Key: "B", Name: "Second", Fields: []string{positionField}},
+			{Action: ActionDelete, Item: ItemTask, Key: "b", Name: "b"},
+			{Action: ActionUpdate, Item: ItemTaskGroup, Key: "A", Name: "First", Fields: []string{positionField}},
+			{Action: ActionUpdate, Item: ItemTask, Key: "a", Name: "a", Fields: []string{"reward"}},
+			{Action: ActionCreate, Item: ItemTask, Name: "c"},
+		}, changes)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		changes, err := Diff(newTestBundle(t), newTestBundle(t))
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
+
+// fakeTaskGroups keeps task groups and tasks changed through mock storage
+type fakeTaskGroups struct {
+	groups []storage.TaskGroup
+	tasks  map[storage.ID][]storage.Task
+}
+
+func (f *fakeTaskGroups) sorted() []storage.TaskGroup {
+	slices.SortFunc(f.groups, func(a, b storage.TaskGroup) int { return a.OrderIdx - b.OrderIdx })
+	return f.groups
+}
+
+func (f *fakeTaskGroups) expect(s *storagemock.MockQuestSpaceStorage) {
+	if f.tasks == nil {
+		f.tasks = make(map[storage.ID][]storage.Task)
+	}
+	s.EXPECT().GetTaskGroups(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.GetTaskGroupsRequest) ([]storage.TaskGroup, error) {
+		res := make([]storage.TaskGroup, 0, len(f.groups))
+		for _, tg := range f.sorted() {
+			if req.IncludeTasks {
+				groupTasks := slices.Clone(f.tasks[tg.ID])
+				slices.SortFunc(groupTasks, func(a, b storage.Task) int { return a.OrderIdx - b.OrderIdx })
+				tg.Tasks = groupTasks
+			}
+			res = append(res, tg)
+		}
+		return res, nil
+	}).AnyTimes()
+	s.EXPECT().CreateTaskGroup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.CreateTaskGroupRequest) (*storage.TaskGroup, error) {
+		tg := storage.TaskGroup{
+			ID:       storage.ID("new-" + req.Name),
+			OrderIdx: req.OrderIdx,
+			Quest:    &storage.Quest{ID: req.QuestID},
+			Name:     req.Name,
+			Sticky:   req.Sticky,
+		}
+		f.groups = append(f.groups, tg)
+		return &tg, nil
+	}).AnyTimes()
+	s.EXPECT().UpdateTaskGroup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.UpdateTaskGroupRequest) (*storage.TaskGroup, error) {
+		for i := range f.groups {
+			tg := &f.groups[i]
+			if tg.ID != req.ID {
+				continue
+			}
+			tg.OrderIdx = req.OrderIdx
+			if len(req.Name) > 0 {
+				tg.Name = req.Name
+			}
+			if req.HasTimeLimit != nil {
+				tg.HasTimeLimit = *req.HasTimeLimit
+			}
+			if req.TimeLimit != nil || slices.Contains(req.Reset, storage.ResetTimeLimit) {
+				tg.TimeLimit = req.TimeLimit
+			}
+			if req.SkipPenalty != nil || slices.Contains(req.Reset, storage.ResetSkipPenalty) {
+				tg.SkipPenalty = req.SkipPenalty
+			}
+			if req.Transitions != nil {
+				tg.Transitions = *req.Transitions
+			}
+			if req.Requires != nil {
+				tg.Requires = req.Requires
+			}
+			res := *tg
+			return &res, nil
+		}
+		return nil, storage.ErrNotFound
+	}).AnyTimes()
+	s.EXPECT().DeleteTaskGroup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.DeleteTaskGroupRequest) error {
+		f.groups = slices.DeleteFunc(f.groups, func(tg storage.TaskGroup) bool { return tg.ID == req.ID })
+		delete(f.tasks, req.ID)
+		return nil
+	}).AnyTimes()
+	s.EXPECT().GetTasks(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.GetTasksRequest) (storage.GetTasksResponse, error) {
+		res := make(storage.GetTasksResponse)
+		for _, id := range req.GroupIDs {
+			groupTasks := slices.Clone(f.tasks[id])
+			slices.SortFunc(groupTasks, func(a, b storage.Task) int { return a.OrderIdx - b.OrderIdx })
+			res[id] = groupTasks
+		}
+		return res, nil
+	}).AnyTimes()
+	s.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.CreateTaskRequest) (*storage.Task, error) {
+		task := storage.Task{
+			ID:       storage.ID("new-" + req.Name),
+			OrderIdx: req.OrderIdx,
+			Name:     req.Name,
+			Reward:   req.Reward,
+		}
+		f.tasks[req.GroupID] = append(f.tasks[req.GroupID], task)
+		return &task, nil
+	}).AnyTimes()
+	s.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.UpdateTaskRequest) (*storage.Task, error) {
+		for groupID, groupTasks := range f.tasks {
+			for i := range groupTasks {
+				task := &f.tasks[groupID][i]
+				if task.ID != req.ID {
+					continue
+				}
+				task.OrderIdx = req.OrderIdx
+				if req.Reward != 0 {
+					task.Reward = req.Reward
+				}
+				if len(req.Question) > 0 {
+					task.Question = req.Question
+				}
+				if len(req.CorrectAnswers) > 0 {
+					task.CorrectAnswers = req.CorrectAnswers
+				}
+				if req.Matcher != nil || slices.Contains(req.Reset, storage.ResetMatcher) {
+					task.Matcher = req.Matcher
+				}
+				if req.Scoring != nil || slices.Contains(req.Reset, storage.ResetScoring) {
+					task.Scoring = req.Scoring
+				}
+				if req.Requires != nil {
+					task.Requires = req.Requires
+				}
+				res := *task
+				return &res, nil
+			}
+		}
+		return nil, storage.ErrNotFound
+	}).AnyTimes()
+	s.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.DeleteTaskRequest) error {
+		for groupID, groupTasks := range f.tasks {
+			f.tasks[groupID] = slices.DeleteFunc(groupTasks, func(task storage.Task) bool { return task.ID == req.ID })
+		}
+		return nil
+	}).AnyTimes()
+}
+
+func newTestUpdater(s *storagemock.MockQuestSpaceStorage) *taskgroups.Updater {
+	return taskgroups.NewUpdater(s, tasks.NewUpdater(s, storage.NewScorePenalty(0)), requests.NopValidator{})
+}
+
+func TestImport_NewQuest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := storagemock.NewMockQuestSpaceStorage(ctrl)
+	var fake fakeTaskGroups
+	fake.expect(s)
+
+	imported, err := Import(context.Background(), newTestUpdater(s), "quest", nil, newTestBundle(t))
+	require.NoError(t, err)
+	require.Len(t, imported, 3)
+
+	assert.Equal(t, storage.ID("new-First"), imported[0].ID)
+	assert.Equal(t, []storage.Transition{{NextGroupID: "new-Second", TaskID: "new-a", Answers: []string{"left"}}}, imported[0].Transitions)
+	assert.Equal(t, &storage.Prerequisites{SolvedInGroups: []storage.SolvedInGroup{{TaskGroupID: "new-First", Count: 1}}}, imported[1].Requires)
+	require.Len(t, imported[1].Tasks, 1)
+	assert.Equal(t, &storage.Prerequisites{SolvedTasks: []storage.ID{"new-a"}}, imported[1].Tasks[0].Requires)
+	assert.True(t, imported[2].Sticky)
+}
+
+func TestImport_ExistingQuest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := storagemock.NewMockQuestSpaceStorage(ctrl)
+	fake := fakeTaskGroups{
+		groups: []storage.TaskGroup{
+			{
+				ID:          "A",
+				Name:        "First",
+				Quest:       &storage.Quest{ID: "quest"},
+				Transitions: []storage.Transition{{NextGroupID: "B", TaskID: "a"}},
+			},
+			{
+				ID:       "B",
+				OrderIdx: 1,
+				Name:     "Second",
+				Quest:    &storage.Quest{ID: "quest"},
+			},
+			{
+				ID:       "C",
+				OrderIdx: 2,
+				Name:     "Bonus",
+				Quest:    &storage.Quest{ID: "quest"},
+				Sticky:   true,
+			},
+		},
+		tasks: map[storage.ID][]storage.Task{
+			"A": {{ID: "a", Name: "a", Reward: 10}},
+			"B": {{ID: "b", Name: "b", Reward: 20}},
+		},
+	}
+	fake.expect(s)
+	current := newTestBundle(t)
+
+	// groups B and C are replaced with new group D, which opens after task a is solved
+	b := newTestBundle(t)
+	b.TaskGroups = b.TaskGroups[:1]
+	b.TaskGroups[0].Transitions = []Transition{{NextGroup: "D", Task: "a"}}
+	b.TaskGroups[0].Tasks[0].Reward = 15
+	b.TaskGroups = append(b.TaskGroups, TaskGroup{
+		Key:   "D",
+		Name:  "Third",
+		Tasks: []Task{{Name: "c", Question: "Why?", Requires: &Prerequisites{SolvedTasks: []string{"a"}}}},
+	})
+	require.NoError(t, b.Validate())
+
+	imported, err := Import(context.Background(), newTestUpdater(s), "quest", current, b)
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+
+	assert.Equal(t, storage.ID("A"), imported[0].ID)
+	assert.Equal(t, []storage.Transition{{NextGroupID: "new-Third", TaskID: "a"}}, imported[0].Transitions)
+	require.Len(t, imported[0].Tasks, 1)
+	assert.Equal(t, 15, imported[0].Tasks[0].Reward)
+
+	assert.Equal(t, storage.ID("new-Third"), imported[1].ID)
+	require.Len(t, imported[1].Tasks, 1)
+	assert.Equal(t, &storage.Prerequisites{SolvedTasks: []storage.ID{"a"}}, imported[1].Tasks[0].Requires)
+	assert.NotContains(t, fake.tasks, storage.ID("B"))
+	assert.Len(t, fake.groups, 2)
+}
+
+func TestImport_RemovedFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := storagemock.NewMockQuestSpaceStorage(ctrl)
+	timeLimit := storage.Duration(5 * time.Minute)
+	skipPenalty := storage.NewScorePenalty(10)
+	fake := fakeTaskGroups{
+		groups: []storage.TaskGroup{{
+			ID:           "A",
+			Name:         "First",
+			Quest:        &storage.Quest{ID: "quest"},
+			HasTimeLimit: true,
+			TimeLimit:    &timeLimit,
+			AllowSkip:    true,
+			SkipPenalty:  &skipPenalty,
+		}},
+		tasks: map[storage.ID][]storage.Task{
+			"A": {{
+				ID:             "a",
+				Name:           "a",
+				Question:       "Where to go?",
+				Reward:         10,
+				CorrectAnswers: []string{"left"},
+				Matcher:        &storage.AnswerMatcher{Type: storage.MatcherFuzzy},
+				Scoring:        &storage.Scoring{FirstSolveBonuses: []int{5}},
+			}},
+		},
+	}
+	fake.expect(s)
+	ctx := context.Background()
+	quest := &storage.Quest{ID: "quest", Name: "Quest", Access: storage.AccessPublic, StartTime: ptr.Time(time.Now())}
+	export := func() *Bundle {
+		taskGroups, err := s.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: "quest", IncludeTasks: true})
+		require.NoError(t, err)
+		return Export(quest, taskGroups)
+	}
+	current := export()
+
+	b := export()
+	b.TaskGroups[0].HasTimeLimit = false
+	b.TaskGroups[0].TimeLimit = nil
+	b.TaskGroups[0].SkipPenalty = nil
+	b.TaskGroups[0].Tasks[0].Matcher = nil
+	b.TaskGroups[0].Tasks[0].Scoring = nil
+	require.NoError(t, b.Validate())
+
+	changes, err := Diff(current, b)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Action: ActionUpdate, Item: ItemTaskGroup, Key: "A", Name: "First", Fields: []string{"has_time_limit", "skip_penalty", "time_limit"}},
+		{Action: ActionUpdate, Item: ItemTask, Key: "a", Name: "a", Fields: []string{"matcher", "scoring"}},
+	}, changes)
+
+	_, err = Import(ctx, newTestUpdater(s), "quest", current, b)
+	require.NoError(t, err)
+
+	// removed fields are cleared, so that the import leaves nothing to change
+	changes, err = Diff(export(), b)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
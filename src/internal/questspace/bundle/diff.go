@@ -0,0 +1,187 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+type Item string
+
+const (
+	ItemQuest     Item = "quest"
+	ItemTaskGroup Item = "task_group"
+	ItemTask      Item = "task"
+)
+
+// Change is a single change made by import of bundle
+type Change struct {
+	Action Action `json:"action" enums:"create,update,delete"`
+	Item   Item   `json:"item" enums:"quest,task_group,task"`
+	Key    string `json:"key,omitempty"`
+	Name   string `json:"name"`
+	// Fields are names of updated fields. Field "position" tells that item is moved
+	Fields []string `json:"fields,omitempty"`
+}
+
+// positionField is reported when group or task changes its place in bundle
+const positionField = "position"
+
+// Diff returns changes made by import of bundle b into quest with current content, or into new quest when current is nil.
+// Groups and tasks are matched by keys the same way as on import.
+func Diff(current, b *Bundle) ([]Change, error) {
+	if current == nil {
+		changes := []Change{{Action: ActionCreate, Item: ItemQuest, Name: b.Quest.Name}}
+		return append(changes, createdGroups(b.TaskGroups)...), nil
+	}
+
+	var changes []Change
+	fields, err := changedFields(&current.Quest, &b.Quest)
+	if err != nil {
+		return nil, xerrors.Errorf("quest: %w", err)
+	}
+	if len(fields) > 0 {
+		changes = append(changes, Change{Action: ActionUpdate, Item: ItemQuest, Name: b.Quest.Name, Fields: fields})
+	}
+
+	currentGroups := make(map[string]int, len(current.TaskGroups))
+	for i, tg := range current.TaskGroups {
+		currentGroups[tg.Key] = i
+	}
+	matched := make(map[string]struct{}, len(b.TaskGroups))
+	for i, tg := range b.TaskGroups {
+		oldIdx, ok := currentGroups[tg.Key]
+		if len(tg.Key) == 0 || !ok {
+			changes = append(changes, createdGroups(b.TaskGroups[i:i+1])...)
+			continue
+		}
+		matched[tg.Key] = struct{}{}
+		old := &current.TaskGroups[oldIdx]
+		groupChanges, err := diffGroup(old, &tg)
+		if err != nil {
+			return nil, xerrors.Errorf("task group %q: %w", tg.Key, err)
+		}
+		if oldIdx != i {
+			groupChanges = insertPosition(groupChanges, &tg)
+		}
+		changes = append(changes, groupChanges...)
+	}
+	for _, tg := range current.TaskGroups {
+		if _, ok := matched[tg.Key]; !ok {
+			changes = append(changes, Change{Action: ActionDelete, Item: ItemTaskGroup, Key: tg.Key, Name: tg.Name})
+		}
+	}
+	return changes, nil
+}
+
+func createdGroups(taskGroups []TaskGroup) []Change {
+	var changes []Change
+	for _, tg := range taskGroups {
+		changes = append(changes, Change{Action: ActionCreate, Item: ItemTaskGroup, Key: tg.Key, Name: tg.Name})
+		for _, t := range tg.Tasks {
+			changes = append(changes, Change{Action: ActionCreate, Item: ItemTask, Key: t.Key, Name: t.Name})
+		}
+	}
+	return changes
+}
+
+// diffGroup returns changes of group followed by changes of its tasks
+func diffGroup(old, tg *TaskGroup) ([]Change, error) {
+	oldGroup, newGroup := *old, *tg
+	oldGroup.Tasks, newGroup.Tasks = nil, nil
+	fields, err := changedFields(&oldGroup, &newGroup)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	if len(fields) > 0 {
+		changes = append(changes, Change{Action: ActionUpdate, Item: ItemTaskGroup, Key: tg.Key, Name: tg.Name, Fields: fields})
+	}
+
+	oldTasks := make(map[string]int, len(old.Tasks))
+	for i, t := range old.Tasks {
+		oldTasks[t.Key] = i
+	}
+	matched := make(map[string]struct{}, len(tg.Tasks))
+	for i, t := range tg.Tasks {
+		oldIdx, ok := oldTasks[t.Key]
+		if len(t.Key) == 0 || !ok {
+			changes = append(changes, Change{Action: ActionCreate, Item: ItemTask, Key: t.Key, Name: t.Name})
+			continue
+		}
+		matched[t.Key] = struct{}{}
+		fields, err := changedFields(&old.Tasks[oldIdx], &t)
+		if err != nil {
+			return nil, xerrors.Errorf("task %q: %w", t.Key, err)
+		}
+		if oldIdx != i {
+			fields = append(fields, positionField)
+		}
+		if len(fields) > 0 {
+			changes = append(changes, Change{Action: ActionUpdate, Item: ItemTask, Key: t.Key, Name: t.Name, Fields: fields})
+		}
+	}
+	for _, t := range old.Tasks {
+		if _, ok := matched[t.Key]; !ok {
+			changes = append(changes, Change{Action: ActionDelete, Item: ItemTask, Key: t.Key, Name: t.Name})
+		}
+	}
+	return changes, nil
+}
+
+// insertPosition marks group as moved, adding its update to changes when group fields are the same
+func insertPosition(changes []Change, tg *TaskGroup) []Change {
+	if len(changes) > 0 && changes[0].Item == ItemTaskGroup {
+		changes[0].Fields = append(changes[0].Fields, positionField)
+		return changes
+	}
+	update := Change{Action: ActionUpdate, Item: ItemTaskGroup, Key: tg.Key, Name: tg.Name, Fields: []string{positionField}}
+	return append([]Change{update}, changes...)
+}
+
+// changedFields compares JSON representations of values field by field and returns sorted names of different fields
+func changedFields(old, updated any) ([]string, error) {
+	oldFields, err := jsonFields(old)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := jsonFields(updated)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	for name, value := range newFields {
+		if !bytes.Equal(value, oldFields[name]) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range oldFields {
+		if _, ok := newFields[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	slices.Sort(fields)
+	return fields, nil
+}
+
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, xerrors.Errorf("unmarshal fields: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,334 @@
+package bundle
+
+import (
+	"context"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/taskgroups"
+	"questspace/pkg/storage"
+)
+
+// UpdateQuestRequest returns request to update quest with fields of bundle
+func (b *Bundle) UpdateQuestRequest(questID storage.ID) *storage.UpdateQuestRequest {
+	q := &b.Quest
+	return &storage.UpdateQuestRequest{
+		ID:                   questID,
+		Name:                 q.Name,
+		Description:          q.Description,
+		Access:               q.Access,
+		RegistrationDeadline: q.RegistrationDeadline,
+		StartTime:            q.StartTime,
+		FinishTime:           q.FinishTime,
+		MediaLink:            q.MediaLink,
+		MaxTeamCap:           q.MaxTeamCap,
+		HasBrief:             &q.HasBrief,
+		Brief:                &q.Brief,
+		MaxTeamsAmount:       q.MaxTeamsAmount,
+		RegistrationType:     q.RegistrationType,
+		QuestType:            q.QuestType,
+		FeedbackLink:         q.FeedbackLink,
+		AnswerLimits:         q.AnswerLimits,
+		LeaderboardFreeze:    q.LeaderboardFreeze,
+		DefaultHintPenalty:   q.DefaultHintPenalty,
+		AutoFinish:           &q.AutoFinish,
+	}
+}
+
+// Import applies task groups of bundle b to quest through updater. Groups and tasks with keys of current ones are updated,
+// other ones are created, while current groups and tasks missing in bundle are deleted. Current is nil for new quest.
+// Transitions and prerequisites are set after all groups and tasks exist, since new ones get ids only on creation.
+func Import(ctx context.Context, u *taskgroups.Updater, questID storage.ID, current, b *Bundle) ([]storage.TaskGroup, error) {
+	if current == nil {
+		current = &Bundle{}
+	}
+	currentGroups := make(map[string]*TaskGroup, len(current.TaskGroups))
+	for i := range current.TaskGroups {
+		currentGroups[current.TaskGroups[i].Key] = &current.TaskGroups[i]
+	}
+
+	contentRequest := storage.TaskGroupsBulkUpdateRequest{QuestID: questID}
+	matched := make(map[string]struct{}, len(b.TaskGroups))
+	for i := range b.TaskGroups {
+		tg := &b.TaskGroups[i]
+		old, ok := currentGroups[tg.Key]
+		if len(tg.Key) == 0 || !ok {
+			contentRequest.Create = append(contentRequest.Create, createGroupRequest(i, tg))
+			continue
+		}
+		matched[tg.Key] = struct{}{}
+		contentRequest.Update = append(contentRequest.Update, updateGroupRequest(i, tg, old))
+	}
+	for _, old := range current.TaskGroups {
+		if _, ok := matched[old.Key]; !ok {
+			contentRequest.Delete = append(contentRequest.Delete, storage.DeleteTaskGroupRequest{ID: storage.ID(old.Key)})
+		}
+	}
+	imported, err := u.BulkUpdateTaskGroups(ctx, &contentRequest)
+	if err != nil {
+		return nil, xerrors.Errorf("update task groups: %w", err)
+	}
+	if len(imported) != len(b.TaskGroups) {
+		return nil, xerrors.Errorf("expected %d task groups in quest %q, got %d", len(b.TaskGroups), questID, len(imported))
+	}
+
+	// empty key is never referenced, so transitions without task are resolved to empty task id
+	ids := make(map[string]storage.ID)
+	for i, tg := range b.TaskGroups {
+		if len(tg.Key) > 0 {
+			ids[tg.Key] = imported[i].ID
+		}
+		for j, t := range tg.Tasks {
+			if len(t.Key) > 0 {
+				ids[t.Key] = imported[i].Tasks[j].ID
+			}
+		}
+	}
+	linkRequest := storage.TaskGroupsBulkUpdateRequest{QuestID: questID}
+	for i, tg := range b.TaskGroups {
+		var tasksUpdate []storage.UpdateTaskRequest
+		for j, t := range tg.Tasks {
+			if t.Requires == nil {
+				continue
+			}
+			tasksUpdate = append(tasksUpdate, storage.UpdateTaskRequest{
+				ID:       imported[i].Tasks[j].ID,
+				OrderIdx: j,
+				Requires: t.Requires.resolve(ids),
+			})
+		}
+		if len(tg.Transitions) == 0 && tg.Requires == nil && len(tasksUpdate) == 0 {
+			continue
+		}
+
+		updateReq := storage.UpdateTaskGroupRequest{
+			ID:       imported[i].ID,
+			OrderIdx: i,
+		}
+		if tg.Requires != nil {
+			updateReq.Requires = tg.Requires.resolve(ids)
+		}
+		if len(tg.Transitions) > 0 {
+			transitions := make([]storage.Transition, 0, len(tg.Transitions))
+			for _, t := range tg.Transitions {
+				transitions = append(transitions, storage.Transition{
+					NextGroupID: ids[t.NextGroup],
+					TaskID:      ids[t.Task],
+					Answers:     t.Answers,
+				})
+			}
+			updateReq.Transitions = &transitions
+		}
+		if len(tasksUpdate) > 0 {
+			updateReq.Tasks = &storage.TasksBulkUpdateRequest{Update: tasksUpdate}
+		}
+		linkRequest.Update = append(linkRequest.Update, updateReq)
+	}
+	if len(linkRequest.Update) == 0 {
+		return imported, nil
+	}
+
+	linked, err := u.BulkUpdateTaskGroups(ctx, &linkRequest)
+	if err != nil {
+		return nil, xerrors.Errorf("link task groups: %w", err)
+	}
+	return linked, nil
+}
+
+func createGroupRequest(orderIdx int, tg *TaskGroup) storage.CreateTaskGroupRequest {
+	req := storage.CreateTaskGroupRequest{
+		OrderIdx:     orderIdx,
+		Name:         tg.Name,
+		Description:  tg.Description,
+		PubTime:      tg.PubTime,
+		Sticky:       tg.Sticky,
+		HasTimeLimit: tg.HasTimeLimit,
+		TimeLimit:    tg.TimeLimit,
+		AllowSkip:    tg.AllowSkip,
+		SkipPenalty:  tg.SkipPenalty,
+		Location:     tg.Location,
+	}
+	for j := range tg.Tasks {
+		t := &tg.Tasks[j]
+		req.Tasks = append(req.Tasks, storage.CreateTaskRequest{
+			OrderIdx:       j,
+			Name:           t.Name,
+			Question:       t.Question,
+			Reward:         t.Reward,
+			CorrectAnswers: t.CorrectAnswers,
+			Matcher:        t.Matcher,
+			AnswerLimits:   t.AnswerLimits,
+			Scoring:        t.Scoring,
+			Type:           t.Type,
+			Choice:         t.Choice,
+			Location:       t.Location,
+			Parts:          t.Parts,
+			OrderedHints:   t.OrderedHints,
+			Verification:   verification(t.Verification),
+			FullHints:      t.Hints,
+			PubTime:        t.PubTime,
+			MediaLinks:     t.MediaLinks,
+		})
+	}
+	return req
+}
+
+// updateGroupRequest returns request to update group with fields of bundle. References of the group and its tasks
+// are cleared, since they may lead to deleted groups and tasks, and are set again when all of them exist.
+func updateGroupRequest(orderIdx int, tg *TaskGroup, old *TaskGroup) storage.UpdateTaskGroupRequest {
+	req := storage.UpdateTaskGroupRequest{
+		ID:           storage.ID(tg.Key),
+		OrderIdx:     orderIdx,
+		Name:         tg.Name,
+		Description:  &tg.Description,
+		PubTime:      tg.PubTime,
+		Sticky:       &tg.Sticky,
+		HasTimeLimit: &tg.HasTimeLimit,
+		TimeLimit:    tg.TimeLimit,
+		AllowSkip:    &tg.AllowSkip,
+		SkipPenalty:  tg.SkipPenalty,
+		Location:     tg.Location,
+		Reset:        groupResets(tg),
+		Tasks:        &storage.TasksBulkUpdateRequest{},
+	}
+	if len(old.Transitions) > 0 {
+		req.Transitions = &[]storage.Transition{}
+	}
+	if old.Requires != nil {
+		req.Requires = &storage.Prerequisites{}
+	}
+
+	oldTasks := make(map[string]*Task, len(old.Tasks))
+	for i := range old.Tasks {
+		oldTasks[old.Tasks[i].Key] = &old.Tasks[i]
+	}
+	create := createGroupRequest(orderIdx, tg).Tasks
+	matched := make(map[string]struct{}, len(tg.Tasks))
+	for j := range tg.Tasks {
+		t := &tg.Tasks[j]
+		oldTask, ok := oldTasks[t.Key]
+		if len(t.Key) == 0 || !ok {
+			req.Tasks.Create = append(req.Tasks.Create, create[j])
+			continue
+		}
+		matched[t.Key] = struct{}{}
+		hints := t.Hints
+		if hints == nil {
+			hints = []storage.CreateHintRequest{}
+		}
+		updateReq := storage.UpdateTaskRequest{
+			ID:             storage.ID(t.Key),
+			OrderIdx:       j,
+			Name:           t.Name,
+			Question:       t.Question,
+			Reward:         t.Reward,
+			CorrectAnswers: t.CorrectAnswers,
+			Matcher:        t.Matcher,
+			AnswerLimits:   t.AnswerLimits,
+			Scoring:        t.Scoring,
+			Type:           taskType(t.Type),
+			Choice:         t.Choice,
+			Location:       t.Location,
+			Parts:          t.Parts,
+			OrderedHints:   &t.OrderedHints,
+			Verification:   verification(t.Verification),
+			FullHints:      &hints,
+			PubTime:        t.PubTime,
+			MediaLinks:     t.MediaLinks,
+			Reset:          taskResets(t),
+		}
+		if oldTask.Requires != nil {
+			updateReq.Requires = &storage.Prerequisites{}
+		}
+		req.Tasks.Update = append(req.Tasks.Update, updateReq)
+	}
+	for _, oldTask := range old.Tasks {
+		if _, ok := matched[oldTask.Key]; !ok {
+			req.Tasks.Delete = append(req.Tasks.Delete, storage.DeleteTaskRequest{ID: storage.ID(oldTask.Key)})
+		}
+	}
+	return req
+}
+
+// groupResets returns optional fields missing in bundle group, so that import clears them the same way as Diff reports
+func groupResets(tg *TaskGroup) []storage.ResetField {
+	var reset []storage.ResetField
+	if tg.PubTime == nil {
+		reset = append(reset, storage.ResetPubTime)
+	}
+	if tg.TimeLimit == nil {
+		reset = append(reset, storage.ResetTimeLimit)
+	}
+	if tg.SkipPenalty == nil {
+		reset = append(reset, storage.ResetSkipPenalty)
+	}
+	if tg.Location == nil {
+		reset = append(reset, storage.ResetLocation)
+	}
+	return reset
+}
+
+// taskResets returns optional fields missing in bundle task, so that import clears them the same way as Diff reports
+func taskResets(t *Task) []storage.ResetField {
+	var reset []storage.ResetField
+	if len(t.Question) == 0 {
+		reset = append(reset, storage.ResetQuestion)
+	}
+	if t.Reward == 0 {
+		reset = append(reset, storage.ResetReward)
+	}
+	if len(t.CorrectAnswers) == 0 {
+		reset = append(reset, storage.ResetCorrectAnswers)
+	}
+	if t.Matcher == nil {
+		reset = append(reset, storage.ResetMatcher)
+	}
+	if t.AnswerLimits == nil {
+		reset = append(reset, storage.ResetAnswerLimits)
+	}
+	if t.Scoring == nil {
+		reset = append(reset, storage.ResetScoring)
+	}
+	if t.Choice == nil {
+		reset = append(reset, storage.ResetChoice)
+	}
+	if t.Location == nil {
+		reset = append(reset, storage.ResetLocation)
+	}
+	if t.Parts == nil {
+		reset = append(reset, storage.ResetParts)
+	}
+	if t.PubTime == nil {
+		reset = append(reset, storage.ResetPubTime)
+	}
+	if t.MediaLinks == nil {
+		reset = append(reset, storage.ResetMediaLinks)
+	}
+	return reset
+}
+
+func taskType(t storage.TaskType) storage.TaskType {
+	if len(t) == 0 {
+		return storage.TaskTypeText
+	}
+	return t
+}
+
+func verification(v storage.VerificationType) storage.VerificationType {
+	if len(v) == 0 {
+		return storage.VerificationAuto
+	}
+	return v
+}
+
+// resolve replaces keys of groups and tasks with their ids
+func (p *Prerequisites) resolve(ids map[string]storage.ID) *storage.Prerequisites {
+	res := &storage.Prerequisites{}
+	for _, key := range p.SolvedTasks {
+		res.SolvedTasks = append(res.SolvedTasks, ids[key])
+	}
+	for _, solved := range p.SolvedInGroups {
+		res.SolvedInGroups = append(res.SolvedInGroups, storage.SolvedInGroup{TaskGroupID: ids[solved.Group], Count: solved.Count})
+	}
+	return res
+}
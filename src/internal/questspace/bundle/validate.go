@@ -0,0 +1,137 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/validate"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// Validate checks that bundle follows the schema: required fields are set, enums have known values,
+// keys are unique and references lead to groups and tasks of the bundle.
+// Task settings are checked on import the same way as on manual update of task groups.
+func (b *Bundle) Validate() error {
+	var errs []error
+	addErr := func(format string, args ...any) {
+		errs = append(errs, xerrors.Errorf(format, args...))
+	}
+
+	if b.Version != Version {
+		return httperrors.Errorf(http.StatusBadRequest, "unsupported bundle version %d, expected %d", b.Version, Version)
+	}
+	q := &b.Quest
+	if len(strings.TrimSpace(q.Name)) == 0 {
+		addErr("quest name is required")
+	}
+	if q.StartTime == nil {
+		addErr("quest start time is required")
+	}
+	if q.Access != storage.AccessPublic && q.Access != storage.AccessLinkOnly {
+		addErr("unknown quest access %q", q.Access)
+	}
+	if q.RegistrationType != "" && q.RegistrationType != storage.RegistrationAuto && q.RegistrationType != storage.RegistrationVerify {
+		addErr("unknown registration type %q", q.RegistrationType)
+	}
+	if q.QuestType != "" && q.QuestType != storage.TypeAssault && q.QuestType != storage.TypeLinear {
+		addErr("unknown quest type %q", q.QuestType)
+	}
+	if q.FeedbackLink != nil {
+		if err := validate.URL(*q.FeedbackLink); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := validate.AnswerLimits(q.AnswerLimits); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validate.LeaderboardFreeze(q.LeaderboardFreeze); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validate.Penalty("default hint penalty", q.DefaultHintPenalty); err != nil {
+		errs = append(errs, err)
+	}
+
+	groupKeys := make(map[string]int)
+	taskKeys := make(map[string]int)
+	for i, tg := range b.TaskGroups {
+		if len(strings.TrimSpace(tg.Name)) == 0 {
+			addErr("task group #%d: name is required", i+1)
+		}
+		if len(tg.Key) > 0 {
+			if _, ok := groupKeys[tg.Key]; ok {
+				addErr("task group #%d: duplicate key %q", i+1, tg.Key)
+			}
+			if _, ok := taskKeys[tg.Key]; ok {
+				addErr("task group #%d: key %q is already used by task", i+1, tg.Key)
+			}
+			groupKeys[tg.Key] = i
+		}
+		for j, t := range tg.Tasks {
+			item := fmt.Sprintf("task #%d of task group #%d", j+1, i+1)
+			if len(strings.TrimSpace(t.Name)) == 0 {
+				addErr("%s: name is required", item)
+			}
+			switch t.Type {
+			case "", storage.TaskTypeText, storage.TaskTypeChoice, storage.TaskTypeFile, storage.TaskTypeLocation, storage.TaskTypeParts:
+			default:
+				addErr("%s: unknown type %q", item, t.Type)
+			}
+			if t.Verification != "" && t.Verification != storage.VerificationAuto && t.Verification != storage.VerificationManual {
+				addErr("%s: unknown verification %q", item, t.Verification)
+			}
+			if len(t.Key) > 0 {
+				if _, ok := taskKeys[t.Key]; ok {
+					addErr("%s: duplicate key %q", item, t.Key)
+				}
+				if _, ok := groupKeys[t.Key]; ok {
+					addErr("%s: key %q is already used by task group", item, t.Key)
+				}
+				taskKeys[t.Key] = i
+			}
+		}
+	}
+
+	checkPrerequisites := func(item string, p *Prerequisites) {
+		if p == nil {
+			return
+		}
+		for _, key := range p.SolvedTasks {
+			if _, ok := taskKeys[key]; !ok {
+				addErr("%s requires unknown task %q", item, key)
+			}
+		}
+		for _, solved := range p.SolvedInGroups {
+			if _, ok := groupKeys[solved.Group]; !ok {
+				addErr("%s requires unknown task group %q", item, solved.Group)
+			}
+		}
+	}
+	for i, tg := range b.TaskGroups {
+		item := fmt.Sprintf("task group #%d", i+1)
+		checkPrerequisites(item, tg.Requires)
+		for _, t := range tg.Transitions {
+			if _, ok := groupKeys[t.NextGroup]; !ok {
+				addErr("%s has transition to unknown task group %q", item, t.NextGroup)
+			}
+			if len(t.Task) == 0 {
+				continue
+			}
+			if groupIdx, ok := taskKeys[t.Task]; !ok || groupIdx != i {
+				addErr("%s has transition by task %q, which is not in the group", item, t.Task)
+			}
+		}
+		for j, t := range tg.Tasks {
+			checkPrerequisites(fmt.Sprintf("task #%d of %s", j+1, item), t.Requires)
+		}
+	}
+
+	if len(errs) > 0 {
+		return httperrors.WrapWithCode(http.StatusBadRequest, errors.Join(errs...))
+	}
+	return nil
+}
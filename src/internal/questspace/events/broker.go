@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"questspace/pkg/logging"
+	"questspace/pkg/storage"
+)
+
+// Publisher delivers events to subscribers
+type Publisher interface {
+	Publish(e Event)
+}
+
+// Relay passes events to every application instance, including the sending one,
+// which delivers them to its subscribers with Broker.Deliver
+type Relay interface {
+	Send(e Event) error
+}
+
+// Broker delivers events to subscribers of the same quest inside a single application instance.
+// Publishing never blocks: if subscriber does not read events fast enough, new events are dropped for it
+// and counted, so subscriber has to resynchronize its state instead of slowing down publishers.
+type Broker struct {
+	mu    sync.RWMutex
+	subs  map[storage.ID]map[*Subscription]struct{}
+	relay Relay
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subs: make(map[storage.ID]map[*Subscription]struct{}),
+	}
+}
+
+// NewRelayBroker returns broker, which publishes events through relay, so that subscribers
+// connected to other application instances receive them too
+func NewRelayBroker(relay Relay) *Broker {
+	b := NewBroker()
+	b.relay = relay
+	return b
+}
+
+type Subscription struct {
+	broker  *Broker
+	questID storage.ID
+	events  chan Event
+	dropped atomic.Int64
+	once    sync.Once
+}
+
+// Subscribe returns subscription to quest events with buffer for bufSize events.
+// Subscription with buffer for a single event coalesces bursts of events into one notification.
+func (b *Broker) Subscribe(questID storage.ID, bufSize int) *Subscription {
+	sub := &Subscription{
+		broker:  b,
+		questID: questID,
+		events:  make(chan Event, max(bufSize, 1)),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	questSubs := b.subs[questID]
+	if questSubs == nil {
+		questSubs = make(map[*Subscription]struct{})
+		b.subs[questID] = questSubs
+	}
+	questSubs[sub] = struct{}{}
+	return sub
+}
+
+// Publish sends event to quest subscribers. Publishing to nil broker does nothing.
+// If relay fails, event is delivered to subscribers of this instance only
+func (b *Broker) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if b.relay != nil {
+		err := b.relay.Send(e)
+		if err == nil {
+			return
+		}
+		logging.Error(context.Background(), "could not relay event", zap.String("type", string(e.Type)), zap.Error(err))
+	}
+	b.Deliver(e)
+}
+
+// Deliver sends event to quest subscribers of this instance bypassing relay
+func (b *Broker) Deliver(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs[e.QuestID] {
+		select {
+		case sub.events <- e:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped returns number of events dropped for subscription since previous call
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Swap(0)
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.broker.mu.Lock()
+		defer s.broker.mu.Unlock()
+		questSubs := s.broker.subs[s.questID]
+		delete(questSubs, s)
+		if len(questSubs) == 0 {
+			delete(s.broker.subs, s.questID)
+		}
+	})
+}
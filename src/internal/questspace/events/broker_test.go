@@ -0,0 +1,140 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+)
+
+func TestBroker_PublishToQuestSubscribers(t *testing.T) {
+	b := NewBroker()
+	sub := b.Subscribe("quest", 2)
+	defer sub.Close()
+	other := b.Subscribe("other", 2)
+	defer other.Close()
+
+	b.Publish(Event{Type: TaskAccepted, QuestID: "quest", TeamID: "team"})
+
+	require.Len(t, sub.Events(), 1)
+	assert.Equal(t, Event{Type: TaskAccepted, QuestID: "quest", TeamID: "team"}, <-sub.Events())
+	assert.Empty(t, other.Events())
+}
+
+func TestBroker_SlowSubscriberDoesNotBlock(t *testing.T) {
+	b := NewBroker()
+	sub := b.Subscribe("quest", 1)
+	defer sub.Close()
+
+	for i := 0; i < 5; i++ {
+		b.Publish(Event{Type: PenaltyAdded, QuestID: "quest"})
+	}
+
+	assert.Len(t, sub.Events(), 1)
+	assert.Equal(t, int64(4), sub.Dropped())
+	assert.Equal(t, int64(0), sub.Dropped())
+}
+
+func TestBroker_Close(t *testing.T) {
+	b := NewBroker()
+	sub := b.Subscribe("quest", 1)
+	sub.Close()
+	sub.Close()
+
+	b.Publish(Event{Type: TaskAccepted, QuestID: storage.ID("quest")})
+	assert.Empty(t, sub.Events())
+	assert.Empty(t, b.subs)
+}
+
+func TestBatch_PublishAfterCommit(t *testing.T) {
+	b := NewBroker()
+	sub := b.Subscribe("quest", 4)
+	defer sub.Close()
+
+	batch := NewBatch(b)
+	batch.Add(New(HintTaken, "quest", "team", HintTakenData{TaskID: "task"}))
+	batch.Add(New(TaskAccepted, "quest", "team", TaskAcceptedData{TaskID: "task", Score: 10}))
+	assert.Empty(t, sub.Events())
+
+	batch.Publish()
+	require.Len(t, sub.Events(), 2)
+	assert.Equal(t, HintTaken, (<-sub.Events()).Type)
+	assert.Equal(t, TaskAccepted, (<-sub.Events()).Type)
+
+	batch.Publish()
+	assert.Empty(t, sub.Events())
+
+	var nilBatch *Batch
+	nilBatch.Add(New(PenaltyAdded, "quest", "team", nil))
+	nilBatch.Publish()
+}
+
+type chanRelay struct {
+	sent chan []byte
+	err  error
+}
+
+func (r *chanRelay) Send(e Event) error {
+	if r.err != nil {
+		return r.err
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	r.sent <- payload
+	return nil
+}
+
+func TestBroker_Relay(t *testing.T) {
+	relay := &chanRelay{sent: make(chan []byte, 1)}
+	b := NewRelayBroker(relay)
+	sub := b.Subscribe("quest", 2)
+	defer sub.Close()
+
+	published := New(MemberJoined, "quest", "team", MemberData{UserID: "user"})
+	b.Publish(published)
+	assert.Empty(t, sub.Events(), "event is delivered only after it comes back from relay")
+
+	e, err := Decode(<-relay.sent)
+	require.NoError(t, err)
+	b.Deliver(e)
+	require.Len(t, sub.Events(), 1)
+	got := <-sub.Events()
+	assert.True(t, published.Time.Equal(got.Time))
+	got.Time = published.Time
+	assert.Equal(t, published, got)
+
+	relay.err = errors.New("relay is down")
+	b.Publish(New(PenaltyAdded, "quest", "team", PenaltyData{Penalty: 5}))
+	assert.Len(t, sub.Events(), 1, "event is delivered locally when relay fails")
+}
+
+func TestDecode(t *testing.T) {
+	for _, e := range []Event{
+		New(TaskAccepted, "quest", "team", TaskAcceptedData{TaskGroupID: "group", TaskID: "task", Score: 10}),
+		New(HintTaken, "quest", "team", HintTakenData{TaskID: "task", Index: 1, UserID: "user"}),
+		New(GroupClosed, "quest", "team", GroupData{TaskGroupID: "group", Reason: GroupSkipped}),
+		New(PenaltyRevoked, "quest", "team", PenaltyData{ID: 1, Penalty: 5}),
+		New(BonusCodeUsed, "quest", "team", BonusCodeData{Code: "code", Value: 3}),
+		New(CaptainChanged, "quest", "team", MemberData{UserID: "user"}),
+		New(QuestFinished, "quest", "", nil),
+	} {
+		t.Run(string(e.Type), func(t *testing.T) {
+			payload, err := json.Marshal(e)
+			require.NoError(t, err)
+			got, err := Decode(payload)
+			require.NoError(t, err)
+			assert.True(t, e.Time.Equal(got.Time))
+			got.Time = e.Time
+			assert.Equal(t, e, got)
+		})
+	}
+
+	_, err := Decode([]byte(`{"type":"task_accepted","data":{"score":"ten"}}`))
+	assert.Error(t, err)
+}
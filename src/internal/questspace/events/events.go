@@ -0,0 +1,175 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/qtime"
+	"questspace/pkg/storage"
+)
+
+type Type string
+
+const (
+	TaskAccepted   Type = "task_accepted"
+	HintTaken      Type = "hint_taken"
+	GroupOpened    Type = "group_opened"
+	GroupClosed    Type = "group_closed"
+	PenaltyAdded   Type = "penalty_added"
+	QuestFinished  Type = "quest_finished"
+	MemberJoined   Type = "member_joined"
+	MemberLeft     Type = "member_left"
+	CaptainChanged Type = "captain_changed"
+	TeamAccepted   Type = "team_accepted"
+	// LeaderboardRevealed is sent when quest creator ends leaderboard freeze
+	LeaderboardRevealed Type = "leaderboard_revealed"
+	BonusCodeUsed       Type = "bonus_code_used"
+	PenaltyRevoked      Type = "penalty_revoked"
+)
+
+// ChangesScore reports whether event changes quest results visible in leaderboard
+func (t Type) ChangesScore() bool {
+	return t == TaskAccepted || t == PenaltyAdded || t == LeaderboardRevealed || t == BonusCodeUsed || t == PenaltyRevoked
+}
+
+// Event notifies subscribers of a quest that game state has changed.
+// Events without TeamID concern all teams of the quest.
+type Event struct {
+	Type    Type       `json:"type"`
+	QuestID storage.ID `json:"quest_id"`
+	TeamID  storage.ID `json:"team_id,omitempty"`
+	Time    time.Time  `json:"time"`
+	Data    any        `json:"data,omitempty"`
+}
+
+func New(eventType Type, questID, teamID storage.ID, data any) Event {
+	return Event{
+		Type:    eventType,
+		QuestID: questID,
+		TeamID:  teamID,
+		Time:    qtime.Now(),
+		Data:    data,
+	}
+}
+
+// Decode parses event encoded as JSON, restoring its data with the same type it was published with
+func Decode(payload []byte) (Event, error) {
+	var raw struct {
+		Event
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return Event{}, xerrors.Errorf("unmarshal event: %w", err)
+	}
+	e := raw.Event
+	if len(raw.Data) == 0 {
+		return e, nil
+	}
+	var err error
+	switch e.Type {
+	case TaskAccepted:
+		e.Data, err = decodeData[TaskAcceptedData](raw.Data)
+	case HintTaken:
+		e.Data, err = decodeData[HintTakenData](raw.Data)
+	case GroupOpened, GroupClosed:
+		e.Data, err = decodeData[GroupData](raw.Data)
+	case PenaltyAdded, PenaltyRevoked:
+		e.Data, err = decodeData[PenaltyData](raw.Data)
+	case BonusCodeUsed:
+		e.Data, err = decodeData[BonusCodeData](raw.Data)
+	case MemberJoined, MemberLeft, CaptainChanged:
+		e.Data, err = decodeData[MemberData](raw.Data)
+	}
+	if err != nil {
+		return Event{}, xerrors.Errorf("unmarshal %s data: %w", e.Type, err)
+	}
+	return e, nil
+}
+
+// decodeData returns data by value, the same way events are published
+func decodeData[T any](raw json.RawMessage) (any, error) {
+	var data T
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+type TaskAcceptedData struct {
+	TaskGroupID storage.ID `json:"task_group_id"`
+	TaskID      storage.ID `json:"task_id"`
+	UserID      storage.ID `json:"user_id,omitempty"`
+	Score       int        `json:"score"`
+	// Part is set when a part of multi-part task is accepted
+	Part *int `json:"part,omitempty"`
+}
+
+type HintTakenData struct {
+	TaskID storage.ID `json:"task_id"`
+	Index  int        `json:"index"`
+	UserID storage.ID `json:"user_id"`
+}
+
+type GroupData struct {
+	TaskGroupID storage.ID `json:"task_group_id"`
+	// Reason is set for closed groups: either "solved", "time_limit", "transition" or "skipped"
+	Reason string `json:"reason,omitempty" enums:"solved,time_limit,transition,skipped"`
+}
+
+const (
+	GroupSolved    = "solved"
+	GroupTimeLimit = "time_limit"
+	// GroupTransition closes linear group when accepted answer leads team to another branch of the route
+	GroupTransition = "transition"
+	GroupSkipped    = "skipped"
+)
+
+type PenaltyData struct {
+	// ID is set for revoked entries of penalty ledger
+	ID          int64      `json:"id,omitempty"`
+	Penalty     int        `json:"penalty"`
+	Reason      string     `json:"reason,omitempty"`
+	TaskID      storage.ID `json:"task_id,omitempty"`
+	TaskGroupID storage.ID `json:"task_group_id,omitempty"`
+}
+
+type BonusCodeData struct {
+	Code   string     `json:"code"`
+	Value  int        `json:"value"`
+	UserID storage.ID `json:"user_id"`
+}
+
+type MemberData struct {
+	UserID storage.ID `json:"user_id"`
+}
+
+// Batch collects events during a transaction, so that they are published only after it is committed.
+// Nil batch silently drops events.
+type Batch struct {
+	publisher Publisher
+	events    []Event
+}
+
+func NewBatch(publisher Publisher) *Batch {
+	return &Batch{publisher: publisher}
+}
+
+func (b *Batch) Add(e Event) {
+	if b == nil {
+		return
+	}
+	b.events = append(b.events, e)
+}
+
+// Publish sends collected events to publisher and resets the batch
+func (b *Batch) Publish() {
+	if b == nil || b.publisher == nil {
+		return
+	}
+	for _, e := range b.events {
+		b.publisher.Publish(e)
+	}
+	b.events = nil
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/qtime"
+	"questspace/internal/questspace/events"
+	"questspace/internal/validate"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+type BonusCodesResponse struct {
+	BonusCodes []storage.BonusCode `json:"bonus_codes"`
+}
+
+func (s *Service) CreateBonusCode(ctx context.Context, req *storage.CreateBonusCodeRequest) (*storage.BonusCode, error) {
+	req.Code = strings.TrimSpace(req.Code)
+	if err := validate.BonusCode(req); err != nil {
+		return nil, err
+	}
+	code, err := s.ah.CreateBonusCode(ctx, req)
+	if err != nil {
+		if errors.Is(err, storage.ErrExists) {
+			return nil, httperrors.Errorf(http.StatusConflict, "bonus code %q already exists", req.Code)
+		}
+		return nil, xerrors.Errorf("create bonus code: %w", err)
+	}
+	return code, nil
+}
+
+func (s *Service) GetBonusCodes(ctx context.Context, questID storage.ID) (*BonusCodesResponse, error) {
+	codes, err := s.ah.GetBonusCodes(ctx, &storage.GetBonusCodesRequest{QuestID: questID})
+	if err != nil {
+		return nil, xerrors.Errorf("get bonus codes: %w", err)
+	}
+	if codes == nil {
+		codes = []storage.BonusCode{}
+	}
+	return &BonusCodesResponse{BonusCodes: codes}, nil
+}
+
+func (s *Service) DeleteBonusCode(ctx context.Context, req *storage.DeleteBonusCodeRequest) error {
+	if err := s.ah.DeleteBonusCode(ctx, req); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "bonus code %q not found", req.ID)
+		}
+		return xerrors.Errorf("delete bonus code: %w", err)
+	}
+	return nil
+}
+
+type UseBonusCodeRequest struct {
+	QuestID storage.ID `json:"-"`
+	Code    string     `json:"code"`
+}
+
+type UseBonusCodeResponse struct {
+	Code  string `json:"code"`
+	Value int    `json:"value"`
+}
+
+// UseBonusCode adds value of bonus code entered by user to the score of their team. Every team can enter each code only once.
+func (s *Service) UseBonusCode(ctx context.Context, user *storage.User, req *UseBonusCodeRequest) (*UseBonusCodeResponse, error) {
+	team, err := s.tms.GetTeam(ctx, &storage.GetTeamRequest{UserRegistration: &storage.UserRegistration{UserID: user.ID, QuestID: req.QuestID}})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.Errorf(http.StatusNotFound, "team for user %q not found", user.ID)
+		}
+		return nil, xerrors.Errorf("get team: %w", err)
+	}
+	if team.RegistrationStatus != storage.RegistrationStatusAccepted {
+		return nil, httperrors.New(http.StatusForbidden, "only accepted teams can enter bonus codes")
+	}
+	if err = checkQuestRunning(team.Quest); err != nil {
+		return nil, err
+	}
+
+	code, err := s.ah.GetBonusCode(ctx, &storage.GetBonusCodeRequest{QuestID: req.QuestID, Code: strings.TrimSpace(req.Code)})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.New(http.StatusNotFound, "unknown bonus code")
+		}
+		return nil, xerrors.Errorf("get bonus code: %w", err)
+	}
+	now := qtime.Now()
+	if code.ValidFrom != nil && now.Before(*code.ValidFrom) {
+		return nil, httperrors.New(http.StatusNotAcceptable, "bonus code is not valid yet")
+	}
+	if code.ValidUntil != nil && now.After(*code.ValidUntil) {
+		return nil, httperrors.New(http.StatusNotAcceptable, "bonus code is not valid anymore")
+	}
+	if err = s.ah.UseBonusCode(ctx, &storage.UseBonusCodeRequest{CodeID: code.ID, TeamID: team.ID, UserID: user.ID}); err != nil {
+		if errors.Is(err, storage.ErrExists) {
+			return nil, httperrors.New(http.StatusNotAcceptable, "bonus code is already used by team")
+		}
+		if errors.Is(err, storage.ErrUsesExhausted) {
+			return nil, httperrors.New(http.StatusNotAcceptable, "bonus code has no uses left")
+		}
+		return nil, xerrors.Errorf("use bonus code: %w", err)
+	}
+	s.events.Add(events.New(events.BonusCodeUsed, team.Quest.ID, team.ID, events.BonusCodeData{Code: code.Code, Value: code.Value, UserID: user.ID}))
+	return &UseBonusCodeResponse{Code: code.Code, Value: code.Value}, nil
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestService_UseBonusCode(t *testing.T) {
+	replaceNowFunc(t)
+	ctrl := gomock.NewController(t)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, nil, tms, ah)
+
+	user := &storage.User{ID: "user"}
+	team := &storage.Team{
+		ID:                 "team",
+		RegistrationStatus: storage.RegistrationStatusAccepted,
+		Quest:              &storage.Quest{ID: "quest", StartTime: ptr.Time(visibilityNow.Add(-time.Hour))},
+	}
+	tms.EXPECT().GetTeam(gomock.Any(), gomock.Any()).Return(team, nil).AnyTimes()
+
+	code := &storage.BonusCode{ID: "code", Code: "Treasure", Value: 15}
+	ah.EXPECT().GetBonusCode(gomock.Any(), &storage.GetBonusCodeRequest{QuestID: "quest", Code: "treasure"}).Return(code, nil)
+	ah.EXPECT().UseBonusCode(gomock.Any(), &storage.UseBonusCodeRequest{CodeID: "code", TeamID: "team", UserID: "user"}).Return(nil)
+	resp, err := s.UseBonusCode(context.Background(), user, &UseBonusCodeRequest{QuestID: "quest", Code: " treasure "})
+	require.NoError(t, err)
+	assert.Equal(t, &UseBonusCodeResponse{Code: "Treasure", Value: 15}, resp)
+
+	ah.EXPECT().GetBonusCode(gomock.Any(), gomock.Any()).Return(code, nil)
+	ah.EXPECT().UseBonusCode(gomock.Any(), gomock.Any()).Return(storage.ErrExists)
+	_, err = s.UseBonusCode(context.Background(), user, &UseBonusCodeRequest{QuestID: "quest", Code: "treasure"})
+	requireHTTPCode(t, http.StatusNotAcceptable, err)
+
+	ah.EXPECT().GetBonusCode(gomock.Any(), gomock.Any()).Return(code, nil)
+	ah.EXPECT().UseBonusCode(gomock.Any(), gomock.Any()).Return(storage.ErrUsesExhausted)
+	_, err = s.UseBonusCode(context.Background(), user, &UseBonusCodeRequest{QuestID: "quest", Code: "treasure"})
+	requireHTTPCode(t, http.StatusNotAcceptable, err)
+
+	expired := &storage.BonusCode{ID: "expired", Code: "Late", Value: 5, ValidUntil: ptr.Time(visibilityNow.Add(-time.Minute))}
+	ah.EXPECT().GetBonusCode(gomock.Any(), gomock.Any()).Return(expired, nil)
+	_, err = s.UseBonusCode(context.Background(), user, &UseBonusCodeRequest{QuestID: "quest", Code: "late"})
+	requireHTTPCode(t, http.StatusNotAcceptable, err)
+
+	ah.EXPECT().GetBonusCode(gomock.Any(), gomock.Any()).Return(nil, storage.ErrNotFound)
+	_, err = s.UseBonusCode(context.Background(), user, &UseBonusCodeRequest{QuestID: "quest", Code: "guess"})
+	requireHTTPCode(t, http.StatusNotFound, err)
+}
+
+func TestService_GetResults_BonusCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, tgs, tms, ah)
+
+	tms.EXPECT().GetTeams(gomock.Any(), gomock.Any()).Return([]storage.Team{{ID: "team", Name: "Winners"}}, nil)
+	tgs.EXPECT().GetTaskGroups(gomock.Any(), gomock.Any()).Return(nil, nil)
+	ah.EXPECT().GetScoreResults(gomock.Any(), gomock.Any()).Return(storage.ScoreResults{}, nil)
+	ah.EXPECT().GetPenalties(gomock.Any(), gomock.Any()).Return(storage.TeamPenalties{"team": {{TeamID: "team", Value: 4, Source: storage.PenaltySourceLedger}}}, nil)
+	ah.EXPECT().GetBonusCodeUses(gomock.Any(), &storage.GetBonusCodeUsesRequest{QuestID: "quest"}).Return(storage.TeamBonusCodes{
+		"team": {{TeamID: "team", CodeID: "first", Value: 15}, {TeamID: "team", CodeID: "trap", Value: -5}},
+	}, nil)
+	ah.EXPECT().GetAdjustments(gomock.Any(), gomock.Any()).Return([]storage.Adjustment{{ID: 1, TeamID: "team", Value: 4}}, nil)
+
+	res, err := s.GetResults(context.Background(), "quest")
+	require.NoError(t, err)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, 10, res.Results[0].BonusCodes)
+	assert.Equal(t, 6, res.Results[0].TotalScore)
+}
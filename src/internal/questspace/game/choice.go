@@ -0,0 +1,80 @@
+package game
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+type AnswerOption struct {
+	// Index of the option, which is sent back on answer
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+}
+
+// shuffledOptions returns options of choice task in order, which is random but stays the same for the team
+func shuffledOptions(choice *storage.Choice, teamID, taskID storage.ID) []AnswerOption {
+	options := make([]AnswerOption, 0, len(choice.Options))
+	for i, text := range choice.Options {
+		options = append(options, AnswerOption{Index: i, Text: text})
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(teamID))
+	_, _ = h.Write([]byte(taskID))
+	r := rand.New(rand.NewPCG(h.Sum64(), 0))
+	r.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+	return options
+}
+
+// choiceShare returns share of reward earned by selected options: 1 for exactly correct selection,
+// share of correct options minus share of wrong ones for partial scoring and 0 otherwise.
+func choiceShare(choice *storage.Choice, selected []int) (float64, error) {
+	if len(selected) == 0 {
+		return 0, httperrors.New(http.StatusBadRequest, "no options selected")
+	}
+	if !choice.Multiple && len(selected) > 1 {
+		return 0, httperrors.New(http.StatusBadRequest, "only one option can be selected")
+	}
+	correct := make(map[int]struct{}, len(choice.Correct))
+	for _, idx := range choice.Correct {
+		correct[idx] = struct{}{}
+	}
+	seen := make(map[int]struct{}, len(selected))
+	var right, wrong int
+	for _, idx := range selected {
+		if idx < 0 || idx >= len(choice.Options) {
+			return 0, httperrors.Errorf(http.StatusBadRequest, "option %d is out of options range", idx)
+		}
+		if _, ok := seen[idx]; ok {
+			return 0, httperrors.Errorf(http.StatusBadRequest, "option %d is selected twice", idx)
+		}
+		seen[idx] = struct{}{}
+		if _, ok := correct[idx]; ok {
+			right++
+		} else {
+			wrong++
+		}
+	}
+	if right == len(correct) && wrong == 0 {
+		return 1, nil
+	}
+	if !choice.PartialScoring || right <= wrong {
+		return 0, nil
+	}
+	return float64(right-wrong) / float64(len(correct)), nil
+}
+
+// choiceAnswerText returns text of selected options to be saved as answer of the team
+func choiceAnswerText(choice *storage.Choice, selected []int) string {
+	texts := make([]string, 0, len(selected))
+	for _, idx := range selected {
+		texts = append(texts, choice.Options[idx])
+	}
+	return strings.Join(texts, "; ")
+}
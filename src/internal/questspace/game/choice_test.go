@@ -0,0 +1,62 @@
+package game
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+)
+
+func TestChoiceShare(t *testing.T) {
+	single := &storage.Choice{Options: []string{"a", "b", "c"}, Correct: []int{1}}
+	multiple := &storage.Choice{Options: []string{"a", "b", "c", "d"}, Correct: []int{0, 2}, Multiple: true}
+	partial := &storage.Choice{Options: []string{"a", "b", "c", "d", "e"}, Correct: []int{0, 1, 2, 3}, Multiple: true, PartialScoring: true}
+
+	testCases := []struct {
+		name          string
+		choice        *storage.Choice
+		selected      []int
+		expectedShare float64
+		expectedCode  int
+	}{
+		{name: "single correct", choice: single, selected: []int{1}, expectedShare: 1},
+		{name: "single wrong", choice: single, selected: []int{0}},
+		{name: "single with two options", choice: single, selected: []int{0, 1}, expectedCode: http.StatusBadRequest},
+		{name: "multiple correct", choice: multiple, selected: []int{2, 0}, expectedShare: 1},
+		{name: "multiple incomplete", choice: multiple, selected: []int{0}},
+		{name: "partial incomplete", choice: partial, selected: []int{0, 1, 2}, expectedShare: 0.75},
+		{name: "partial with wrong option", choice: partial, selected: []int{0, 1, 4}, expectedShare: 0.25},
+		{name: "partial mostly wrong", choice: partial, selected: []int{0, 4}},
+		{name: "nothing selected", choice: multiple, expectedCode: http.StatusBadRequest},
+		{name: "out of range", choice: multiple, selected: []int{4}, expectedCode: http.StatusBadRequest},
+		{name: "selected twice", choice: multiple, selected: []int{0, 0}, expectedCode: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			share, err := choiceShare(tc.choice, tc.selected)
+			if tc.expectedCode != 0 {
+				requireHTTPCode(t, tc.expectedCode, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedShare, share, 1e-9)
+		})
+	}
+}
+
+func TestShuffledOptions(t *testing.T) {
+	choice := &storage.Choice{Options: []string{"a", "b", "c", "d", "e", "f", "g", "h"}}
+	options := shuffledOptions(choice, "team", "task")
+	assert.Equal(t, options, shuffledOptions(choice, "team", "task"))
+
+	texts := make([]string, 0, len(options))
+	for _, o := range options {
+		assert.Equal(t, choice.Options[o.Index], o.Text)
+		texts = append(texts, o.Text)
+	}
+	assert.ElementsMatch(t, choice.Options, texts)
+	assert.NotEqual(t, options, shuffledOptions(choice, "other team", "task"))
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"context"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/events"
+	"questspace/pkg/storage"
+)
+
+// recordGroupClosed records closing of task group. In linear quests it also records opening of the next group,
+// which becomes available to team right after the previous one is closed. Next group is either chosen by transition
+// or is the next one by order.
+func (s *Service) recordGroupClosed(ctx context.Context, team *storage.Team, taskGroup *storage.TaskGroup, reason string, nextGroupID *storage.ID) error {
+	if s.events == nil {
+		return nil
+	}
+	s.events.Add(events.New(events.GroupClosed, team.Quest.ID, team.ID, events.GroupData{TaskGroupID: taskGroup.ID, Reason: reason}))
+	if team.Quest.QuestType != storage.TypeLinear || taskGroup.Sticky {
+		return nil
+	}
+	if nextGroupID != nil {
+		s.events.Add(events.New(events.GroupOpened, team.Quest.ID, team.ID, events.GroupData{TaskGroupID: *nextGroupID}))
+		return nil
+	}
+	// failed query aborts the whole transaction, so the error cannot be ignored even though events are best-effort
+	taskGroups, err := s.tgs.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: team.Quest.ID})
+	if err != nil {
+		return xerrors.Errorf("get task groups: %w", err)
+	}
+	for _, tg := range taskGroups {
+		if tg.OrderIdx > taskGroup.OrderIdx && !tg.Sticky {
+			s.events.Add(events.New(events.GroupOpened, team.Quest.ID, team.ID, events.GroupData{TaskGroupID: tg.ID}))
+			return nil
+		}
+	}
+	return nil
+}
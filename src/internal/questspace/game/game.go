@@ -7,32 +7,49 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/yandex/perforator/library/go/core/xerrors"
 	"go.uber.org/zap"
 
 	"questspace/internal/qtime"
+	"questspace/internal/questspace/events"
+	"questspace/internal/questspace/quests"
+	"questspace/pkg/geo"
 	"questspace/pkg/httperrors"
 	"questspace/pkg/logging"
+	"questspace/pkg/matchers"
 	"questspace/pkg/storage"
 )
 
 type Service struct {
-	ts  storage.TaskStorage
-	tgs storage.TaskGroupStorage
-	tms storage.TeamStorage
-	ah  storage.AnswerHintStorage
+	ts     storage.TaskStorage
+	tgs    storage.TaskGroupStorage
+	tms    storage.TeamStorage
+	ah     storage.AnswerHintStorage
+	events *events.Batch
 }
 
-func NewService(ts storage.TaskStorage, tgs storage.TaskGroupStorage, tms storage.TeamStorage, ah storage.AnswerHintStorage) *Service {
-	return &Service{
+type Option func(s *Service)
+
+// WithEvents makes service record game events into batch, which should be published after transaction commit
+func WithEvents(batch *events.Batch) Option {
+	return func(s *Service) {
+		s.events = batch
+	}
+}
+
+func NewService(ts storage.TaskStorage, tgs storage.TaskGroupStorage, tms storage.TeamStorage, ah storage.AnswerHintStorage, opts ...Option) *Service {
+	s := &Service{
 		ts:  ts,
 		tgs: tgs,
 		tms: tms,
 		ah:  ah,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 type AnswerDataRequest struct {
@@ -46,6 +63,12 @@ type AnswerTaskHint struct {
 	Name    string               `json:"name,omitempty"`
 	Text    string               `json:"text,omitempty"`
 	Penalty storage.PenaltyOneOf `json:"penalty"`
+	// Auto is set for hint revealed automatically, which costs AutoPenalty or nothing if it is not set
+	Auto        bool                  `json:"auto,omitempty"`
+	AutoPenalty *storage.PenaltyOneOf `json:"auto_penalty,omitempty"`
+	// OpensAt and OpensIn are set for hint, which is not revealed automatically yet
+	OpensAt *time.Time        `json:"opens_at,omitempty"`
+	OpensIn *storage.Duration `json:"opens_in,omitempty" swaggertype:"integer" example:"300"`
 }
 
 type AnswerTask struct {
@@ -55,12 +78,20 @@ type AnswerTask struct {
 	Question     string                   `json:"question"`
 	Reward       int                      `json:"reward"`
 	Verification storage.VerificationType `json:"verification" enums:"auto,manual"`
+	Type         storage.TaskType         `json:"type,omitempty" enums:"text,choice,file,location,parts"`
+	Multiple     bool                     `json:"multiple,omitempty"`
+	Options      []AnswerOption           `json:"options,omitempty"`
+	Parts        []AnswerPart             `json:"parts,omitempty"`
 	Hints        []AnswerTaskHint         `json:"hints"`
+	OrderedHints bool                     `json:"ordered_hints,omitempty"`
 	Accepted     bool                     `json:"accepted"`
 	Score        int                      `json:"score"`
 	Answer       string                   `json:"answer,omitempty"`
+	ReviewStatus storage.ReviewStatus     `json:"review_status,omitempty" enums:"PENDING,ACCEPTED,REJECTED"`
 	PubTime      *time.Time               `json:"pub_time,omitempty"`
 	MediaLinks   []string                 `json:"media_links,omitempty"`
+	// Partial is set when choice task is answered partially correct, so it is scored but not solved
+	Partial bool `json:"partial,omitempty"`
 	// Deprecated
 	MediaLink string `json:"media_link,omitempty" example:"deprecated"`
 	// Deprecated
@@ -95,115 +126,269 @@ func (s *Service) FillAnswerData(ctx context.Context, req *AnswerDataRequest) (*
 	if err != nil {
 		return nil, xerrors.Errorf("get accepted tasks: %w", err)
 	}
-	return s.fillAnswerData(ctx, req, takenHints, acceptedTasks), nil
+	reviewTries, err := s.ah.GetReviewAnswerTries(ctx, &storage.GetReviewAnswerTriesRequest{TeamID: req.Team.ID, QuestID: req.Quest.ID})
+	if err != nil {
+		return nil, xerrors.Errorf("get review answer tries: %w", err)
+	}
+	lastReviews := make(map[storage.ID]storage.AnswerTry, len(reviewTries))
+	for _, try := range reviewTries {
+		lastReviews[try.Task.ID] = try
+	}
+	return s.fillAnswerData(ctx, req, takenHints, acceptedTasks, lastReviews), nil
 }
 
-func (s *Service) fillAnswerData(ctx context.Context, req *AnswerDataRequest, takenHints storage.HintTakes, acceptedTasks storage.AcceptedTasks) *AnswerDataResponse {
-	taskGroups := make([]AnswerTaskGroup, 0, len(req.TaskGroups))
-	var nextStart *time.Time
+func (s *Service) fillAnswerData(
+	ctx context.Context,
+	req *AnswerDataRequest,
+	takenHints storage.HintTakes,
+	acceptedTasks storage.AcceptedTasks,
+	lastReviews map[storage.ID]storage.AnswerTry,
+) *AnswerDataResponse {
 	now := qtime.Now()
+	answerGroups := make([]AnswerTaskGroup, 0, len(req.TaskGroups))
 	for _, tg := range req.TaskGroups {
-		newTg := AnswerTaskGroup{
-			ID:           tg.ID,
-			OrderIdx:     tg.OrderIdx,
-			Name:         tg.Name,
-			Description:  tg.Description,
-			PubTime:      tg.PubTime,
-			Sticky:       tg.Sticky,
-			Tasks:        make([]AnswerTask, 0, len(tg.Tasks)),
-			HasTimeLimit: tg.HasTimeLimit,
-			TimeLimit:    tg.TimeLimit,
-			TeamInfo:     tg.TeamInfo,
-		}
-
-		for _, t := range tg.Tasks {
-			newT := AnswerTask{
-				ID:               t.ID,
-				OrderIdx:         t.OrderIdx,
-				Name:             t.Name,
-				Question:         t.Question,
-				Reward:           t.Reward,
-				Verification:     t.Verification,
-				VerificationType: t.Verification,
-				Hints:            make([]AnswerTaskHint, len(t.FullHints)),
-				PubTime:          t.PubTime,
-				MediaLink:        t.MediaLink,
-				MediaLinks:       t.MediaLinks,
+		answerGroups = append(answerGroups, newAnswerTaskGroup(req.Quest, &tg, req.Team.ID, takenHints, acceptedTasks, lastReviews, now))
+	}
+
+	var taskGroups []AnswerTaskGroup
+	if req.Quest.QuestType == storage.TypeLinear {
+		var err error
+		if taskGroups, err = s.fillRoute(ctx, req, answerGroups, now); err != nil {
+			// team still gets its route, while groups are closed again on the next request
+			logging.Error(ctx, "error updating closing time", zap.Error(err))
+		}
+	} else {
+		taskGroups = make([]AnswerTaskGroup, 0, len(answerGroups))
+		unlocks := newUnlocks(req.TaskGroups, acceptedTasks)
+		for i, tg := range answerGroups {
+			if isPublished(tg.PubTime, now) && unlocks.met(req.TaskGroups[i].Requires) {
+				taskGroups = append(taskGroups, unlocks.hideLockedTasks(tg, &req.TaskGroups[i]))
 			}
-			if ans, ok := acceptedTasks[t.ID]; ok {
-				newT.Accepted = true
-				newT.Answer = ans.Text
-				newT.Score = ans.Score
+		}
+	}
+
+	resp := &AnswerDataResponse{
+		Quest:      req.Quest,
+		Team:       req.Team,
+		TaskGroups: taskGroups,
+	}
+	return resp
+}
+
+func newAnswerTaskGroup(
+	quest *storage.Quest,
+	tg *storage.TaskGroup,
+	teamID storage.ID,
+	takenHints storage.HintTakes,
+	acceptedTasks storage.AcceptedTasks,
+	lastReviews map[storage.ID]storage.AnswerTry,
+	now time.Time,
+) AnswerTaskGroup {
+	newTg := AnswerTaskGroup{
+		ID:           tg.ID,
+		OrderIdx:     tg.OrderIdx,
+		Name:         tg.Name,
+		Description:  tg.Description,
+		PubTime:      tg.PubTime,
+		Sticky:       tg.Sticky,
+		Tasks:        make([]AnswerTask, 0, len(tg.Tasks)),
+		HasTimeLimit: tg.HasTimeLimit,
+		TimeLimit:    tg.TimeLimit,
+		TeamInfo:     tg.TeamInfo,
+	}
+
+	for _, t := range tg.Tasks {
+		if !isPublished(t.PubTime, now) {
+			continue
+		}
+		newT := AnswerTask{
+			ID:               t.ID,
+			OrderIdx:         t.OrderIdx,
+			Name:             t.Name,
+			Question:         t.Question,
+			Reward:           t.Reward,
+			Verification:     t.Verification,
+			VerificationType: t.Verification,
+			Hints:            make([]AnswerTaskHint, len(t.FullHints)),
+			OrderedHints:     t.OrderedHints,
+			PubTime:          t.PubTime,
+			MediaLink:        t.MediaLink,
+			MediaLinks:       t.MediaLinks,
+			Type:             t.Type,
+		}
+		if t.Type == storage.TaskTypeChoice && t.Choice != nil {
+			newT.Multiple = t.Choice.Multiple
+			newT.Options = shuffledOptions(t.Choice, teamID, t.ID)
+		}
+		if review, ok := lastReviews[t.ID]; ok {
+			newT.ReviewStatus = review.ReviewStatus
+			newT.Answer = review.Answer
+		}
+		if ans, ok := acceptedTasks[t.ID]; ok {
+			newT.Accepted = !ans.Partial
+			newT.Partial = ans.Partial && t.Type == storage.TaskTypeChoice
+			newT.Answer = ans.Text
+			newT.Score = ans.Score
+		}
+		if t.Type == storage.TaskTypeParts {
+			newT.Parts = answerParts(&t, acceptedTasks[t.ID])
+		}
+		for _, h := range takenHints[newT.ID] {
+			newT.Hints[h.Hint.Index].Taken = true
+			newT.Hints[h.Hint.Index].Text = h.Hint.Text
+		}
+		opening := taskOpeningTime(quest, tg, &t)
+		for i, hint := range t.FullHints {
+			newT.Hints[i].Penalty = hint.Penalty
+			newT.Hints[i].AutoPenalty = hint.AutoPenalty
+			if hint.Name != nil {
+				newT.Hints[i].Name = *hint.Name
 			}
-			for _, h := range takenHints[newT.ID] {
-				newT.Hints[h.Hint.Index].Taken = true
-				newT.Hints[h.Hint.Index].Text = h.Hint.Text
+			opensAt := hintOpensAt(&hint, opening)
+			if opensAt == nil || newT.Hints[i].Taken {
+				continue
 			}
-			for i, hint := range t.FullHints {
-				newT.Hints[i].Penalty = hint.Penalty
-				if hint.Name != nil {
-					newT.Hints[i].Name = *hint.Name
-				}
+			if now.Before(*opensAt) {
+				opensIn := storage.Duration(opensAt.Sub(now))
+				newT.Hints[i].OpensAt = opensAt
+				newT.Hints[i].OpensIn = &opensIn
+				continue
 			}
+			newT.Hints[i].Taken = true
+			newT.Hints[i].Auto = true
+			newT.Hints[i].Text = hint.Text
+		}
+
+		newTg.Tasks = append(newTg.Tasks, newT)
+	}
+	return newTg
+}
 
-			newTg.Tasks = append(newTg.Tasks, newT)
+// fillRoute returns groups of team route in linear quest along with sticky ones, closing groups which time limit is exceeded.
+// Errors of closing are returned along with the route, which is built as if groups were closed
+func (s *Service) fillRoute(ctx context.Context, req *AnswerDataRequest, answerGroups []AnswerTaskGroup, now time.Time) ([]AnswerTaskGroup, error) {
+	taskGroups := make([]AnswerTaskGroup, 0, len(answerGroups))
+	var closeErrs []error
+	indexByID := make(map[storage.ID]int, len(answerGroups))
+	for i, tg := range answerGroups {
+		indexByID[tg.ID] = i
+	}
+	nextByOrder := func(idx int) int {
+		for i := idx + 1; i < len(answerGroups); i++ {
+			if !answerGroups[i].Sticky {
+				return i
+			}
 		}
-		if req.Quest.QuestType != storage.TypeLinear || newTg.Sticky {
-			taskGroups = append(taskGroups, newTg)
-			continue
+		return len(answerGroups)
+	}
+	nextOnRoute := func(idx int) int {
+		if teamInfo := answerGroups[idx].TeamInfo; teamInfo.NextGroupID != nil {
+			if next, ok := indexByID[*teamInfo.NextGroupID]; ok {
+				return next
+			}
+		}
+		return nextByOrder(idx)
+	}
+	// sticky groups with index less than stickyIdx are already added
+	var stickyIdx int
+	addSticky := func(upTo int) {
+		for ; stickyIdx < upTo; stickyIdx++ {
+			if tg := answerGroups[stickyIdx]; tg.Sticky && isPublished(tg.PubTime, now) {
+				taskGroups = append(taskGroups, tg)
+			}
+		}
+	}
+
+	var nextStart *time.Time
+	// justClosed is set when previous group was closed by time limit during this call
+	var justClosed bool
+	visited := make(map[int]struct{}, len(answerGroups))
+	for idx := nextByOrder(-1); idx < len(answerGroups); {
+		if _, ok := visited[idx]; ok {
+			// routes are validated on update, but broken one should not hang the request
+			logging.Error(ctx, "task group route has a cycle", zap.Stringer("task_group_id", answerGroups[idx].ID))
+			break
+		}
+		visited[idx] = struct{}{}
+		addSticky(idx)
+
+		newTg := answerGroups[idx]
+		if !isPublished(newTg.PubTime, now) {
+			// linear quest cannot be continued until next group is published
+			return taskGroups, errors.Join(closeErrs...)
 		}
 		if newTg.TeamInfo == nil && nextStart != nil {
 			newTg.TeamInfo = &storage.TaskGroupTeamInfo{
 				OpeningTime: *nextStart,
 			}
 			nextStart = nil
+			if justClosed {
+				s.events.Add(events.New(events.GroupOpened, req.Quest.ID, req.Team.ID, events.GroupData{TaskGroupID: newTg.ID}))
+			}
 		}
+		justClosed = false
 		if newTg.TeamInfo != nil && newTg.TeamInfo.ClosingTime != nil {
 			taskGroups = append(taskGroups, newTg)
+			answerGroups[idx] = newTg
+			idx = nextOnRoute(idx)
 			continue
 		}
-		if newTg.HasTimeLimit && newTg.TimeLimit != nil {
+		if newTg.HasTimeLimit && newTg.TimeLimit != nil && newTg.TeamInfo != nil {
 			deadline := newTg.TeamInfo.OpeningTime.Add(time.Duration(*newTg.TimeLimit))
 			if deadline.Before(now) {
 				newTg.TeamInfo.ClosingTime = &deadline
+				if transition := defaultTransition(&req.TaskGroups[idx]); transition != nil {
+					newTg.TeamInfo.NextGroupID = &transition.NextGroupID
+				}
 				if _, err := s.tgs.UpsertTeamInfo(ctx, &storage.UpsertTeamInfoRequest{
 					TeamID:      req.Team.ID,
 					TaskGroupID: newTg.ID,
 					OpeningTime: newTg.TeamInfo.OpeningTime,
 					ClosingTime: &deadline,
+					NextGroupID: newTg.TeamInfo.NextGroupID,
 				}); err != nil {
-					logging.Error(ctx, "error updating closing time", zap.Error(err))
+					closeErrs = append(closeErrs, xerrors.Errorf("close task group %q: %w", newTg.ID, err))
+				} else {
+					s.events.Add(events.New(events.GroupClosed, req.Quest.ID, req.Team.ID, events.GroupData{TaskGroupID: newTg.ID, Reason: events.GroupTimeLimit}))
+					justClosed = true
 				}
 				taskGroups = append(taskGroups, newTg)
+				answerGroups[idx] = newTg
 				nextStart = &deadline
+				idx = nextOnRoute(idx)
 				continue
 			}
 		}
 		taskGroups = append(taskGroups, newTg)
-		break
+		return taskGroups, errors.Join(closeErrs...)
 	}
-
-	resp := &AnswerDataResponse{
-		Quest:      req.Quest,
-		Team:       req.Team,
-		TaskGroups: taskGroups,
-	}
-	return resp
+	addSticky(len(answerGroups))
+	return taskGroups, errors.Join(closeErrs...)
 }
 
 type TaskResult struct {
-	Score      int
-	groupIndex int
-	taskIndex  int
+	Score int
+	Bonus int
+	Decay int
+	// SolvedParts of Parts are set for multi-part tasks
+	SolvedParts int
+	Parts       int
+	groupIndex  int
+	taskIndex   int
 }
 
 type TeamResult struct {
-	TeamID                storage.ID
-	TeamName              string
-	TotalScore            int
-	TaskScore             int
-	Penalty               int
-	TaskResults           []TaskResult
+	TeamID     storage.ID
+	TeamName   string
+	TotalScore int
+	TaskScore  int
+	// Penalty is the sum of WrongAnswerPenalty and values of Adjustments, which itemize the rest of it
+	Penalty            int
+	WrongAnswerPenalty int
+	BonusCodes         int
+	TaskResults        []TaskResult
+	// Adjustments are not revoked entries of team penalty ledger, they are the only source of penalties besides wrong answers
+	Adjustments           []storage.Adjustment
 	lastCorrectAnswerTime *time.Time
 }
 
@@ -214,9 +399,27 @@ func (t TeamResult) MarshalJSON() ([]byte, error) {
 	resJSONMap["total_score"] = t.TotalScore
 	resJSONMap["task_score"] = t.TaskScore
 	resJSONMap["penalty"] = t.Penalty
+	if t.WrongAnswerPenalty != 0 {
+		resJSONMap["wrong_answer_penalty"] = t.WrongAnswerPenalty
+	}
+	if t.BonusCodes != 0 {
+		resJSONMap["bonus_codes"] = t.BonusCodes
+	}
+	if len(t.Adjustments) > 0 {
+		resJSONMap["adjustments"] = t.Adjustments
+	}
 	for _, result := range t.TaskResults {
 		taskKey := fmt.Sprintf("task_%d_%d_score", result.groupIndex, result.taskIndex)
 		resJSONMap[taskKey] = result.Score
+		if result.Bonus != 0 {
+			resJSONMap[fmt.Sprintf("task_%d_%d_bonus", result.groupIndex, result.taskIndex)] = result.Bonus
+		}
+		if result.Decay != 0 {
+			resJSONMap[fmt.Sprintf("task_%d_%d_decay", result.groupIndex, result.taskIndex)] = result.Decay
+		}
+		if result.Parts > 0 {
+			resJSONMap[fmt.Sprintf("task_%d_%d_solved_parts", result.groupIndex, result.taskIndex)] = result.SolvedParts
+		}
 	}
 
 	res, err := json.Marshal(resJSONMap)
@@ -251,23 +454,40 @@ func (s *Service) GetResults(ctx context.Context, questID storage.ID) (*TeamResu
 	if err != nil {
 		return nil, xerrors.Errorf("get penalties: %w", err)
 	}
+	bonusCodes, err := s.ah.GetBonusCodeUses(ctx, &storage.GetBonusCodeUsesRequest{QuestID: questID})
+	if err != nil {
+		return nil, xerrors.Errorf("get bonus codes: %w", err)
+	}
+	adjustments, err := s.ah.GetAdjustments(ctx, &storage.GetAdjustmentsRequest{QuestID: questID, ActiveOnly: true})
+	if err != nil {
+		return nil, xerrors.Errorf("get adjustments: %w", err)
+	}
+	teamAdjustments := make(map[storage.ID][]storage.Adjustment)
+	for _, a := range adjustments {
+		teamAdjustments[a.TeamID] = append(teamAdjustments[a.TeamID], a)
+	}
 
 	var res TeamResults
 	for _, team := range teams {
 		teamScore := results[team.ID]
 		teamPenalties := penalties[team.ID]
 		teamRes := TeamResult{
-			TeamID:   team.ID,
-			TeamName: team.Name,
+			TeamID:      team.ID,
+			TeamName:    team.Name,
+			Adjustments: teamAdjustments[team.ID],
 		}
 		for i, tg := range taskGroups {
 			for j, task := range tg.Tasks {
 				taskRes := TaskResult{
+					Parts:      len(task.Parts),
 					groupIndex: i,
 					taskIndex:  j,
 				}
 				if scoreRes, ok := teamScore[task.ID]; ok {
 					taskRes.Score = scoreRes.Score
+					taskRes.Bonus = scoreRes.Bonus
+					taskRes.Decay = scoreRes.Decay
+					taskRes.SolvedParts = scoreRes.SolvedParts
 					teamRes.TaskScore += scoreRes.Score
 					teamRes.TotalScore += scoreRes.Score
 					if teamRes.lastCorrectAnswerTime == nil {
@@ -280,8 +500,19 @@ func (s *Service) GetResults(ctx context.Context, questID storage.ID) (*TeamResu
 			}
 		}
 		for _, p := range teamPenalties {
-			teamRes.Penalty += p.Value
-			teamRes.TotalScore -= p.Value
+			// entries of the ledger are counted below as adjustments
+			if p.Source == storage.PenaltySourceWrongAnswer {
+				teamRes.WrongAnswerPenalty += p.Value
+			}
+		}
+		teamRes.Penalty = teamRes.WrongAnswerPenalty
+		for _, a := range teamRes.Adjustments {
+			teamRes.Penalty += a.Value
+		}
+		teamRes.TotalScore -= teamRes.Penalty
+		for _, use := range bonusCodes[team.ID] {
+			teamRes.BonusCodes += use.Value
+			teamRes.TotalScore += use.Value
 		}
 		res.Results = append(res.Results, teamRes)
 	}
@@ -301,17 +532,23 @@ func (s *Service) GetResults(ctx context.Context, questID storage.ID) (*TeamResu
 }
 
 type LeaderboardRow struct {
-	TeamID                storage.ID `json:"team_id"`
-	TeamName              string     `json:"team_name"`
-	Score                 int        `json:"score"`
+	TeamID   storage.ID `json:"team_id"`
+	TeamName string     `json:"team_name"`
+	Score    int        `json:"score"`
+	// BonusCodes is a part of score earned by bonus codes
+	BonusCodes            int `json:"bonus_codes,omitempty"`
 	lastCorrectAnswerTime *time.Time
 }
 
 type LeaderboardResponse struct {
 	Rows []LeaderboardRow `json:"rows"`
+	// FrozenAt is set while leaderboard is frozen. Teams see results as of this moment, while quest creator sees live ones
+	FrozenAt *time.Time `json:"frozen_at,omitempty"`
 }
 
-func (s *Service) GetLeaderboard(ctx context.Context, questID storage.ID) (*LeaderboardResponse, error) {
+// GetLeaderboard returns quest leaderboard as seen by user, who may be nil for anonymous requests
+func (s *Service) GetLeaderboard(ctx context.Context, quest *storage.Quest, user *storage.User) (*LeaderboardResponse, error) {
+	questID := quest.ID
 	teams, err := s.tms.GetTeams(ctx, &storage.GetTeamsRequest{QuestIDs: []storage.ID{questID}, AcceptedOnly: true})
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -319,15 +556,21 @@ func (s *Service) GetLeaderboard(ctx context.Context, questID storage.ID) (*Lead
 		}
 		return nil, xerrors.Errorf("get teams: %w", err)
 	}
-	results, err := s.ah.GetScoreResults(ctx, &storage.GetResultsRequest{QuestID: questID})
+	var res LeaderboardResponse
+	res.FrozenAt = quests.LeaderboardFreezeTime(quest)
+	before := visibleResultsBefore(quest, user)
+	results, err := s.ah.GetScoreResults(ctx, &storage.GetResultsRequest{QuestID: questID, Before: before})
 	if err != nil {
 		return nil, xerrors.Errorf("get results: %w", err)
 	}
-	penalties, err := s.ah.GetPenalties(ctx, &storage.GetPenaltiesRequest{QuestID: questID})
+	penalties, err := s.ah.GetPenalties(ctx, &storage.GetPenaltiesRequest{QuestID: questID, Before: before})
 	if err != nil {
 		return nil, xerrors.Errorf("get penalties: %w", err)
 	}
-	var res LeaderboardResponse
+	bonusCodes, err := s.ah.GetBonusCodeUses(ctx, &storage.GetBonusCodeUsesRequest{QuestID: questID, Before: before})
+	if err != nil {
+		return nil, xerrors.Errorf("get bonus codes: %w", err)
+	}
 	for _, team := range teams {
 		teamScore := results[team.ID]
 		teamPenalties := penalties[team.ID]
@@ -346,6 +589,10 @@ func (s *Service) GetLeaderboard(ctx context.Context, questID storage.ID) (*Lead
 		for _, p := range teamPenalties {
 			teamRes.Score -= p.Value
 		}
+		for _, use := range bonusCodes[team.ID] {
+			teamRes.BonusCodes += use.Value
+			teamRes.Score += use.Value
+		}
 		res.Rows = append(res.Rows, teamRes)
 	}
 	sort.Slice(res.Rows, func(i, j int) bool {
@@ -377,10 +624,16 @@ func (s *Service) TakeHint(ctx context.Context, user *storage.User, req *TakeHin
 	if team.RegistrationStatus != storage.RegistrationStatusAccepted {
 		return nil, httperrors.New(http.StatusForbidden, "only accepted teams can take hints")
 	}
+	if err = checkQuestRunning(team.Quest); err != nil {
+		return nil, err
+	}
 	task, err := s.ts.GetTask(ctx, &storage.GetTaskRequest{
 		ID: req.TaskID,
 	})
 	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.Errorf(http.StatusNotFound, "task %q not found", req.TaskID)
+		}
 		return nil, xerrors.Errorf("get task: %w", err)
 	}
 	taskGroup, err := s.tgs.GetTaskGroup(ctx, &storage.GetTaskGroupRequest{
@@ -391,6 +644,9 @@ func (s *Service) TakeHint(ctx context.Context, user *storage.User, req *TakeHin
 		return nil, xerrors.Errorf("get task group: %w", err)
 	}
 	now := qtime.Now()
+	if err = checkTaskPublished(taskGroup, task, now); err != nil {
+		return nil, err
+	}
 	if team.Quest.QuestType == storage.TypeLinear {
 		if taskGroup.TeamInfo == nil {
 			return nil, httperrors.Errorf(http.StatusNotAcceptable, "task %q cannot be accessed because task group is closed", req.TaskID)
@@ -401,13 +657,8 @@ func (s *Service) TakeHint(ctx context.Context, user *storage.User, req *TakeHin
 		if taskGroup.HasTimeLimit && taskGroup.TimeLimit != nil {
 			deadline := taskGroup.TeamInfo.OpeningTime.Add(time.Duration(*taskGroup.TimeLimit))
 			if deadline.Before(now) {
-				if _, err = s.tgs.UpsertTeamInfo(ctx, &storage.UpsertTeamInfoRequest{
-					TeamID:      team.ID,
-					TaskGroupID: taskGroup.ID,
-					OpeningTime: taskGroup.TeamInfo.OpeningTime,
-					ClosingTime: &deadline,
-				}); err != nil {
-					logging.Error(ctx, "could not upsert team info", zap.Error(err))
+				if err = s.closeGroup(ctx, team, taskGroup, deadline, events.GroupTimeLimit, nil); err != nil {
+					logging.Error(ctx, "could not close task group", zap.Error(err))
 				}
 				return nil, httperrors.Errorf(http.StatusNotAcceptable, "task %q deadline exceeded", req.TaskID)
 			}
@@ -418,9 +669,14 @@ func (s *Service) TakeHint(ctx context.Context, user *storage.User, req *TakeHin
 	if err != nil {
 		return nil, xerrors.Errorf("get results: %w", err)
 	}
-	if _, ok := accepted[req.TaskID]; ok {
+	if accepted.Solved(req.TaskID) {
 		return nil, httperrors.Errorf(http.StatusNotAcceptable, "question %q already accepted", req.TaskID)
 	}
+	if team.Quest.QuestType == storage.TypeAssault {
+		if err = s.checkUnlocked(ctx, team.Quest.ID, taskGroup, task, accepted); err != nil {
+			return nil, err
+		}
+	}
 	answerData, err := s.ts.GetAnswerData(ctx, &storage.GetTaskRequest{ID: req.TaskID})
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -431,6 +687,20 @@ func (s *Service) TakeHint(ctx context.Context, user *storage.User, req *TakeHin
 	if len(answerData.FullHints) <= req.Index {
 		return nil, httperrors.Errorf(http.StatusBadRequest, "index %d out of hints range", req.Index)
 	}
+	opening := taskOpeningTime(team.Quest, taskGroup, answerData)
+	if hint := answerData.FullHints[req.Index]; hintOpened(&hint, opening, now) {
+		// hint is already revealed automatically, so taking it costs nothing
+		return &hint, nil
+	}
+	if answerData.OrderedHints {
+		takenHints, err := s.ah.GetHintTakes(ctx, &storage.GetHintTakesRequest{TeamID: team.ID, TaskID: req.TaskID, QuestID: req.QuestID})
+		if err != nil {
+			return nil, xerrors.Errorf("get hints: %w", err)
+		}
+		if err = checkHintOrder(answerData, takenHints[req.TaskID], req.Index, opening, now); err != nil {
+			return nil, err
+		}
+	}
 	hint, err := s.ah.TakeHint(ctx, &storage.TakeHintRequest{TeamID: team.ID, TaskID: req.TaskID, Index: req.Index})
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -438,6 +708,7 @@ func (s *Service) TakeHint(ctx context.Context, user *storage.User, req *TakeHin
 		}
 		return nil, xerrors.Errorf("get hint: %w", err)
 	}
+	s.events.Add(events.New(events.HintTaken, team.Quest.ID, team.ID, events.HintTakenData{TaskID: req.TaskID, Index: req.Index, UserID: user.ID}))
 	return hint, nil
 }
 
@@ -445,12 +716,31 @@ type TryAnswerRequest struct {
 	QuestID storage.ID `json:"-"`
 	TaskID  storage.ID `json:"task_id"`
 	Text    string     `json:"text"`
+	// Choices contain indexes of options selected for choice task
+	Choices []int `json:"choices,omitempty"`
+	// File is already saved to blob store by the caller
+	File *storage.AnswerFile `json:"-"`
+	// Position of the team device for location task
+	Position *geo.Point `json:"position,omitempty"`
+	// Part is index of answered part of multi-part task
+	Part *int `json:"part,omitempty"`
 }
 
 type TryAnswerResponse struct {
-	Accepted   bool              `json:"accepted"`
-	Score      int               `json:"score"`
-	Text       string            `json:"text"`
+	Accepted     bool                 `json:"accepted"`
+	Score        int                  `json:"score"`
+	Text         string               `json:"text"`
+	ReviewStatus storage.ReviewStatus `json:"review_status,omitempty" enums:"PENDING"`
+	// Penalty is subtracted from team score for wrong answer
+	Penalty int `json:"penalty,omitempty"`
+	// AttemptsLeft is set only when task has limited number of attempts
+	AttemptsLeft *int `json:"attempts_left,omitempty"`
+	// Partial is set when answer solved a part of multi-part task, while other parts are still unsolved,
+	// or when choice answer is partially correct
+	Partial bool `json:"partial,omitempty"`
+	// Bonus and Decay show how task scoring modifiers changed Score
+	Bonus      int               `json:"bonus,omitempty"`
+	Decay      int               `json:"decay,omitempty"`
 	TaskGroups []AnswerTaskGroup `json:"task_groups,omitempty"`
 }
 
@@ -465,12 +755,20 @@ func (s *Service) TryAnswer(ctx context.Context, user *storage.User, req *TryAns
 	if team.RegistrationStatus != storage.RegistrationStatusAccepted {
 		return nil, httperrors.New(http.StatusForbidden, "only accepted teams can answer tasks")
 	}
+	if err = checkQuestRunning(team.Quest); err != nil {
+		return nil, err
+	}
+	// concurrent tries of team are serialized, so that they cannot exceed attempt limits or solve task twice
+	if err = s.ah.LockAnswerTries(ctx, &storage.LockAnswerTriesRequest{TaskID: req.TaskID, TeamID: team.ID}); err != nil {
+		return nil, xerrors.Errorf("lock answer tries: %w", err)
+	}
 	acceptedTasks, err := s.ah.GetAcceptedTasks(ctx, &storage.GetAcceptedTasksRequest{TeamID: team.ID, QuestID: req.QuestID})
 	if err != nil {
 		return nil, xerrors.Errorf("get results: %w", err)
 	}
-	if acceptedTask, ok := acceptedTasks[req.TaskID]; ok {
-		return &TryAnswerResponse{Accepted: true, Text: acceptedTask.Text, Score: acceptedTask.Score}, nil
+	// partially correct choice is final, while parts of multi-part task are answered one by one
+	if acceptedTask, ok := acceptedTasks[req.TaskID]; ok && (!acceptedTask.Partial || acceptedTask.Parts == nil) {
+		return &TryAnswerResponse{Accepted: true, Text: acceptedTask.Text, Score: acceptedTask.Score, Partial: acceptedTask.Partial}, nil
 	}
 
 	answerData, err := s.ts.GetAnswerData(ctx, &storage.GetTaskRequest{ID: req.TaskID})
@@ -480,6 +778,15 @@ func (s *Service) TryAnswer(ctx context.Context, user *storage.User, req *TryAns
 		}
 		return nil, xerrors.Errorf("get answer data: %w", err)
 	}
+	if answerData.Type == storage.TaskTypeFile && req.File == nil {
+		return nil, httperrors.Errorf(http.StatusBadRequest, "task %q should be answered with file", req.TaskID)
+	}
+	if answerData.Type != storage.TaskTypeFile && req.File != nil {
+		return nil, httperrors.Errorf(http.StatusBadRequest, "task %q cannot be answered with file", req.TaskID)
+	}
+	if answerData.Type != storage.TaskTypeParts && req.Part != nil {
+		return nil, httperrors.Errorf(http.StatusBadRequest, "task %q has no parts", req.TaskID)
+	}
 	taskGroup, err := s.tgs.GetTaskGroup(ctx, &storage.GetTaskGroupRequest{
 		ID:           answerData.Group.ID,
 		IncludeTasks: true,
@@ -489,6 +796,9 @@ func (s *Service) TryAnswer(ctx context.Context, user *storage.User, req *TryAns
 		return nil, xerrors.Errorf("get task group: %w", err)
 	}
 	now := qtime.Now()
+	if err = checkTaskPublished(taskGroup, answerData, now); err != nil {
+		return nil, err
+	}
 	if team.Quest.QuestType == storage.TypeLinear && !taskGroup.Sticky {
 		if taskGroup.TeamInfo == nil {
 			return nil, httperrors.Errorf(http.StatusNotAcceptable, "task %q cannot be accessed because task group is closed", req.TaskID)
@@ -499,33 +809,94 @@ func (s *Service) TryAnswer(ctx context.Context, user *storage.User, req *TryAns
 		if taskGroup.HasTimeLimit && taskGroup.TimeLimit != nil {
 			deadline := taskGroup.TeamInfo.OpeningTime.Add(time.Duration(*taskGroup.TimeLimit))
 			if deadline.Before(now) {
-				if _, err = s.tgs.UpsertTeamInfo(ctx, &storage.UpsertTeamInfoRequest{
-					TeamID:      team.ID,
-					TaskGroupID: taskGroup.ID,
-					OpeningTime: taskGroup.TeamInfo.OpeningTime,
-					ClosingTime: &deadline,
-				}); err != nil {
-					logging.Error(ctx, "could not upsert team info", zap.Error(err))
+				if err = s.closeGroup(ctx, team, taskGroup, deadline, events.GroupTimeLimit, nil); err != nil {
+					logging.Error(ctx, "could not close task group", zap.Error(err))
 				}
 				return nil, httperrors.Errorf(http.StatusNotAcceptable, "task %q deadline exceeded", req.TaskID)
 			}
 		}
 	}
 
-	accepted := false
-	for _, correctAnswer := range answerData.CorrectAnswers {
-		trimmedCorrect := strings.TrimSpace(correctAnswer)
-		trimmedAnswer := strings.TrimSpace(req.Text)
-		if strings.EqualFold(trimmedCorrect, trimmedAnswer) {
-			accepted = true
-			break
+	if team.Quest.QuestType == storage.TypeAssault {
+		if err = s.checkUnlocked(ctx, team.Quest.ID, taskGroup, answerData, acceptedTasks); err != nil {
+			return nil, err
+		}
+	}
+
+	limits := mergeAnswerLimits(answerData.AnswerLimits, team.Quest.AnswerLimits)
+	var tryStats *storage.AnswerTryStats
+	if limits != nil {
+		tryStats, err = s.ah.GetAnswerTryStats(ctx, &storage.GetAnswerTryStatsRequest{TeamID: team.ID, TaskID: req.TaskID})
+		if err != nil {
+			return nil, xerrors.Errorf("get answer try stats: %w", err)
+		}
+		if err = checkAnswerLimits(limits, tryStats, now); err != nil {
+			return nil, err
 		}
 	}
+
+	var accepted, partialChoice bool
+	switch answerData.Type {
+	case storage.TaskTypeFile:
+		// uploaded files are verified by organizers only
+	case storage.TaskTypeChoice:
+		if answerData.Choice == nil {
+			return nil, xerrors.Errorf("choice task %q has no options", req.TaskID)
+		}
+		share, err := choiceShare(answerData.Choice, req.Choices)
+		if err != nil {
+			return nil, err
+		}
+		req.Text = choiceAnswerText(answerData.Choice, req.Choices)
+		accepted = share > 0
+		if accepted && share < 1 {
+			// partially correct selection is scored as task with reduced reward, but is not a solve
+			partialChoice = true
+			answerData = withReward(answerData, int(float64(answerData.Reward)*share))
+			if answerData.Scoring != nil {
+				answerData.Scoring.FirstSolveBonuses = nil
+			}
+		}
+	case storage.TaskTypeLocation:
+		if answerData.Location == nil {
+			return nil, xerrors.Errorf("location task %q has no location", req.TaskID)
+		}
+		if accepted, err = checkPosition(answerData.Location, req.Position); err != nil {
+			return nil, err
+		}
+		req.Text = positionText(req.Position)
+	case storage.TaskTypeParts:
+		part, err := answerPart(answerData, req.Part, acceptedTasks[req.TaskID])
+		if err != nil {
+			return nil, err
+		}
+		matcher, err := matchers.New(answerData.Matcher, answerData.Parts[part].CorrectAnswers)
+		if err != nil {
+			return nil, xerrors.Errorf("task %q: %w", req.TaskID, err)
+		}
+		accepted = matcher.Match(req.Text)
+		// every part is scored and penalized as task with its share of reward
+		answerData = withReward(answerData, partReward(answerData, part))
+	default:
+		matcher, err := matchers.New(answerData.Matcher, answerData.CorrectAnswers)
+		if err != nil {
+			return nil, xerrors.Errorf("task %q: %w", req.TaskID, err)
+		}
+		accepted = matcher.Match(req.Text)
+	}
 	tryReq := storage.CreateAnswerTryRequest{
 		TaskID: req.TaskID,
 		TeamID: team.ID,
 		UserID: user.ID,
 		Text:   req.Text,
+		File:   req.File,
+		Part:   req.Part,
+	}
+
+	if answerData.Verification == storage.VerificationManual {
+		tryReq.ReviewStatus = storage.ReviewStatusPending
+	} else if !accepted && limits != nil && limits.WrongAnswerPenalty != nil {
+		tryReq.Penalty = limits.WrongAnswerPenalty.GetPenaltyPoints(answerData.Reward)
 	}
 
 	if !accepted || answerData.Verification == storage.VerificationManual {
@@ -534,59 +905,98 @@ func (s *Service) TryAnswer(ctx context.Context, user *storage.User, req *TryAns
 			zap.String("team_name", team.Name),
 			zap.Stringer("task_id", req.TaskID),
 			zap.String("text", req.Text),
+			zap.Int("penalty", tryReq.Penalty),
 		)
 		if err = s.ah.CreateAnswerTry(ctx, &tryReq); err != nil {
 			return nil, xerrors.Errorf("create answer try: %w", err)
 		}
-		return &TryAnswerResponse{Accepted: false, Text: req.Text}, nil
+		if tryReq.Penalty > 0 {
+			s.events.Add(events.New(events.PenaltyAdded, team.Quest.ID, team.ID, events.PenaltyData{Penalty: tryReq.Penalty, TaskID: req.TaskID}))
+		}
+		return &TryAnswerResponse{
+			Accepted:     false,
+			Text:         req.Text,
+			ReviewStatus: tryReq.ReviewStatus,
+			Penalty:      tryReq.Penalty,
+			AttemptsLeft: attemptsLeft(limits, tryStats),
+		}, nil
 	}
 
 	takenHints, err := s.ah.GetHintTakes(ctx, &storage.GetHintTakesRequest{TeamID: team.ID, TaskID: req.TaskID, QuestID: req.QuestID})
 	if err != nil {
 		return nil, xerrors.Errorf("get hints: %w", err)
 	}
-	taskHints := takenHints[req.TaskID]
-	penalty := 0
-	for _, h := range taskHints {
-		penalty += h.Hint.Penalty.GetPenaltyPoints(answerData.Reward)
+	taskHints := withAutoHints(takenHints[req.TaskID], answerData, taskOpeningTime(team.Quest, taskGroup, answerData), now)
+	if req.Part != nil {
+		taskHints = partHints(taskHints, answerData.Parts[*req.Part].Share)
+	}
+	score, err := s.getTaskScore(ctx, team.Quest, taskGroup, answerData, taskHints, now)
+	if err != nil {
+		return nil, xerrors.Errorf("get task score: %w", err)
 	}
-	score := answerData.Reward - penalty
 	tryReq.Accepted = true
-	tryReq.Score = score
+	tryReq.Partial = partialChoice
+	tryReq.Score = score.Score
+	tryReq.Bonus = score.Bonus
+	tryReq.Decay = score.Decay
 	logging.Info(ctx, "answer try",
 		zap.Stringer("team_id", team.ID),
 		zap.String("team_name", team.Name),
 		zap.Stringer("task_id", req.TaskID),
 		zap.String("text", req.Text),
-		zap.Int("reward", score),
+		zap.Int("reward", score.Score),
+		zap.Int("bonus", score.Bonus),
+		zap.Int("decay", score.Decay),
 		zap.Any("taken_hints", taskHints),
 	)
 
 	if err = s.ah.CreateAnswerTry(ctx, &tryReq); err != nil {
 		return nil, xerrors.Errorf("create answer try: %w", err)
 	}
-	acceptedTasks[req.TaskID] = storage.AcceptedTask{
-		Score: score,
-		Text:  req.Text,
+	partial := partialChoice
+	if req.Part != nil {
+		partial = acceptPart(acceptedTasks, answerData, *req.Part, req.Text, score.Score).Partial
+	} else {
+		acceptedTasks[req.TaskID] = storage.AcceptedTask{
+			Score:   score.Score,
+			Text:    req.Text,
+			Partial: partialChoice,
+		}
 	}
+	s.events.Add(events.New(events.TaskAccepted, team.Quest.ID, team.ID, events.TaskAcceptedData{
+		TaskGroupID: taskGroup.ID,
+		TaskID:      req.TaskID,
+		UserID:      user.ID,
+		Score:       score.Score,
+		Part:        req.Part,
+	}))
 
-	if allSolved(acceptedTasks, taskGroup.Tasks) {
-		if _, err = s.tgs.UpsertTeamInfo(ctx, &storage.UpsertTeamInfoRequest{
-			TeamID:      team.ID,
-			TaskGroupID: taskGroup.ID,
-			OpeningTime: taskGroup.TeamInfo.OpeningTime,
-			ClosingTime: &now,
-		}); err != nil {
-			return nil, xerrors.Errorf("upsert team info: %w", err)
+	var transition *storage.Transition
+	if team.Quest.QuestType == storage.TypeLinear && !taskGroup.Sticky && !partial {
+		if transition, err = answerTransition(taskGroup, req.TaskID, req.Text); err != nil {
+			return nil, xerrors.Errorf("get answer transition: %w", err)
 		}
 	}
+	if reason := closedGroupReason(transition, acceptedTasks, taskGroup.Tasks); len(reason) > 0 {
+		if err = s.closeGroup(ctx, team, taskGroup, now, reason, transition); err != nil {
+			return nil, xerrors.Errorf("close task group: %w", err)
+		}
+	}
+
+	return &TryAnswerResponse{Accepted: true, Text: req.Text, Score: score.Score, Bonus: score.Bonus, Decay: score.Decay, Partial: partial}, nil
+}
 
-	return &TryAnswerResponse{Accepted: true, Text: req.Text, Score: score}, nil
+func scoreWithHints(reward int, takenHints []storage.HintTake) int {
+	penalty := 0
+	for _, h := range takenHints {
+		penalty += h.Hint.Penalty.GetPenaltyPoints(reward)
+	}
+	return reward - penalty
 }
 
 func allSolved(accepted storage.AcceptedTasks, tasks []storage.Task) bool {
 	for _, task := range tasks {
-		if _, ok := accepted[task.ID]; !ok {
+		if !accepted.Solved(task.ID) {
 			return false
 		}
 	}
@@ -599,20 +1009,42 @@ type AddPenaltyRequest struct {
 	Penalty int        `json:"penalty"`
 }
 
-func (s *Service) AddPenalty(ctx context.Context, req *AddPenaltyRequest) error {
-	team, err := s.tms.GetTeam(ctx, &storage.GetTeamRequest{ID: req.TeamID})
+// AddPenalty sets manual penalty of the team to the given value. Entries previously set this way are revoked,
+// while other ledger entries stay intact. To add itemized entry to the ledger use AddAdjustment
+func (s *Service) AddPenalty(ctx context.Context, user *storage.User, req *AddPenaltyRequest) error {
+	if err := s.checkQuestTeam(ctx, req.QuestID, req.TeamID); err != nil {
+		return err
+	}
+	adjustments, err := s.ah.GetAdjustments(ctx, &storage.GetAdjustmentsRequest{QuestID: req.QuestID, TeamID: req.TeamID, ActiveOnly: true})
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			return httperrors.Errorf(http.StatusNotFound, "team %q not found", req.TeamID)
+		return xerrors.Errorf("get adjustments: %w", err)
+	}
+	for _, a := range adjustments {
+		if !a.Legacy {
+			continue
+		}
+		if err = s.ah.RevokeAdjustment(ctx, &storage.RevokeAdjustmentRequest{ID: a.ID, QuestID: req.QuestID, UserID: user.ID}); err != nil {
+			return xerrors.Errorf("revoke adjustment %d: %w", a.ID, err)
 		}
-		return xerrors.Errorf("get team: %w", err)
+		s.events.Add(events.New(events.PenaltyRevoked, req.QuestID, req.TeamID, events.PenaltyData{
+			ID:      a.ID,
+			Penalty: a.Value,
+			Reason:  a.Reason,
+			TaskID:  a.TaskID,
+		}))
 	}
-	if team.Quest.ID != req.QuestID {
-		return httperrors.Errorf(http.StatusForbidden, "team %q belongs to quest %q", req.TeamID, req.QuestID)
+	if req.Penalty == 0 {
+		return nil
 	}
-	if err := s.ah.CreatePenalty(ctx, &storage.CreatePenaltyRequest{TeamID: req.TeamID, Penalty: req.Penalty}); err != nil {
+	if err = s.ah.CreatePenalty(ctx, &storage.CreatePenaltyRequest{
+		TeamID:   req.TeamID,
+		Penalty:  req.Penalty,
+		AuthorID: user.ID,
+		Legacy:   true,
+	}); err != nil {
 		return xerrors.Errorf("create penalty: %w", err)
 	}
+	s.events.Add(events.New(events.PenaltyAdded, req.QuestID, req.TeamID, events.PenaltyData{Penalty: req.Penalty}))
 	return nil
 }
 
@@ -629,6 +1061,10 @@ type AnswerLog struct {
 	Answer      string     `json:"answer"`
 	AnswerTime  time.Time  `json:"answer_time"`
 	Score       int        `json:"score"`
+	// File can be downloaded with GET /quest/{id}/answer-file/{key}
+	File *storage.AnswerFile `json:"file,omitempty"`
+	// BonusCode is set for bonus code entered by team, which answer is the code
+	BonusCode bool `json:"bonus_code,omitempty"`
 }
 
 type AnswerLogResponse struct {
@@ -643,24 +1079,8 @@ func (s *Service) GetAnswerLogs(ctx context.Context, user *storage.User, questID
 		return AnswerLogResponse{}, xerrors.Errorf("get answer tries: %w", err)
 	}
 	logs := make([]AnswerLog, 0, len(logResp.AnswerLogs))
-	for _, log := range logResp.AnswerLogs {
-		al := AnswerLog{
-			TeamID:      log.Team.ID,
-			Team:        log.Team.Name,
-			TaskGroupID: log.TaskGroup.ID,
-			TaskGroup:   log.TaskGroup.Name,
-			TaskID:      log.Task.ID,
-			Task:        log.Task.Name,
-			Accepted:    log.Accepted,
-			Answer:      log.Answer,
-			AnswerTime:  log.AnswerTime,
-			Score:       log.Score,
-		}
-		if log.User != nil {
-			al.UserID = log.User.ID
-			al.User = log.User.Username
-		}
-		logs = append(logs, al)
+	for i := range logResp.AnswerLogs {
+		logs = append(logs, newAnswerLog(&logResp.AnswerLogs[i]))
 	}
 	resp := AnswerLogResponse{
 		AnswerLogs:    logs,
@@ -669,3 +1089,37 @@ func (s *Service) GetAnswerLogs(ctx context.Context, user *storage.User, questID
 	}
 	return resp, nil
 }
+
+// IterAnswerLogs calls fn for every answer log matching filters without loading the whole log into memory
+func (s *Service) IterAnswerLogs(ctx context.Context, questID storage.ID, fn func(*AnswerLog) error, opts ...storage.FilteringOption) error {
+	err := s.ah.IterAnswerTries(ctx, &storage.GetAnswerTriesRequest{QuestID: questID}, func(log *storage.AnswerLog) error {
+		al := newAnswerLog(log)
+		return fn(&al)
+	}, opts...)
+	if err != nil {
+		return xerrors.Errorf("iter answer tries: %w", err)
+	}
+	return nil
+}
+
+func newAnswerLog(log *storage.AnswerLog) AnswerLog {
+	al := AnswerLog{
+		TeamID:      log.Team.ID,
+		Team:        log.Team.Name,
+		TaskGroupID: log.TaskGroup.ID,
+		TaskGroup:   log.TaskGroup.Name,
+		TaskID:      log.Task.ID,
+		Task:        log.Task.Name,
+		Accepted:    log.Accepted,
+		Answer:      log.Answer,
+		AnswerTime:  log.AnswerTime,
+		Score:       log.Score,
+		File:        log.File,
+		BonusCode:   log.BonusCode,
+	}
+	if log.User != nil {
+		al.UserID = log.User.ID
+		al.User = log.User.Username
+	}
+	return al
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/geo"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// checkPosition tells whether team position is within radius of task location
+func checkPosition(location *storage.Location, position *geo.Point) (bool, error) {
+	if position == nil {
+		return false, httperrors.New(http.StatusBadRequest, "position is required for location task")
+	}
+	if err := validatePoint(position); err != nil {
+		return false, err
+	}
+	return geo.Distance(geo.Point{Lat: location.Lat, Lon: location.Lon}, *position) <= location.Radius, nil
+}
+
+func validatePoint(p *geo.Point) error {
+	if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+		return httperrors.Errorf(http.StatusBadRequest, "position (%g, %g) is out of bounds", p.Lat, p.Lon)
+	}
+	return nil
+}
+
+// positionText returns position to be saved as answer of the team
+func positionText(p *geo.Point) string {
+	return fmt.Sprintf("%.6f, %.6f", p.Lat, p.Lon)
+}
+
+// GetQuestMap returns locations of task groups and tasks of the quest.
+// When teamID is set, tasks are marked as solved or unsolved by the team.
+func (s *Service) GetQuestMap(ctx context.Context, questID, teamID storage.ID) (*geo.FeatureCollection, error) {
+	var accepted storage.AcceptedTasks
+	if len(teamID) > 0 {
+		team, err := s.tms.GetTeam(ctx, &storage.GetTeamRequest{ID: teamID})
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, httperrors.Errorf(http.StatusNotFound, "team %q not found", teamID)
+			}
+			return nil, xerrors.Errorf("get team: %w", err)
+		}
+		if team.Quest.ID != questID {
+			return nil, httperrors.Errorf(http.StatusForbidden, "team %q belongs to quest %q", teamID, team.Quest.ID)
+		}
+		accepted, err = s.ah.GetAcceptedTasks(ctx, &storage.GetAcceptedTasksRequest{TeamID: teamID, QuestID: questID})
+		if err != nil {
+			return nil, xerrors.Errorf("get accepted tasks: %w", err)
+		}
+	}
+	taskGroups, err := s.tgs.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID, IncludeTasks: true})
+	if err != nil {
+		return nil, xerrors.Errorf("get task groups: %w", err)
+	}
+
+	var features []geo.Feature
+	for _, tg := range taskGroups {
+		if tg.Location != nil {
+			features = append(features, locationFeature(tg.Location, map[string]any{
+				"kind": "task_group",
+				"id":   tg.ID,
+				"name": tg.Name,
+			}))
+		}
+		for _, task := range tg.Tasks {
+			if task.Location == nil {
+				continue
+			}
+			properties := map[string]any{
+				"kind":          "task",
+				"id":            task.ID,
+				"name":          task.Name,
+				"task_group_id": tg.ID,
+			}
+			if len(teamID) > 0 {
+				properties["solved"] = accepted.Solved(task.ID)
+			}
+			features = append(features, locationFeature(task.Location, properties))
+		}
+	}
+	return geo.NewFeatureCollection(features...), nil
+}
+
+func locationFeature(l *storage.Location, properties map[string]any) geo.Feature {
+	properties["radius"] = l.Radius
+	return geo.NewPointFeature(geo.Point{Lat: l.Lat, Lon: l.Lon}, properties)
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/geo"
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestCheckPosition(t *testing.T) {
+	location := &storage.Location{Lat: 55.7539, Lon: 37.6208, Radius: 50}
+
+	testCases := []struct {
+		name     string
+		position *geo.Point
+		accepted bool
+		code     int
+	}{
+		{name: "same point", position: &geo.Point{Lat: 55.7539, Lon: 37.6208}, accepted: true},
+		{name: "within radius", position: &geo.Point{Lat: 55.7542, Lon: 37.6208}, accepted: true},
+		{name: "out of radius", position: &geo.Point{Lat: 55.7549, Lon: 37.6208}},
+		{name: "no position", code: http.StatusBadRequest},
+		{name: "bad latitude", position: &geo.Point{Lat: 95, Lon: 37.6208}, code: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			accepted, err := checkPosition(location, tc.position)
+			if tc.code != 0 {
+				requireHTTPCode(t, tc.code, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.accepted, accepted)
+		})
+	}
+}
+
+func TestService_GetQuestMap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, tgs, tms, ah)
+
+	tgs.EXPECT().GetTaskGroups(gomock.Any(), gomock.Any()).Return([]storage.TaskGroup{
+		{
+			ID:       "park",
+			Name:     "Park",
+			Location: &storage.Location{Lat: 1, Lon: 2, Radius: 300},
+			Tasks: []storage.Task{
+				{ID: "fountain", Name: "Fountain", Location: &storage.Location{Lat: 1.001, Lon: 2.001, Radius: 20}},
+				{ID: "riddle", Name: "Riddle"},
+			},
+		},
+	}, nil).Times(2)
+
+	questMap, err := s.GetQuestMap(context.Background(), "quest", "")
+	require.NoError(t, err)
+	require.Len(t, questMap.Features, 2)
+	assert.Equal(t, []float64{2, 1}, questMap.Features[0].Geometry.Coordinates)
+	assert.Equal(t, map[string]any{"kind": "task_group", "id": storage.ID("park"), "name": "Park", "radius": 300.0}, questMap.Features[0].Properties)
+	assert.NotContains(t, questMap.Features[1].Properties, "solved")
+
+	tms.EXPECT().GetTeam(gomock.Any(), &storage.GetTeamRequest{ID: "team"}).Return(&storage.Team{ID: "team", Quest: &storage.Quest{ID: "quest"}}, nil)
+	ah.EXPECT().GetAcceptedTasks(gomock.Any(), gomock.Any()).Return(storage.AcceptedTasks{"fountain": {Score: 10}}, nil)
+	questMap, err = s.GetQuestMap(context.Background(), "quest", "team")
+	require.NoError(t, err)
+	require.Len(t, questMap.Features, 2)
+	assert.Equal(t, map[string]any{
+		"kind":          "task",
+		"id":            storage.ID("fountain"),
+		"name":          "Fountain",
+		"task_group_id": storage.ID("park"),
+		"radius":        20.0,
+		"solved":        true,
+	}, questMap.Features[1].Properties)
+
+	tms.EXPECT().GetTeam(gomock.Any(), gomock.Any()).Return(&storage.Team{ID: "stranger", Quest: &storage.Quest{ID: "other"}}, nil)
+	_, err = s.GetQuestMap(context.Background(), "quest", "stranger")
+	requireHTTPCode(t, http.StatusForbidden, err)
+}
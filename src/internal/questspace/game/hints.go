@@ -0,0 +1,74 @@
+package game
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// taskOpeningTime returns time when task is opened for team: opening of linear group by team or quest start,
+// postponed by publication of the group and the task
+func taskOpeningTime(quest *storage.Quest, taskGroup *storage.TaskGroup, task *storage.Task) *time.Time {
+	opening := quest.StartTime
+	if taskGroup.TeamInfo != nil {
+		opening = &taskGroup.TeamInfo.OpeningTime
+	}
+	for _, pubTime := range []*time.Time{taskGroup.PubTime, task.PubTime} {
+		if pubTime != nil && (opening == nil || pubTime.After(*opening)) {
+			opening = pubTime
+		}
+	}
+	return opening
+}
+
+// hintOpensAt returns time when hint is revealed automatically or nil for hints, which are only taken by team
+func hintOpensAt(hint *storage.Hint, opening *time.Time) *time.Time {
+	if hint.OpensAfter == nil || opening == nil {
+		return nil
+	}
+	opensAt := opening.Add(time.Duration(*hint.OpensAfter))
+	return &opensAt
+}
+
+func hintOpened(hint *storage.Hint, opening *time.Time, now time.Time) bool {
+	opensAt := hintOpensAt(hint, opening)
+	return opensAt != nil && !now.Before(*opensAt)
+}
+
+// withAutoHints adds hints revealed automatically by the time of answer to the hints taken by team.
+// Hint taken before it was revealed keeps its penalty, while revealed one is charged with its AutoPenalty.
+func withAutoHints(takenHints []storage.HintTake, task *storage.Task, opening *time.Time, now time.Time) []storage.HintTake {
+	taken := make(map[int]struct{}, len(takenHints))
+	for _, h := range takenHints {
+		taken[h.Hint.Index] = struct{}{}
+	}
+	res := slices.Clone(takenHints)
+	for _, hint := range task.FullHints {
+		if _, ok := taken[hint.Index]; ok || hint.AutoPenalty == nil || !hintOpened(&hint, opening, now) {
+			continue
+		}
+		autoHint := hint
+		autoHint.Penalty = *hint.AutoPenalty
+		res = append(res, storage.HintTake{TaskID: task.ID, Hint: autoHint})
+	}
+	return res
+}
+
+// checkHintOrder returns error when some hint preceding the one at index is neither taken by team
+// nor revealed automatically, as task requires hints to be taken in order
+func checkHintOrder(task *storage.Task, takenHints []storage.HintTake, index int, opening *time.Time, now time.Time) error {
+	taken := make(map[int]struct{}, len(takenHints))
+	for _, h := range takenHints {
+		taken[h.Hint.Index] = struct{}{}
+	}
+	for _, hint := range task.FullHints[:index] {
+		if _, ok := taken[hint.Index]; ok || hintOpened(&hint, opening, now) {
+			continue
+		}
+		return httperrors.Errorf(http.StatusNotAcceptable, "hint #%d should be taken before hint #%d", hint.Index+1, index+1)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+)
+
+func TestTaskOpeningTime(t *testing.T) {
+	start := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	quest := &storage.Quest{StartTime: &start}
+
+	assert.Equal(t, &start, taskOpeningTime(quest, &storage.TaskGroup{}, &storage.Task{}))
+
+	opened := start.Add(time.Hour)
+	linear := &storage.TaskGroup{TeamInfo: &storage.TaskGroupTeamInfo{OpeningTime: opened}}
+	assert.Equal(t, &opened, taskOpeningTime(quest, linear, &storage.Task{}))
+
+	published := start.Add(2 * time.Hour)
+	assert.Equal(t, &published, taskOpeningTime(quest, linear, &storage.Task{PubTime: &published}))
+	assert.Equal(t, &opened, taskOpeningTime(quest, linear, &storage.Task{PubTime: &start}))
+}
+
+func TestWithAutoHints(t *testing.T) {
+	opening := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	penalty, err := storage.NewPercentagePenalty(30)
+	require.NoError(t, err)
+	autoPenalty, err := storage.NewPercentagePenalty(10)
+	require.NoError(t, err)
+	tenMinutes := storage.Duration(10 * time.Minute)
+	task := &storage.Task{
+		ID:     "task",
+		Reward: 100,
+		FullHints: []storage.Hint{
+			{Index: 0, Penalty: penalty, OpensAfter: &tenMinutes, AutoPenalty: &autoPenalty},
+			{Index: 1, Penalty: penalty, OpensAfter: &tenMinutes},
+			{Index: 2, Penalty: penalty},
+		},
+	}
+
+	// nothing is revealed before delay passes
+	hints := withAutoHints(nil, task, &opening, opening.Add(5*time.Minute))
+	assert.Empty(t, hints)
+
+	// revealed hint costs its auto penalty, while free one is not charged at all
+	hints = withAutoHints(nil, task, &opening, opening.Add(10*time.Minute))
+	require.Len(t, hints, 1)
+	assert.Equal(t, 90, scoreWithHints(task.Reward, hints))
+
+	// hint taken before it was revealed keeps its full penalty
+	taken := []storage.HintTake{{TaskID: "task", Hint: task.FullHints[0]}}
+	hints = withAutoHints(taken, task, &opening, opening.Add(time.Hour))
+	require.Len(t, hints, 1)
+	assert.Equal(t, 70, scoreWithHints(task.Reward, hints))
+}
+
+func TestNewAnswerTaskGroup_TimedHints(t *testing.T) {
+	opening := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	now := opening.Add(15 * time.Minute)
+	tenMinutes, halfHour := storage.Duration(10*time.Minute), storage.Duration(30*time.Minute)
+	tg := &storage.TaskGroup{
+		TeamInfo: &storage.TaskGroupTeamInfo{OpeningTime: opening},
+		Tasks: []storage.Task{{
+			ID: "task",
+			FullHints: []storage.Hint{
+				{Index: 0, Text: "early", Penalty: storage.NewScorePenalty(5), OpensAfter: &tenMinutes},
+				{Index: 1, Text: "late", Penalty: storage.NewScorePenalty(5), OpensAfter: &halfHour},
+			},
+		}},
+	}
+
+	answerGroup := newAnswerTaskGroup(&storage.Quest{StartTime: ptr.Time(opening.Add(-time.Hour))}, tg, "team", nil, nil, nil, now)
+	require.Len(t, answerGroup.Tasks, 1)
+	hints := answerGroup.Tasks[0].Hints
+	assert.True(t, hints[0].Taken)
+	assert.True(t, hints[0].Auto)
+	assert.Equal(t, "early", hints[0].Text)
+	assert.Nil(t, hints[0].OpensAt)
+
+	assert.False(t, hints[1].Taken)
+	assert.Empty(t, hints[1].Text)
+	assert.Equal(t, ptr.Time(opening.Add(30*time.Minute)), hints[1].OpensAt)
+	require.NotNil(t, hints[1].OpensIn)
+	assert.Equal(t, storage.Duration(15*time.Minute), *hints[1].OpensIn)
+}
+
+func TestCheckHintOrder(t *testing.T) {
+	opening := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	penalty, err := storage.NewPercentagePenalty(10)
+	require.NoError(t, err)
+	tenMinutes := storage.Duration(10 * time.Minute)
+	task := &storage.Task{
+		ID:           "task",
+		OrderedHints: true,
+		FullHints: []storage.Hint{
+			{Index: 0, Penalty: penalty, OpensAfter: &tenMinutes},
+			{Index: 1, Penalty: penalty},
+			{Index: 2, Penalty: penalty},
+		},
+	}
+
+	require.NoError(t, checkHintOrder(task, nil, 0, &opening, opening))
+	requireHTTPCode(t, http.StatusNotAcceptable, checkHintOrder(task, nil, 1, &opening, opening))
+
+	// automatically revealed hint counts as taken
+	require.NoError(t, checkHintOrder(task, nil, 1, &opening, opening.Add(time.Hour)))
+
+	taken := []storage.HintTake{{TaskID: "task", Hint: task.FullHints[0]}}
+	require.NoError(t, checkHintOrder(task, taken, 1, &opening, opening))
+	requireHTTPCode(t, http.StatusNotAcceptable, checkHintOrder(task, taken, 2, &opening, opening))
+}
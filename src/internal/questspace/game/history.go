@@ -0,0 +1,77 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/quests"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+type ScoreHistoryPoint struct {
+	Time  time.Time `json:"time"`
+	Score int       `json:"score"`
+}
+
+type TeamScoreHistory struct {
+	TeamID   storage.ID `json:"team_id"`
+	TeamName string     `json:"team_name"`
+	// Points contain cumulative team score after each change ordered by time. With bucketing, time is the end of bucket
+	Points []ScoreHistoryPoint `json:"points"`
+}
+
+type ScoreHistoryResponse struct {
+	Teams []TeamScoreHistory `json:"teams"`
+	// FrozenAt is set while leaderboard is frozen, see LeaderboardResponse
+	FrozenAt *time.Time `json:"frozen_at,omitempty"`
+}
+
+// GetScoreHistory returns cumulative score of every accepted team over time as seen by user, who may be nil for anonymous requests
+func (s *Service) GetScoreHistory(ctx context.Context, quest *storage.Quest, user *storage.User, bucket time.Duration) (*ScoreHistoryResponse, error) {
+	teams, err := s.tms.GetTeams(ctx, &storage.GetTeamsRequest{QuestIDs: []storage.ID{quest.ID}, AcceptedOnly: true})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.Errorf(http.StatusNotFound, "quest %q not found", quest.ID)
+		}
+		return nil, xerrors.Errorf("get teams: %w", err)
+	}
+	history, err := s.ah.GetScoreHistory(ctx, &storage.GetScoreHistoryRequest{
+		QuestID: quest.ID,
+		Bucket:  bucket,
+		Before:  visibleResultsBefore(quest, user),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get score history: %w", err)
+	}
+
+	res := ScoreHistoryResponse{
+		Teams:    make([]TeamScoreHistory, 0, len(teams)),
+		FrozenAt: quests.LeaderboardFreezeTime(quest),
+	}
+	for _, team := range teams {
+		teamHistory := TeamScoreHistory{
+			TeamID:   team.ID,
+			TeamName: team.Name,
+			Points:   make([]ScoreHistoryPoint, 0, len(history[team.ID])),
+		}
+		for _, p := range history[team.ID] {
+			teamHistory.Points = append(teamHistory.Points, ScoreHistoryPoint{Time: p.Time, Score: p.Score})
+		}
+		res.Teams = append(res.Teams, teamHistory)
+	}
+	return &res, nil
+}
+
+// visibleResultsBefore returns time after which results are hidden from user because of leaderboard freeze.
+// Quest creator always sees live results.
+func visibleResultsBefore(quest *storage.Quest, user *storage.User) *time.Time {
+	if user != nil && quest.Creator != nil && quest.Creator.ID == user.ID {
+		return nil
+	}
+	return quests.LeaderboardFreezeTime(quest)
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"net/http"
+	"time"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// mergeAnswerLimits returns quest default limits overridden by task limits field by field
+func mergeAnswerLimits(task, quest *storage.AnswerLimits) *storage.AnswerLimits {
+	if task == nil {
+		return quest
+	}
+	if quest == nil {
+		return task
+	}
+	merged := *quest
+	if task.MaxAttempts != nil {
+		merged.MaxAttempts = task.MaxAttempts
+	}
+	if task.CooldownAttempts != nil || task.Cooldown != nil {
+		merged.CooldownAttempts = task.CooldownAttempts
+		merged.Cooldown = task.Cooldown
+	}
+	if task.WrongAnswerPenalty != nil {
+		merged.WrongAnswerPenalty = task.WrongAnswerPenalty
+	}
+	return &merged
+}
+
+// checkAnswerLimits returns error if team cannot answer task at the moment because of attempts limit or cooldown
+func checkAnswerLimits(limits *storage.AnswerLimits, stats *storage.AnswerTryStats, now time.Time) error {
+	if limits == nil || stats == nil {
+		return nil
+	}
+	if limits.MaxAttempts != nil && stats.WrongTries >= *limits.MaxAttempts {
+		return httperrors.Errorf(http.StatusNotAcceptable, "no attempts left: all %d attempts are used", *limits.MaxAttempts)
+	}
+	if limits.CooldownAttempts == nil || limits.Cooldown == nil || stats.LastTryTime == nil {
+		return nil
+	}
+	if n := *limits.CooldownAttempts; n <= 0 || stats.WrongTries == 0 || stats.WrongTries%n != 0 {
+		return nil
+	}
+	if until := stats.LastTryTime.Add(time.Duration(*limits.Cooldown)); now.Before(until) {
+		return httperrors.Errorf(http.StatusTooManyRequests, "too many wrong answers, next try is available in %s", until.Sub(now).Round(time.Second))
+	}
+	return nil
+}
+
+// attemptsLeft returns number of remaining tries after the current one or nil if attempts are not limited
+func attemptsLeft(limits *storage.AnswerLimits, stats *storage.AnswerTryStats) *int {
+	if limits == nil || limits.MaxAttempts == nil || stats == nil {
+		return nil
+	}
+	left := max(*limits.MaxAttempts-stats.WrongTries-1, 0)
+	return &left
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+func TestMergeAnswerLimits(t *testing.T) {
+	scorePenalty := storage.NewScorePenalty(10)
+	cooldown := storage.Duration(time.Minute)
+	quest := &storage.AnswerLimits{
+		MaxAttempts:        ptr.Int(5),
+		CooldownAttempts:   ptr.Int(3),
+		Cooldown:           &cooldown,
+		WrongAnswerPenalty: &scorePenalty,
+	}
+	task := &storage.AnswerLimits{MaxAttempts: ptr.Int(1)}
+
+	merged := mergeAnswerLimits(task, quest)
+	require.NotNil(t, merged)
+	assert.Equal(t, 1, *merged.MaxAttempts)
+	assert.Equal(t, 3, *merged.CooldownAttempts)
+	assert.Equal(t, &scorePenalty, merged.WrongAnswerPenalty)
+	assert.Equal(t, 5, *quest.MaxAttempts)
+
+	assert.Same(t, quest, mergeAnswerLimits(nil, quest))
+	assert.Same(t, task, mergeAnswerLimits(task, nil))
+	assert.Nil(t, mergeAnswerLimits(nil, nil))
+}
+
+func TestCheckAnswerLimits(t *testing.T) {
+	now := time.Date(2024, 4, 14, 12, 0, 0, 0, time.UTC)
+	cooldown := storage.Duration(time.Minute)
+	limits := &storage.AnswerLimits{
+		MaxAttempts:      ptr.Int(6),
+		CooldownAttempts: ptr.Int(3),
+		Cooldown:         &cooldown,
+	}
+
+	testCases := []struct {
+		name         string
+		stats        storage.AnswerTryStats
+		expectedCode int
+	}{
+		{
+			name:  "no tries",
+			stats: storage.AnswerTryStats{},
+		},
+		{
+			name:  "between cooldowns",
+			stats: storage.AnswerTryStats{WrongTries: 2, LastTryTime: ptr.Time(now.Add(-time.Second))},
+		},
+		{
+			name:         "cooldown",
+			stats:        storage.AnswerTryStats{WrongTries: 3, LastTryTime: ptr.Time(now.Add(-30 * time.Second))},
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			name:  "cooldown passed",
+			stats: storage.AnswerTryStats{WrongTries: 3, LastTryTime: ptr.Time(now.Add(-2 * time.Minute))},
+		},
+		{
+			name:         "no attempts left",
+			stats:        storage.AnswerTryStats{WrongTries: 6, LastTryTime: ptr.Time(now.Add(-time.Hour))},
+			expectedCode: http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkAnswerLimits(limits, &tc.stats, now)
+			if tc.expectedCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var httpErr *httperrors.HTTPError
+			require.True(t, errors.As(err, &httpErr))
+			assert.Equal(t, tc.expectedCode, httpErr.Code)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"maps"
+	"net/http"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+type AnswerPart struct {
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	Reward   int    `json:"reward"`
+	Accepted bool   `json:"accepted"`
+	Answer   string `json:"answer,omitempty"`
+}
+
+// partReward returns share of task reward given for the part
+func partReward(task *storage.Task, part int) int {
+	return task.Reward * task.Parts[part].Share / 100
+}
+
+// unsolvedTaskReward returns reward of task which is not earned by team yet: solved parts of multi-part task are excluded
+func unsolvedTaskReward(task *storage.Task, accepted storage.AcceptedTasks) int {
+	acceptedTask, ok := accepted[task.ID]
+	if !ok {
+		return task.Reward
+	}
+	if !acceptedTask.Partial {
+		return 0
+	}
+	reward := task.Reward
+	for part := range acceptedTask.Parts {
+		if part < len(task.Parts) {
+			reward -= partReward(task, part)
+		}
+	}
+	return reward
+}
+
+// answerPart checks that team answers existing part of multi-part task, which is not solved yet
+func answerPart(task *storage.Task, part *int, acceptedTask storage.AcceptedTask) (int, error) {
+	if part == nil {
+		return 0, httperrors.Errorf(http.StatusBadRequest, "part of task %q is required", task.ID)
+	}
+	if *part < 0 || *part >= len(task.Parts) {
+		return 0, httperrors.Errorf(http.StatusBadRequest, "part %d is out of parts range", *part)
+	}
+	if _, ok := acceptedTask.Parts[*part]; ok {
+		return 0, httperrors.Errorf(http.StatusNotAcceptable, "part %d of task %q is already accepted", *part, task.ID)
+	}
+	return *part, nil
+}
+
+// partHints returns taken hints with penalties applied to the part only. Percentage penalties are already
+// proportional to the part reward, while score penalties are scaled by the part share, so that hints
+// taken after some parts are solved penalize only the remaining unsolved value of the task.
+func partHints(takenHints []storage.HintTake, share int) []storage.HintTake {
+	res := make([]storage.HintTake, 0, len(takenHints))
+	for _, h := range takenHints {
+		if h.Hint.Penalty.IsScore() {
+			h.Hint.Penalty = storage.NewScorePenalty(h.Hint.Penalty.Score() * share / 100)
+		}
+		res = append(res, h)
+	}
+	return res
+}
+
+// acceptPart adds solved part to accepted tasks, so that task becomes accepted completely with its last part
+func acceptPart(accepted storage.AcceptedTasks, task *storage.Task, part int, text string, score int) storage.AcceptedTask {
+	acceptedTask := accepted[task.ID]
+	parts := maps.Clone(acceptedTask.Parts)
+	if parts == nil {
+		parts = make(map[int]string, len(task.Parts))
+	}
+	parts[part] = text
+	acceptedTask = storage.AcceptedTask{
+		Text:    text,
+		Score:   acceptedTask.Score + score,
+		Parts:   parts,
+		Partial: len(parts) < len(task.Parts),
+	}
+	accepted[task.ID] = acceptedTask
+	return acceptedTask
+}
+
+// answerParts returns parts of multi-part task with answers of solved ones
+func answerParts(task *storage.Task, acceptedTask storage.AcceptedTask) []AnswerPart {
+	parts := make([]AnswerPart, 0, len(task.Parts))
+	for i, part := range task.Parts {
+		answer, ok := acceptedTask.Parts[i]
+		parts = append(parts, AnswerPart{
+			Index:    i,
+			Name:     part.Name,
+			Reward:   partReward(task, i),
+			Accepted: ok,
+			Answer:   answer,
+		})
+	}
+	return parts
+}
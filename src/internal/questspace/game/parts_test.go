@@ -0,0 +1,82 @@
+package game
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+)
+
+func newPartsTask() *storage.Task {
+	return &storage.Task{
+		ID:     "cities",
+		Reward: 100,
+		Type:   storage.TaskTypeParts,
+		Parts: []storage.TaskPart{
+			{Name: "north", CorrectAnswers: []string{"murmansk"}, Share: 50},
+			{Name: "south", CorrectAnswers: []string{"sochi"}, Share: 30},
+			{Name: "east", CorrectAnswers: []string{"vladivostok"}, Share: 20},
+		},
+	}
+}
+
+func TestAnswerPart(t *testing.T) {
+	task := newPartsTask()
+	solved := storage.AcceptedTask{Parts: map[int]string{0: "murmansk"}, Partial: true}
+
+	idx, err := answerPart(task, ptr.Int(1), solved)
+	require.NoError(t, err)
+	assert.Equal(t, 1, idx)
+
+	_, err = answerPart(task, nil, solved)
+	requireHTTPCode(t, http.StatusBadRequest, err)
+	_, err = answerPart(task, ptr.Int(3), solved)
+	requireHTTPCode(t, http.StatusBadRequest, err)
+	_, err = answerPart(task, ptr.Int(0), solved)
+	requireHTTPCode(t, http.StatusNotAcceptable, err)
+}
+
+func TestAcceptPart(t *testing.T) {
+	task := newPartsTask()
+	accepted := make(storage.AcceptedTasks)
+
+	acceptedTask := acceptPart(accepted, task, 1, "sochi", 30)
+	assert.True(t, acceptedTask.Partial)
+	assert.False(t, accepted.Solved(task.ID))
+	assert.Equal(t, 70, unsolvedTaskReward(task, accepted))
+
+	acceptPart(accepted, task, 0, "murmansk", 45)
+	acceptedTask = acceptPart(accepted, task, 2, "vladivostok", 20)
+	assert.False(t, acceptedTask.Partial)
+	assert.Equal(t, 95, acceptedTask.Score)
+	assert.True(t, accepted.Solved(task.ID))
+	assert.Zero(t, unsolvedTaskReward(task, accepted))
+
+	assert.Equal(t, []AnswerPart{
+		{Index: 0, Name: "north", Reward: 50, Accepted: true, Answer: "murmansk"},
+		{Index: 1, Name: "south", Reward: 30, Accepted: true, Answer: "sochi"},
+		{Index: 2, Name: "east", Reward: 20, Accepted: true, Answer: "vladivostok"},
+	}, answerParts(task, acceptedTask))
+}
+
+func TestPartHints(t *testing.T) {
+	percent, err := storage.NewPercentagePenalty(20)
+	require.NoError(t, err)
+	takenHints := []storage.HintTake{
+		{TaskID: "cities", Hint: storage.Hint{Index: 0, Penalty: percent}},
+		{TaskID: "cities", Hint: storage.Hint{Index: 1, Penalty: storage.NewScorePenalty(40)}},
+	}
+
+	task := newPartsTask()
+	// hints taken after the first part is solved penalize the remaining 50 points only:
+	// 20% of each part reward and 40 points split by part shares
+	south := scoreWithHints(partReward(task, 1), partHints(takenHints, task.Parts[1].Share))
+	east := scoreWithHints(partReward(task, 2), partHints(takenHints, task.Parts[2].Share))
+	assert.Equal(t, 30-6-12, south)
+	assert.Equal(t, 20-4-8, east)
+	assert.Equal(t, 40, takenHints[1].Hint.Penalty.Score())
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/events"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+type AdjustmentsResponse struct {
+	Adjustments []storage.Adjustment `json:"adjustments"`
+}
+
+// GetAdjustments returns penalty ledger of the quest including revoked entries. Ledger is limited to a single team when teamID is set
+func (s *Service) GetAdjustments(ctx context.Context, questID, teamID storage.ID) (*AdjustmentsResponse, error) {
+	adjustments, err := s.ah.GetAdjustments(ctx, &storage.GetAdjustmentsRequest{QuestID: questID, TeamID: teamID})
+	if err != nil {
+		return nil, xerrors.Errorf("get adjustments: %w", err)
+	}
+	if adjustments == nil {
+		adjustments = []storage.Adjustment{}
+	}
+	return &AdjustmentsResponse{Adjustments: adjustments}, nil
+}
+
+type AddAdjustmentRequest struct {
+	QuestID storage.ID `json:"-"`
+	TeamID  storage.ID `json:"team_id"`
+	// Value is subtracted from team score, negative value is a bonus
+	Value  int    `json:"value"`
+	Reason string `json:"reason,omitempty"`
+	// TaskID optionally links adjustment to a task of the quest
+	TaskID storage.ID `json:"task_id,omitempty"`
+}
+
+// AddAdjustment records penalty or bonus given by user to the ledger of the team
+func (s *Service) AddAdjustment(ctx context.Context, user *storage.User, req *AddAdjustmentRequest) error {
+	if req.Value == 0 {
+		return httperrors.New(http.StatusBadRequest, "value must not be zero")
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := s.checkQuestTeam(ctx, req.QuestID, req.TeamID); err != nil {
+		return err
+	}
+	if req.TaskID != "" {
+		if err := s.checkQuestTask(ctx, req.QuestID, req.TaskID); err != nil {
+			return err
+		}
+	}
+	if err := s.ah.CreatePenalty(ctx, &storage.CreatePenaltyRequest{
+		TeamID:   req.TeamID,
+		Penalty:  req.Value,
+		TaskID:   req.TaskID,
+		Reason:   req.Reason,
+		AuthorID: user.ID,
+	}); err != nil {
+		return xerrors.Errorf("create penalty: %w", err)
+	}
+	s.events.Add(events.New(events.PenaltyAdded, req.QuestID, req.TeamID, events.PenaltyData{Penalty: req.Value, Reason: req.Reason, TaskID: req.TaskID}))
+	return nil
+}
+
+type RevokePenaltyRequest struct {
+	QuestID   storage.ID `json:"-"`
+	PenaltyID int64      `json:"penalty_id"`
+}
+
+// RevokePenalty cancels ledger entry, so that it is not counted in team score anymore. Entry itself stays in the ledger
+func (s *Service) RevokePenalty(ctx context.Context, user *storage.User, req *RevokePenaltyRequest) error {
+	adjustments, err := s.ah.GetAdjustments(ctx, &storage.GetAdjustmentsRequest{QuestID: req.QuestID, ActiveOnly: true})
+	if err != nil {
+		return xerrors.Errorf("get adjustments: %w", err)
+	}
+	var revoked *storage.Adjustment
+	for i := range adjustments {
+		if adjustments[i].ID == req.PenaltyID {
+			revoked = &adjustments[i]
+			break
+		}
+	}
+	if revoked == nil {
+		return httperrors.Errorf(http.StatusNotFound, "penalty %d not found or already revoked", req.PenaltyID)
+	}
+	if err = s.ah.RevokeAdjustment(ctx, &storage.RevokeAdjustmentRequest{ID: req.PenaltyID, QuestID: req.QuestID, UserID: user.ID}); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "penalty %d not found or already revoked", req.PenaltyID)
+		}
+		return xerrors.Errorf("revoke adjustment: %w", err)
+	}
+	s.events.Add(events.New(events.PenaltyRevoked, req.QuestID, revoked.TeamID, events.PenaltyData{
+		ID:          revoked.ID,
+		Penalty:     revoked.Value,
+		Reason:      revoked.Reason,
+		TaskID:      revoked.TaskID,
+		TaskGroupID: revoked.TaskGroupID,
+	}))
+	return nil
+}
+
+// checkQuestTeam returns error when team does not exist or belongs to another quest
+func (s *Service) checkQuestTeam(ctx context.Context, questID, teamID storage.ID) error {
+	team, err := s.tms.GetTeam(ctx, &storage.GetTeamRequest{ID: teamID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "team %q not found", teamID)
+		}
+		return xerrors.Errorf("get team: %w", err)
+	}
+	if team.Quest.ID != questID {
+		return httperrors.Errorf(http.StatusForbidden, "team %q belongs to quest %q", teamID, questID)
+	}
+	return nil
+}
+
+// checkQuestTask returns error when task does not exist or belongs to another quest
+func (s *Service) checkQuestTask(ctx context.Context, questID, taskID storage.ID) error {
+	task, err := s.ts.GetTask(ctx, &storage.GetTaskRequest{ID: taskID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return httperrors.Errorf(http.StatusNotFound, "task %q not found", taskID)
+		}
+		return xerrors.Errorf("get task: %w", err)
+	}
+	taskGroup, err := s.tgs.GetTaskGroup(ctx, &storage.GetTaskGroupRequest{ID: task.Group.ID})
+	if err != nil {
+		return xerrors.Errorf("get task group: %w", err)
+	}
+	if taskGroup.Quest.ID != questID {
+		return httperrors.Errorf(http.StatusBadRequest, "task %q belongs to another quest", taskID)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/internal/questspace/events"
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestService_AddPenalty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, nil, tms, ah)
+	author := &storage.User{ID: "creator"}
+	team := &storage.Team{ID: "team", Quest: &storage.Quest{ID: "quest"}}
+
+	tms.EXPECT().GetTeam(gomock.Any(), &storage.GetTeamRequest{ID: "team"}).Return(team, nil).Times(2)
+	ah.EXPECT().GetAdjustments(gomock.Any(), &storage.GetAdjustmentsRequest{QuestID: "quest", TeamID: "team", ActiveOnly: true}).Return([]storage.Adjustment{
+		{ID: 1, TeamID: "team", Value: 20, Legacy: true},
+		{ID: 2, TeamID: "team", Value: 30, TaskGroupID: "skipped"},
+		// itemized entry added with AddAdjustment survives legacy call
+		{ID: 3, TeamID: "team", Value: 10, Reason: "late", TaskID: "task", Author: author},
+	}, nil)
+	ah.EXPECT().RevokeAdjustment(gomock.Any(), &storage.RevokeAdjustmentRequest{ID: 1, QuestID: "quest", UserID: "creator"}).Return(nil)
+	ah.EXPECT().CreatePenalty(gomock.Any(), &storage.CreatePenaltyRequest{TeamID: "team", Penalty: 25, AuthorID: "creator", Legacy: true}).Return(nil)
+	err := s.AddPenalty(context.Background(), author, &AddPenaltyRequest{QuestID: "quest", TeamID: "team", Penalty: 25})
+	require.NoError(t, err)
+
+	// zero penalty only resets the previous one
+	ah.EXPECT().GetAdjustments(gomock.Any(), gomock.Any()).Return([]storage.Adjustment{{ID: 4, TeamID: "team", Value: 25, Legacy: true}}, nil)
+	ah.EXPECT().RevokeAdjustment(gomock.Any(), &storage.RevokeAdjustmentRequest{ID: 4, QuestID: "quest", UserID: "creator"}).Return(nil)
+	err = s.AddPenalty(context.Background(), author, &AddPenaltyRequest{QuestID: "quest", TeamID: "team"})
+	require.NoError(t, err)
+}
+
+func TestService_AddAdjustment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ts := mocks.NewMockTaskStorage(ctrl)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(ts, tgs, tms, ah)
+	author := &storage.User{ID: "creator"}
+	team := &storage.Team{ID: "team", Quest: &storage.Quest{ID: "quest"}}
+
+	err := s.AddAdjustment(context.Background(), author, &AddAdjustmentRequest{QuestID: "quest", TeamID: "team"})
+	requireHTTPCode(t, http.StatusBadRequest, err)
+
+	tms.EXPECT().GetTeam(gomock.Any(), &storage.GetTeamRequest{ID: "team"}).Return(team, nil).Times(2)
+	ts.EXPECT().GetTask(gomock.Any(), &storage.GetTaskRequest{ID: "alien"}).Return(&storage.Task{ID: "alien", Group: &storage.TaskGroup{ID: "other"}}, nil)
+	tgs.EXPECT().GetTaskGroup(gomock.Any(), &storage.GetTaskGroupRequest{ID: "other"}).Return(&storage.TaskGroup{ID: "other", Quest: &storage.Quest{ID: "other"}}, nil)
+	err = s.AddAdjustment(context.Background(), author, &AddAdjustmentRequest{QuestID: "quest", TeamID: "team", Value: 10, TaskID: "alien"})
+	requireHTTPCode(t, http.StatusBadRequest, err)
+
+	ah.EXPECT().CreatePenalty(gomock.Any(), &storage.CreatePenaltyRequest{
+		TeamID:   "team",
+		Penalty:  -15,
+		Reason:   "best costume",
+		AuthorID: "creator",
+	}).Return(nil)
+	err = s.AddAdjustment(context.Background(), author, &AddAdjustmentRequest{QuestID: "quest", TeamID: "team", Value: -15, Reason: " best costume "})
+	require.NoError(t, err)
+}
+
+func TestService_RevokePenalty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	broker := events.NewBroker()
+	batch := events.NewBatch(broker)
+	s := NewService(nil, nil, nil, ah, WithEvents(batch))
+	user := &storage.User{ID: "creator"}
+
+	ah.EXPECT().GetAdjustments(gomock.Any(), &storage.GetAdjustmentsRequest{QuestID: "quest", ActiveOnly: true}).Return([]storage.Adjustment{
+		{ID: 1, TeamID: "team", Value: 20, Reason: "late"},
+	}, nil).Times(2)
+	err := s.RevokePenalty(context.Background(), user, &RevokePenaltyRequest{QuestID: "quest", PenaltyID: 2})
+	requireHTTPCode(t, http.StatusNotFound, err)
+
+	ah.EXPECT().RevokeAdjustment(gomock.Any(), &storage.RevokeAdjustmentRequest{ID: 1, QuestID: "quest", UserID: "creator"}).Return(nil)
+	require.NoError(t, s.RevokePenalty(context.Background(), user, &RevokePenaltyRequest{QuestID: "quest", PenaltyID: 1}))
+
+	sub := broker.Subscribe("quest", 1)
+	defer sub.Close()
+	batch.Publish()
+	e := <-sub.Events()
+	assert.Equal(t, events.PenaltyRevoked, e.Type)
+	assert.Equal(t, storage.ID("team"), e.TeamID)
+	assert.Equal(t, events.PenaltyData{ID: 1, Penalty: 20, Reason: "late"}, e.Data)
+}
+
+func TestService_GetResults_Adjustments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, tgs, tms, ah)
+
+	adjustments := []storage.Adjustment{
+		{ID: 1, TeamID: "team", Value: 20, Reason: "late"},
+		{ID: 2, TeamID: "team", Value: -5, Reason: "best costume"},
+	}
+	tms.EXPECT().GetTeams(gomock.Any(), gomock.Any()).Return([]storage.Team{{ID: "team", Name: "Winners"}, {ID: "other", Name: "Losers"}}, nil)
+	tgs.EXPECT().GetTaskGroups(gomock.Any(), gomock.Any()).Return(nil, nil)
+	ah.EXPECT().GetScoreResults(gomock.Any(), gomock.Any()).Return(storage.ScoreResults{}, nil)
+	ah.EXPECT().GetPenalties(gomock.Any(), gomock.Any()).Return(storage.TeamPenalties{"team": {
+		{TeamID: "team", Value: 20, Source: storage.PenaltySourceLedger},
+		{TeamID: "team", Value: -5, Source: storage.PenaltySourceLedger},
+		{TeamID: "team", Value: 3, Source: storage.PenaltySourceWrongAnswer, TaskID: "task"},
+	}}, nil)
+	ah.EXPECT().GetBonusCodeUses(gomock.Any(), gomock.Any()).Return(storage.TeamBonusCodes{}, nil)
+	ah.EXPECT().GetAdjustments(gomock.Any(), &storage.GetAdjustmentsRequest{QuestID: "quest", ActiveOnly: true}).Return(adjustments, nil)
+
+	res, err := s.GetResults(context.Background(), "quest")
+	require.NoError(t, err)
+	require.Len(t, res.Results, 2)
+	assert.Equal(t, storage.ID("other"), res.Results[0].TeamID)
+	assert.Empty(t, res.Results[0].Adjustments)
+	assert.Equal(t, adjustments, res.Results[1].Adjustments)
+	assert.Equal(t, 3, res.Results[1].WrongAnswerPenalty)
+	assert.Equal(t, 18, res.Results[1].Penalty)
+	assert.Equal(t, -18, res.Results[1].TotalScore)
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// unlocks tells which tasks and task groups of assault quest have their prerequisites met by team
+type unlocks struct {
+	accepted      storage.AcceptedTasks
+	solvedInGroup map[storage.ID]int
+}
+
+func newUnlocks(taskGroups []storage.TaskGroup, accepted storage.AcceptedTasks) *unlocks {
+	u := &unlocks{
+		accepted:      accepted,
+		solvedInGroup: make(map[storage.ID]int, len(taskGroups)),
+	}
+	for _, tg := range taskGroups {
+		for _, task := range tg.Tasks {
+			if accepted.Solved(task.ID) {
+				u.solvedInGroup[tg.ID]++
+			}
+		}
+	}
+	return u
+}
+
+func (u *unlocks) met(p *storage.Prerequisites) bool {
+	if p.Empty() {
+		return true
+	}
+	for _, taskID := range p.SolvedTasks {
+		if !u.accepted.Solved(taskID) {
+			return false
+		}
+	}
+	for _, solved := range p.SolvedInGroups {
+		if u.solvedInGroup[solved.TaskGroupID] < solved.Count {
+			return false
+		}
+	}
+	return true
+}
+
+// hideLockedTasks removes tasks with unmet prerequisites from answer data of the group
+func (u *unlocks) hideLockedTasks(answerGroup AnswerTaskGroup, taskGroup *storage.TaskGroup) AnswerTaskGroup {
+	locked := make(map[storage.ID]struct{})
+	for _, task := range taskGroup.Tasks {
+		if !u.met(task.Requires) {
+			locked[task.ID] = struct{}{}
+		}
+	}
+	if len(locked) == 0 {
+		return answerGroup
+	}
+	tasks := make([]AnswerTask, 0, len(answerGroup.Tasks))
+	for _, task := range answerGroup.Tasks {
+		if _, ok := locked[task.ID]; !ok {
+			tasks = append(tasks, task)
+		}
+	}
+	answerGroup.Tasks = tasks
+	return answerGroup
+}
+
+// checkUnlocked returns error when task of assault quest or its group is still locked for team
+func (s *Service) checkUnlocked(ctx context.Context, questID storage.ID, taskGroup *storage.TaskGroup, task *storage.Task, accepted storage.AcceptedTasks) error {
+	if taskGroup.Requires.Empty() && task.Requires.Empty() {
+		return nil
+	}
+	taskGroups, err := s.tgs.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID, IncludeTasks: true})
+	if err != nil {
+		return xerrors.Errorf("get task groups: %w", err)
+	}
+	u := newUnlocks(taskGroups, accepted)
+	if !u.met(taskGroup.Requires) || !u.met(task.Requires) {
+		return httperrors.Errorf(http.StatusNotAcceptable, "task %q is locked", task.ID)
+	}
+	return nil
+}
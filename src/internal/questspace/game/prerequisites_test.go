@@ -0,0 +1,72 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestFillAnswerData_HidesLocked(t *testing.T) {
+	replaceNowFunc(t)
+	taskGroups := []storage.TaskGroup{
+		{
+			ID: "start",
+			Tasks: []storage.Task{
+				{ID: "first"},
+				{ID: "second"},
+				{ID: "final", Requires: &storage.Prerequisites{SolvedTasks: []storage.ID{"first", "second"}}},
+			},
+		},
+		{
+			ID:       "locked",
+			Requires: &storage.Prerequisites{SolvedInGroups: []storage.SolvedInGroup{{TaskGroupID: "start", Count: 2}}},
+			Tasks:    []storage.Task{{ID: "bonus"}},
+		},
+	}
+	answerData := func(accepted storage.AcceptedTasks) map[storage.ID][]storage.ID {
+		s := &Service{}
+		resp := s.fillAnswerData(context.Background(), &AnswerDataRequest{
+			Quest:      &storage.Quest{QuestType: storage.TypeAssault},
+			Team:       &storage.Team{},
+			TaskGroups: taskGroups,
+		}, nil, accepted, nil)
+		visible := make(map[storage.ID][]storage.ID)
+		for _, tg := range resp.TaskGroups {
+			visible[tg.ID] = []storage.ID{}
+			for _, task := range tg.Tasks {
+				visible[tg.ID] = append(visible[tg.ID], task.ID)
+			}
+		}
+		return visible
+	}
+
+	assert.Equal(t, map[storage.ID][]storage.ID{"start": {"first", "second"}}, answerData(storage.AcceptedTasks{"first": {}}))
+	assert.Equal(t, map[storage.ID][]storage.ID{
+		"start":  {"first", "second", "final"},
+		"locked": {"bonus"},
+	}, answerData(storage.AcceptedTasks{"first": {}, "second": {}}))
+}
+
+func TestService_CheckUnlocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	s := NewService(nil, tgs, nil, nil)
+
+	taskGroup := &storage.TaskGroup{ID: "group"}
+	require.NoError(t, s.checkUnlocked(context.Background(), "quest", taskGroup, &storage.Task{ID: "free"}, nil))
+
+	tgs.EXPECT().GetTaskGroups(gomock.Any(), &storage.GetTaskGroupsRequest{QuestID: "quest", IncludeTasks: true}).
+		Return([]storage.TaskGroup{{ID: "group", Tasks: []storage.Task{{ID: "easy"}, {ID: "hard"}}}}, nil).
+		Times(2)
+	task := &storage.Task{ID: "final", Requires: &storage.Prerequisites{SolvedInGroups: []storage.SolvedInGroup{{TaskGroupID: "group", Count: 2}}}}
+	err := s.checkUnlocked(context.Background(), "quest", taskGroup, task, storage.AcceptedTasks{"easy": {}})
+	requireHTTPCode(t, http.StatusNotAcceptable, err)
+	require.NoError(t, s.checkUnlocked(context.Background(), "quest", taskGroup, task, storage.AcceptedTasks{"easy": {}, "hard": {}}))
+}
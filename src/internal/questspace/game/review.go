@@ -0,0 +1,239 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+	"go.uber.org/zap"
+
+	"questspace/internal/qtime"
+	"questspace/internal/questspace/events"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/logging"
+	"questspace/pkg/storage"
+)
+
+type ReviewAnswer struct {
+	ID          int64      `json:"id"`
+	TeamID      storage.ID `json:"team_id"`
+	Team        string     `json:"team"`
+	UserID      storage.ID `json:"user_id,omitempty"`
+	User        string     `json:"user,omitempty"`
+	TaskGroupID storage.ID `json:"task_group_id"`
+	TaskGroup   string     `json:"task_group"`
+	TaskID      storage.ID `json:"task_id"`
+	Task        string     `json:"task"`
+	Reward      int        `json:"reward"`
+	Answer      string     `json:"answer"`
+	AnswerTime  time.Time  `json:"answer_time"`
+	// File can be downloaded with GET /quest/{id}/answer-file/{key}
+	File *storage.AnswerFile `json:"file,omitempty"`
+}
+
+type ReviewQueueResponse struct {
+	Answers []ReviewAnswer `json:"answers"`
+}
+
+func (s *Service) GetReviewQueue(ctx context.Context, questID storage.ID) (*ReviewQueueResponse, error) {
+	tries, err := s.ah.GetReviewAnswerTries(ctx, &storage.GetReviewAnswerTriesRequest{QuestID: questID, OnlyPending: true})
+	if err != nil {
+		return nil, xerrors.Errorf("get review answer tries: %w", err)
+	}
+	resp := &ReviewQueueResponse{Answers: make([]ReviewAnswer, 0, len(tries))}
+	for _, try := range tries {
+		ra := ReviewAnswer{
+			ID:          try.ID,
+			TeamID:      try.Team.ID,
+			Team:        try.Team.Name,
+			TaskGroupID: try.TaskGroup.ID,
+			TaskGroup:   try.TaskGroup.Name,
+			TaskID:      try.Task.ID,
+			Task:        try.Task.Name,
+			Reward:      try.Task.Reward,
+			Answer:      try.Answer,
+			AnswerTime:  try.AnswerTime,
+			File:        try.File,
+		}
+		if try.User != nil {
+			ra.UserID = try.User.ID
+			ra.User = try.User.Username
+		}
+		resp.Answers = append(resp.Answers, ra)
+	}
+	return resp, nil
+}
+
+type ReviewAnswerRequest struct {
+	QuestID  storage.ID `json:"-"`
+	AnswerID int64      `json:"answer_id"`
+	Accepted bool       `json:"accepted"`
+	// Score overrides task reward computed with taken hints penalties and task scoring
+	Score *int `json:"score,omitempty"`
+}
+
+type ReviewAnswerResponse struct {
+	AnswerID     int64                `json:"answer_id"`
+	TeamID       storage.ID           `json:"team_id"`
+	TaskID       storage.ID           `json:"task_id"`
+	ReviewStatus storage.ReviewStatus `json:"review_status" enums:"ACCEPTED,REJECTED"`
+	Score        int                  `json:"score"`
+	// Bonus and Decay show how task scoring modifiers changed Score
+	Bonus int `json:"bonus,omitempty"`
+	Decay int `json:"decay,omitempty"`
+}
+
+func (s *Service) ReviewAnswer(ctx context.Context, req *ReviewAnswerRequest) (*ReviewAnswerResponse, error) {
+	try, err := s.ah.GetAnswerTry(ctx, &storage.GetAnswerTryRequest{ID: req.AnswerID, QuestID: req.QuestID})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.Errorf(http.StatusNotFound, "answer %d not found", req.AnswerID)
+		}
+		return nil, xerrors.Errorf("get answer try: %w", err)
+	}
+	if try.ReviewStatus != storage.ReviewStatusPending {
+		return nil, httperrors.Errorf(http.StatusNotAcceptable, "answer %d is already reviewed", req.AnswerID)
+	}
+	// reviews are serialized with tries of the team, so that concurrent reviews cannot accept the task twice
+	if err = s.ah.LockAnswerTries(ctx, &storage.LockAnswerTriesRequest{TaskID: try.Task.ID, TeamID: try.Team.ID}); err != nil {
+		return nil, xerrors.Errorf("lock answer tries: %w", err)
+	}
+	resp := &ReviewAnswerResponse{
+		AnswerID:     try.ID,
+		TeamID:       try.Team.ID,
+		TaskID:       try.Task.ID,
+		ReviewStatus: storage.ReviewStatusRejected,
+	}
+
+	if !req.Accepted {
+		if err = s.ah.ReviewAnswerTry(ctx, &storage.ReviewAnswerTryRequest{
+			ID:           try.ID,
+			ReviewStatus: storage.ReviewStatusRejected,
+			ReviewTime:   qtime.Now(),
+		}); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, httperrors.Errorf(http.StatusNotAcceptable, "answer %d is already reviewed", req.AnswerID)
+			}
+			return nil, xerrors.Errorf("reject answer try: %w", err)
+		}
+		return resp, nil
+	}
+
+	acceptedTasks, err := s.ah.GetAcceptedTasks(ctx, &storage.GetAcceptedTasksRequest{TeamID: try.Team.ID, QuestID: req.QuestID})
+	if err != nil {
+		return nil, xerrors.Errorf("get accepted tasks: %w", err)
+	}
+	if _, ok := acceptedTasks[try.Task.ID]; ok {
+		return nil, httperrors.Errorf(http.StatusNotAcceptable, "task %q is already accepted for team %q", try.Task.ID, try.Team.ID)
+	}
+
+	team, err := s.tms.GetTeam(ctx, &storage.GetTeamRequest{ID: try.Team.ID})
+	if err != nil {
+		return nil, xerrors.Errorf("get team: %w", err)
+	}
+	taskGroup, err := s.tgs.GetTaskGroup(ctx, &storage.GetTaskGroupRequest{
+		ID:           try.TaskGroup.ID,
+		IncludeTasks: true,
+		TeamData:     &storage.TeamData{TeamID: &team.ID},
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get task group: %w", err)
+	}
+
+	// manually accepted answer is scored at its answer time the same way as automatically accepted one,
+	// while review time places it in score history
+	now := qtime.Now()
+	var task *storage.Task
+	for i := range taskGroup.Tasks {
+		if taskGroup.Tasks[i].ID == try.Task.ID {
+			task = &taskGroup.Tasks[i]
+		}
+	}
+	if task == nil {
+		return nil, xerrors.Errorf("task %q not found in task group %q", try.Task.ID, taskGroup.ID)
+	}
+	var score taskScore
+	if req.Score != nil {
+		if maxScore := maxTaskScore(task); *req.Score < 0 || *req.Score > maxScore {
+			return nil, httperrors.Errorf(http.StatusBadRequest, "score should be in bounds [0; %d], but got %d", maxScore, *req.Score)
+		}
+		score.Score = *req.Score
+	} else {
+		takenHints, err := s.ah.GetHintTakes(ctx, &storage.GetHintTakesRequest{TeamID: try.Team.ID, TaskID: try.Task.ID, QuestID: req.QuestID})
+		if err != nil {
+			return nil, xerrors.Errorf("get hints: %w", err)
+		}
+		taskHints := withAutoHints(takenHints[try.Task.ID], task, taskOpeningTime(team.Quest, taskGroup, task), try.AnswerTime)
+		if score, err = s.getTaskScore(ctx, team.Quest, taskGroup, task, taskHints, try.AnswerTime); err != nil {
+			return nil, xerrors.Errorf("get task score: %w", err)
+		}
+	}
+	if err = s.ah.ReviewAnswerTry(ctx, &storage.ReviewAnswerTryRequest{
+		ID:           try.ID,
+		ReviewStatus: storage.ReviewStatusAccepted,
+		Score:        score.Score,
+		Bonus:        score.Bonus,
+		Decay:        score.Decay,
+		ReviewTime:   now,
+	}); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.Errorf(http.StatusNotAcceptable, "answer %d is already reviewed", req.AnswerID)
+		}
+		return nil, xerrors.Errorf("accept answer try: %w", err)
+	}
+	// duplicate answers of the team do not need review anymore, since the task is already solved
+	if err = s.ah.RejectPendingAnswerTries(ctx, &storage.RejectPendingAnswerTriesRequest{
+		TeamID:     try.Team.ID,
+		TaskID:     try.Task.ID,
+		ReviewTime: now,
+	}); err != nil {
+		return nil, xerrors.Errorf("reject pending answer tries: %w", err)
+	}
+	logging.Info(ctx, "answer review",
+		zap.Int64("answer_id", try.ID),
+		zap.Stringer("team_id", try.Team.ID),
+		zap.Stringer("task_id", try.Task.ID),
+		zap.Int("reward", score.Score),
+		zap.Int("bonus", score.Bonus),
+		zap.Int("decay", score.Decay),
+	)
+	resp.ReviewStatus = storage.ReviewStatusAccepted
+	resp.Score = score.Score
+	resp.Bonus = score.Bonus
+	resp.Decay = score.Decay
+	s.events.Add(events.New(events.TaskAccepted, req.QuestID, try.Team.ID, events.TaskAcceptedData{
+		TaskGroupID: try.TaskGroup.ID,
+		TaskID:      try.Task.ID,
+		Score:       score.Score,
+	}))
+	acceptedTasks[try.Task.ID] = storage.AcceptedTask{
+		Score: score.Score,
+		Text:  try.Answer,
+	}
+
+	if team.Quest.QuestType != storage.TypeLinear {
+		return resp, nil
+	}
+	if taskGroup.Sticky || taskGroup.TeamInfo == nil || taskGroup.TeamInfo.ClosingTime != nil {
+		return resp, nil
+	}
+	transition, err := answerTransition(taskGroup, try.Task.ID, try.Answer)
+	if err != nil {
+		return nil, xerrors.Errorf("get answer transition: %w", err)
+	}
+	if reason := closedGroupReason(transition, acceptedTasks, taskGroup.Tasks); len(reason) > 0 {
+		// answer could be reviewed after time limit is exceeded, but group cannot be closed later than its deadline
+		closingTime := now
+		if taskGroup.HasTimeLimit && taskGroup.TimeLimit != nil {
+			if deadline := taskGroup.TeamInfo.OpeningTime.Add(time.Duration(*taskGroup.TimeLimit)); deadline.Before(now) {
+				closingTime = deadline
+			}
+		}
+		if err = s.closeGroup(ctx, team, taskGroup, closingTime, reason, transition); err != nil {
+			return nil, xerrors.Errorf("close task group: %w", err)
+		}
+	}
+	return resp, nil
+}
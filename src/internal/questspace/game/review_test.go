@@ -0,0 +1,181 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/internal/qtime"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestService_ReviewAnswer_Scoring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, tgs, tms, ah)
+
+	questStart := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	reviewTime := questStart.Add(90 * time.Minute)
+	qtime.SetNowFunc(t, func() time.Time { return reviewTime })
+
+	quest := &storage.Quest{ID: "quest", StartTime: &questStart, QuestType: storage.TypeAssault}
+	task := storage.Task{
+		ID:     "task",
+		Reward: 100,
+		Scoring: &storage.Scoring{
+			Decay: &storage.RewardDecay{Type: storage.DecayStep, Interval: storage.Duration(time.Hour), Amount: 20},
+			// bonuses stored before they were forbidden for manual verification are not given
+			FirstSolveBonuses: []int{30, 10},
+		},
+		Verification: storage.VerificationManual,
+	}
+	tries := []*storage.AnswerTry{
+		{ID: 1, Team: &storage.Team{ID: "first"}, AnswerTime: questStart.Add(30 * time.Minute)},
+		{ID: 2, Team: &storage.Team{ID: "second"}, AnswerTime: questStart.Add(70 * time.Minute)},
+	}
+
+	// later answer is reviewed first, but neither of the teams gets the bonus for the first solve
+	for _, try := range []*storage.AnswerTry{tries[1], tries[0]} {
+		try.TaskGroup = &storage.TaskGroup{ID: "group"}
+		try.Task = &storage.Task{ID: "task", Reward: 100}
+		try.Answer = "answer"
+		try.ReviewStatus = storage.ReviewStatusPending
+
+		ah.EXPECT().GetAnswerTry(gomock.Any(), &storage.GetAnswerTryRequest{ID: try.ID, QuestID: "quest"}).Return(try, nil)
+		ah.EXPECT().LockAnswerTries(gomock.Any(), &storage.LockAnswerTriesRequest{TaskID: "task", TeamID: try.Team.ID}).Return(nil)
+		ah.EXPECT().GetAcceptedTasks(gomock.Any(), gomock.Any()).Return(storage.AcceptedTasks{}, nil)
+		tms.EXPECT().GetTeam(gomock.Any(), &storage.GetTeamRequest{ID: try.Team.ID}).Return(&storage.Team{ID: try.Team.ID, Quest: quest}, nil)
+		tgs.EXPECT().GetTaskGroup(gomock.Any(), gomock.Any()).Return(&storage.TaskGroup{ID: "group", Tasks: []storage.Task{task}}, nil)
+		ah.EXPECT().GetHintTakes(gomock.Any(), gomock.Any()).Return(storage.HintTakes{}, nil)
+		ah.EXPECT().ReviewAnswerTry(gomock.Any(), gomock.Any()).Return(nil)
+		ah.EXPECT().RejectPendingAnswerTries(gomock.Any(), &storage.RejectPendingAnswerTriesRequest{
+			TeamID:     try.Team.ID,
+			TaskID:     "task",
+			ReviewTime: reviewTime,
+		}).Return(nil)
+	}
+
+	resp, err := s.ReviewAnswer(context.Background(), &ReviewAnswerRequest{QuestID: "quest", AnswerID: 2, Accepted: true})
+	require.NoError(t, err)
+	assert.Equal(t, storage.ReviewStatusAccepted, resp.ReviewStatus)
+	assert.Equal(t, 100-20, resp.Score)
+	assert.Zero(t, resp.Bonus)
+	assert.Equal(t, 20, resp.Decay)
+
+	resp, err = s.ReviewAnswer(context.Background(), &ReviewAnswerRequest{QuestID: "quest", AnswerID: 1, Accepted: true})
+	require.NoError(t, err)
+	// answer is scored before reward decays, even though it is reviewed later
+	assert.Equal(t, 100, resp.Score)
+	assert.Zero(t, resp.Bonus)
+	assert.Zero(t, resp.Decay)
+}
+
+func TestService_ReviewAnswer_AlreadyReviewed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, nil, nil, ah)
+
+	try := &storage.AnswerTry{
+		ID:           1,
+		Team:         &storage.Team{ID: "team"},
+		TaskGroup:    &storage.TaskGroup{ID: "group"},
+		Task:         &storage.Task{ID: "task"},
+		ReviewStatus: storage.ReviewStatusPending,
+	}
+	// answer is reviewed by another organizer, while the lock is awaited
+	ah.EXPECT().GetAnswerTry(gomock.Any(), gomock.Any()).Return(try, nil)
+	ah.EXPECT().LockAnswerTries(gomock.Any(), &storage.LockAnswerTriesRequest{TaskID: "task", TeamID: "team"}).Return(nil)
+	ah.EXPECT().ReviewAnswerTry(gomock.Any(), gomock.Any()).Return(storage.ErrNotFound)
+
+	_, err := s.ReviewAnswer(context.Background(), &ReviewAnswerRequest{QuestID: "quest", AnswerID: 1})
+	var httpErr *httperrors.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotAcceptable, httpErr.Code)
+}
+
+func TestService_ReviewAnswer_ScoreBounds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, tgs, tms, ah)
+
+	quest := &storage.Quest{ID: "quest", QuestType: storage.TypeAssault}
+	task := storage.Task{ID: "task", Reward: 100, Verification: storage.VerificationManual}
+	try := &storage.AnswerTry{
+		ID:           1,
+		Team:         &storage.Team{ID: "team"},
+		TaskGroup:    &storage.TaskGroup{ID: "group"},
+		Task:         &storage.Task{ID: "task", Reward: 100},
+		ReviewStatus: storage.ReviewStatusPending,
+	}
+	ah.EXPECT().GetAnswerTry(gomock.Any(), gomock.Any()).Return(try, nil).Times(2)
+	ah.EXPECT().LockAnswerTries(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	ah.EXPECT().GetAcceptedTasks(gomock.Any(), gomock.Any()).Return(storage.AcceptedTasks{}, nil).Times(2)
+	tms.EXPECT().GetTeam(gomock.Any(), gomock.Any()).Return(&storage.Team{ID: "team", Quest: quest}, nil).Times(2)
+	tgs.EXPECT().GetTaskGroup(gomock.Any(), gomock.Any()).Return(&storage.TaskGroup{ID: "group", Tasks: []storage.Task{task}}, nil).Times(2)
+
+	for _, score := range []int{-1, 101} {
+		_, err := s.ReviewAnswer(context.Background(), &ReviewAnswerRequest{QuestID: "quest", AnswerID: 1, Accepted: true, Score: &score})
+		requireHTTPCode(t, http.StatusBadRequest, err)
+	}
+}
+
+func TestService_ReviewAnswer_ClosesGroupByDeadline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, tgs, tms, ah)
+
+	questStart := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	deadline := questStart.Add(time.Hour)
+	reviewTime := questStart.Add(90 * time.Minute)
+	qtime.SetNowFunc(t, func() time.Time { return reviewTime })
+
+	quest := &storage.Quest{ID: "quest", StartTime: &questStart, QuestType: storage.TypeLinear}
+	timeLimit := storage.Duration(time.Hour)
+	taskGroup := &storage.TaskGroup{
+		ID:           "group",
+		HasTimeLimit: true,
+		TimeLimit:    &timeLimit,
+		Tasks:        []storage.Task{{ID: "task", Reward: 100, Verification: storage.VerificationManual}},
+		TeamInfo:     &storage.TaskGroupTeamInfo{OpeningTime: questStart},
+	}
+	try := &storage.AnswerTry{
+		ID:           1,
+		Team:         &storage.Team{ID: "team"},
+		TaskGroup:    &storage.TaskGroup{ID: "group"},
+		Task:         &storage.Task{ID: "task", Reward: 100},
+		Answer:       "answer",
+		AnswerTime:   questStart.Add(30 * time.Minute),
+		ReviewStatus: storage.ReviewStatusPending,
+	}
+	ah.EXPECT().GetAnswerTry(gomock.Any(), gomock.Any()).Return(try, nil)
+	ah.EXPECT().LockAnswerTries(gomock.Any(), gomock.Any()).Return(nil)
+	ah.EXPECT().GetAcceptedTasks(gomock.Any(), gomock.Any()).Return(storage.AcceptedTasks{}, nil)
+	tms.EXPECT().GetTeam(gomock.Any(), gomock.Any()).Return(&storage.Team{ID: "team", Quest: quest}, nil)
+	tgs.EXPECT().GetTaskGroup(gomock.Any(), gomock.Any()).Return(taskGroup, nil)
+	ah.EXPECT().GetHintTakes(gomock.Any(), gomock.Any()).Return(storage.HintTakes{}, nil)
+	ah.EXPECT().ReviewAnswerTry(gomock.Any(), gomock.Any()).Return(nil)
+	ah.EXPECT().RejectPendingAnswerTries(gomock.Any(), gomock.Any()).Return(nil)
+	// answer is reviewed after the deadline, so the group is closed by the deadline rather than by the review time
+	tgs.EXPECT().UpsertTeamInfo(gomock.Any(), &storage.UpsertTeamInfoRequest{
+		TeamID:      "team",
+		TaskGroupID: "group",
+		OpeningTime: questStart,
+		ClosingTime: &deadline,
+	}).Return(nil, nil)
+
+	_, err := s.ReviewAnswer(context.Background(), &ReviewAnswerRequest{QuestID: "quest", AnswerID: 1, Accepted: true})
+	require.NoError(t, err)
+}
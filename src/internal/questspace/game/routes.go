@@ -0,0 +1,124 @@
+package game
+
+import (
+	"context"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/qtime"
+	"questspace/internal/questspace/events"
+	"questspace/pkg/matchers"
+	"questspace/pkg/storage"
+)
+
+// answerTransition returns transition taken by team right after the answer to the task was accepted.
+// Answers of transition are matched with the matcher of the task.
+func answerTransition(taskGroup *storage.TaskGroup, taskID storage.ID, answer string) (*storage.Transition, error) {
+	var params *storage.AnswerMatcher
+	for _, task := range taskGroup.Tasks {
+		if task.ID == taskID {
+			params = task.Matcher
+			break
+		}
+	}
+	for i, t := range taskGroup.Transitions {
+		if t.TaskID != taskID {
+			continue
+		}
+		if len(t.Answers) == 0 {
+			return &taskGroup.Transitions[i], nil
+		}
+		matcher, err := matchers.New(params, t.Answers)
+		if err != nil {
+			return nil, xerrors.Errorf("transition to %q: %w", t.NextGroupID, err)
+		}
+		if matcher.Match(answer) {
+			return &taskGroup.Transitions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// defaultTransition returns transition taken when group is closed without matching any other transition.
+// Nil result means that team proceeds to the next group by order.
+func defaultTransition(taskGroup *storage.TaskGroup) *storage.Transition {
+	for i, t := range taskGroup.Transitions {
+		if len(t.TaskID) == 0 {
+			return &taskGroup.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// closeGroup saves closing time of task group for team together with the route team takes after it.
+// When transition is nil, default transition of the group is taken.
+func (s *Service) closeGroup(
+	ctx context.Context,
+	team *storage.Team,
+	taskGroup *storage.TaskGroup,
+	closingTime time.Time,
+	reason string,
+	transition *storage.Transition,
+) error {
+	req := closeGroupRequest(team, taskGroup, closingTime, transition)
+	if _, err := s.tgs.UpsertTeamInfo(ctx, req); err != nil {
+		return xerrors.Errorf("upsert team info: %w", err)
+	}
+	if err := s.recordGroupClosed(ctx, team, taskGroup, reason, req.NextGroupID); err != nil {
+		return xerrors.Errorf("record group closed: %w", err)
+	}
+	return nil
+}
+
+func closeGroupRequest(team *storage.Team, taskGroup *storage.TaskGroup, closingTime time.Time, transition *storage.Transition) *storage.UpsertTeamInfoRequest {
+	req := &storage.UpsertTeamInfoRequest{
+		TeamID:      team.ID,
+		TaskGroupID: taskGroup.ID,
+		OpeningTime: closingTime,
+		ClosingTime: &closingTime,
+	}
+	switch {
+	case taskGroup.TeamInfo != nil:
+		req.OpeningTime = taskGroup.TeamInfo.OpeningTime
+	case team.Quest.StartTime != nil:
+		req.OpeningTime = *team.Quest.StartTime
+	}
+	if transition == nil {
+		transition = defaultTransition(taskGroup)
+	}
+	if transition != nil && team.Quest.QuestType == storage.TypeLinear {
+		req.NextGroupID = &transition.NextGroupID
+	}
+	return req
+}
+
+// closedGroupReason returns reason of closing group after accepted answer or empty string if group stays open
+func closedGroupReason(transition *storage.Transition, accepted storage.AcceptedTasks, tasks []storage.Task) string {
+	switch {
+	case transition != nil:
+		return events.GroupTransition
+	case allSolved(accepted, tasks):
+		return events.GroupSolved
+	default:
+		return ""
+	}
+}
+
+// CloseExpiredGroups closes groups of team route, which time limit is exceeded, the same way as it happens
+// when team requests its answer data. Task groups should be requested with team data of the team.
+func (s *Service) CloseExpiredGroups(ctx context.Context, quest *storage.Quest, team *storage.Team, taskGroups []storage.TaskGroup) error {
+	if quest.QuestType != storage.TypeLinear {
+		return nil
+	}
+	now := qtime.Now()
+	answerGroups := make([]AnswerTaskGroup, 0, len(taskGroups))
+	for _, tg := range taskGroups {
+		answerGroups = append(answerGroups, newAnswerTaskGroup(quest, &tg, team.ID, nil, nil, nil, now))
+	}
+	req := &AnswerDataRequest{Quest: quest, Team: team, TaskGroups: taskGroups}
+	if _, err := s.fillRoute(ctx, req, answerGroups, now); err != nil {
+		return xerrors.Errorf("fill route: %w", err)
+	}
+	return nil
+}
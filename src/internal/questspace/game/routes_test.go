@@ -0,0 +1,118 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestAnswerTransition(t *testing.T) {
+	taskGroup := &storage.TaskGroup{
+		Tasks: []storage.Task{
+			{ID: "door", Matcher: &storage.AnswerMatcher{Type: storage.MatcherIgnorePunctuation}},
+			{ID: "window"},
+		},
+		Transitions: []storage.Transition{
+			{NextGroupID: "left", TaskID: "door", Answers: []string{"left"}},
+			{NextGroupID: "right", TaskID: "door", Answers: []string{"right"}},
+			{NextGroupID: "yard", TaskID: "window"},
+			{NextGroupID: "hall"},
+		},
+	}
+
+	transition, err := answerTransition(taskGroup, "door", "Right!")
+	require.NoError(t, err)
+	assert.Equal(t, &taskGroup.Transitions[1], transition)
+
+	transition, err = answerTransition(taskGroup, "door", "up")
+	require.NoError(t, err)
+	assert.Nil(t, transition)
+
+	transition, err = answerTransition(taskGroup, "window", "anything")
+	require.NoError(t, err)
+	assert.Equal(t, &taskGroup.Transitions[2], transition)
+
+	assert.Equal(t, &taskGroup.Transitions[3], defaultTransition(taskGroup))
+	assert.Nil(t, defaultTransition(&storage.TaskGroup{}))
+}
+
+func TestFillAnswerData_Route(t *testing.T) {
+	replaceNowFunc(t)
+	right, join := storage.ID("right"), storage.ID("join")
+	timeLimit := storage.Duration(30 * time.Minute)
+	// storage opens the group chosen by transition at closing time of the previous one
+	newTaskGroups := func(closed time.Time) []storage.TaskGroup {
+		return []storage.TaskGroup{
+			{
+				ID:       "fork",
+				TeamInfo: &storage.TaskGroupTeamInfo{OpeningTime: visibilityNow.Add(-time.Hour), ClosingTime: &closed, NextGroupID: &right},
+				Transitions: []storage.Transition{
+					{NextGroupID: "left", TaskID: "task", Answers: []string{"left"}},
+					{NextGroupID: "right", TaskID: "task", Answers: []string{"right"}},
+				},
+			},
+			{ID: "left", Transitions: []storage.Transition{{NextGroupID: "join"}}},
+			{ID: "sticky", Sticky: true},
+			{
+				ID:           "right",
+				HasTimeLimit: true,
+				TimeLimit:    &timeLimit,
+				TeamInfo:     &storage.TaskGroupTeamInfo{OpeningTime: closed},
+				Transitions:  []storage.Transition{{NextGroupID: "join"}},
+			},
+			{ID: "join"},
+			{ID: "sticky after", Sticky: true},
+		}
+	}
+	groupIDs := func(resp *AnswerDataResponse) []storage.ID {
+		var ids []storage.ID
+		for _, tg := range resp.TaskGroups {
+			ids = append(ids, tg.ID)
+		}
+		return ids
+	}
+
+	t.Run("current group of the branch", func(t *testing.T) {
+		s := &Service{}
+		closed := visibilityNow.Add(-time.Minute)
+		resp := s.fillAnswerData(context.Background(), &AnswerDataRequest{
+			Quest:      &storage.Quest{QuestType: storage.TypeLinear},
+			Team:       &storage.Team{},
+			TaskGroups: newTaskGroups(closed),
+		}, nil, nil, nil)
+
+		assert.Equal(t, []storage.ID{"fork", "sticky", "right"}, groupIDs(resp))
+		require.NotNil(t, resp.TaskGroups[2].TeamInfo)
+		assert.Equal(t, closed, resp.TaskGroups[2].TeamInfo.OpeningTime)
+	})
+
+	t.Run("time limit follows default transition", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		tgs := mocks.NewMockTaskGroupStorage(ctrl)
+		s := NewService(nil, tgs, nil, nil)
+
+		closed := visibilityNow.Add(-time.Hour)
+		deadline := closed.Add(30 * time.Minute)
+		tgs.EXPECT().UpsertTeamInfo(gomock.Any(), &storage.UpsertTeamInfoRequest{
+			TaskGroupID: "right",
+			OpeningTime: closed,
+			ClosingTime: &deadline,
+			NextGroupID: &join,
+		}).Return(&storage.TaskGroupTeamInfo{}, nil)
+		resp := s.fillAnswerData(context.Background(), &AnswerDataRequest{
+			Quest:      &storage.Quest{QuestType: storage.TypeLinear},
+			Team:       &storage.Team{},
+			TaskGroups: newTaskGroups(closed),
+		}, nil, nil, nil)
+
+		assert.Equal(t, []storage.ID{"fork", "sticky", "right", "join"}, groupIDs(resp))
+		assert.Equal(t, deadline, resp.TaskGroups[3].TeamInfo.OpeningTime)
+	})
+}
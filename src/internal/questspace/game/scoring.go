@@ -0,0 +1,116 @@
+package game
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/storage"
+)
+
+// taskScore describes score of accepted answer. Bonus and Decay are already included into Score
+type taskScore struct {
+	Score int
+	Bonus int
+	Decay int
+}
+
+func (s *Service) getTaskScore(
+	ctx context.Context,
+	quest *storage.Quest,
+	taskGroup *storage.TaskGroup,
+	task *storage.Task,
+	takenHints []storage.HintTake,
+	answerTime time.Time,
+) (taskScore, error) {
+	res := taskScore{Score: scoreWithHints(task.Reward, takenHints)}
+	if task.Scoring == nil {
+		return res, nil
+	}
+	minScore := 0
+	if decay := task.Scoring.Decay; decay != nil {
+		minScore = min(decay.MinReward, task.Reward)
+		if start := decayStart(decay.From, quest, taskGroup); start != nil {
+			res.Decay = decayPoints(decay, task.Reward, *start, answerTime)
+		}
+	}
+	// reviews come in arbitrary order, so ranks of manually verified answers cannot be exclusive
+	if len(task.Scoring.FirstSolveBonuses) > 0 && task.Verification != storage.VerificationManual {
+		// teams solving the task at the same time must get different ranks
+		if err := s.ah.LockAnswerTries(ctx, &storage.LockAnswerTriesRequest{TaskID: task.ID}); err != nil {
+			return taskScore{}, xerrors.Errorf("lock answer tries: %w", err)
+		}
+		solvedBefore, err := s.ah.GetTaskSolveCount(ctx, &storage.GetTaskSolveCountRequest{TaskID: task.ID})
+		if err != nil {
+			return taskScore{}, xerrors.Errorf("get task solve count: %w", err)
+		}
+		res.Bonus = firstSolveBonus(task.Scoring.FirstSolveBonuses, solvedBefore)
+	}
+	// hint penalties must not take score below the least reward of decay
+	res.Score = max(res.Score+res.Bonus-res.Decay, minScore)
+	return res, nil
+}
+
+// maxTaskScore returns the most score team can get for the task
+func maxTaskScore(task *storage.Task) int {
+	score := task.Reward
+	if task.Scoring != nil && len(task.Scoring.FirstSolveBonuses) > 0 {
+		score += slices.Max(task.Scoring.FirstSolveBonuses)
+	}
+	return score
+}
+
+// withReward returns copy of task scored with the given part of its reward. Least reward of decay is scaled
+// in the same proportion, so that it never exceeds the reduced reward
+func withReward(task *storage.Task, reward int) *storage.Task {
+	res := *task
+	res.Reward = reward
+	if task.Scoring != nil {
+		scoring := *task.Scoring
+		if scoring.Decay != nil && task.Reward > 0 {
+			decay := *scoring.Decay
+			decay.MinReward = decay.MinReward * reward / task.Reward
+			scoring.Decay = &decay
+		}
+		res.Scoring = &scoring
+	}
+	return &res
+}
+
+// decayStart returns time from which reward decays. Group opening time falls back to group publication and quest start
+func decayStart(from storage.DecayStart, quest *storage.Quest, taskGroup *storage.TaskGroup) *time.Time {
+	if from == storage.DecayFromGroupOpening {
+		if taskGroup.TeamInfo != nil {
+			return &taskGroup.TeamInfo.OpeningTime
+		}
+		if taskGroup.PubTime != nil {
+			return taskGroup.PubTime
+		}
+	}
+	return quest.StartTime
+}
+
+// decayPoints returns number of points taken from reward, so that it never falls below decay MinReward
+func decayPoints(decay *storage.RewardDecay, reward int, start, answerTime time.Time) int {
+	if decay.Interval <= 0 || decay.Amount <= 0 || !answerTime.After(start) {
+		return 0
+	}
+	elapsed, interval := answerTime.Sub(start), time.Duration(decay.Interval)
+	var points int
+	switch decay.Type {
+	case storage.DecayStep:
+		points = decay.Amount * int(elapsed/interval)
+	default:
+		points = int(float64(decay.Amount) * float64(elapsed) / float64(interval))
+	}
+	return min(points, max(reward-decay.MinReward, 0))
+}
+
+func firstSolveBonus(bonuses []int, solvedBefore int) int {
+	if solvedBefore < len(bonuses) {
+		return bonuses[solvedBefore]
+	}
+	return 0
+}
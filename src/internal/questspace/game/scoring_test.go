@@ -0,0 +1,154 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestDecayPoints(t *testing.T) {
+	start := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		decay    storage.RewardDecay
+		elapsed  time.Duration
+		expected int
+	}{
+		{
+			name:     "linear",
+			decay:    storage.RewardDecay{Type: storage.DecayLinear, Interval: storage.Duration(10 * time.Minute), Amount: 10},
+			elapsed:  15 * time.Minute,
+			expected: 15,
+		},
+		{
+			name:     "step",
+			decay:    storage.RewardDecay{Type: storage.DecayStep, Interval: storage.Duration(10 * time.Minute), Amount: 10},
+			elapsed:  15 * time.Minute,
+			expected: 10,
+		},
+		{
+			name:     "min reward",
+			decay:    storage.RewardDecay{Type: storage.DecayStep, Interval: storage.Duration(time.Minute), Amount: 10, MinReward: 40},
+			elapsed:  time.Hour,
+			expected: 60,
+		},
+		{
+			name:     "before start",
+			decay:    storage.RewardDecay{Type: storage.DecayLinear, Interval: storage.Duration(time.Minute), Amount: 10},
+			elapsed:  -time.Hour,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, decayPoints(&tc.decay, 100, start, start.Add(tc.elapsed)))
+		})
+	}
+}
+
+func TestDecayStart(t *testing.T) {
+	questStart := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	pubTime := questStart.Add(time.Hour)
+	openingTime := questStart.Add(2 * time.Hour)
+	quest := &storage.Quest{StartTime: &questStart}
+
+	assert.Equal(t, questStart, *decayStart(storage.DecayFromQuestStart, quest, &storage.TaskGroup{PubTime: &pubTime}))
+	assert.Equal(t, questStart, *decayStart(storage.DecayFromGroupOpening, quest, &storage.TaskGroup{}))
+	assert.Equal(t, pubTime, *decayStart(storage.DecayFromGroupOpening, quest, &storage.TaskGroup{PubTime: &pubTime}))
+	assert.Equal(t, openingTime, *decayStart(storage.DecayFromGroupOpening, quest, &storage.TaskGroup{
+		PubTime:  &pubTime,
+		TeamInfo: &storage.TaskGroupTeamInfo{OpeningTime: openingTime},
+	}))
+}
+
+func TestService_GetTaskScore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, nil, nil, ah)
+
+	questStart := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	penalty := storage.NewScorePenalty(5)
+	task := &storage.Task{
+		ID:     "task",
+		Reward: 100,
+		Scoring: &storage.Scoring{
+			Decay:             &storage.RewardDecay{Type: storage.DecayStep, Interval: storage.Duration(time.Hour), Amount: 20},
+			FirstSolveBonuses: []int{30, 10},
+		},
+	}
+	hints := []storage.HintTake{{Hint: storage.Hint{Penalty: penalty}}}
+
+	gomock.InOrder(
+		ah.EXPECT().LockAnswerTries(gomock.Any(), &storage.LockAnswerTriesRequest{TaskID: "task"}).Return(nil),
+		ah.EXPECT().GetTaskSolveCount(gomock.Any(), &storage.GetTaskSolveCountRequest{TaskID: "task"}).Return(1, nil),
+	)
+	score, err := s.getTaskScore(context.Background(), &storage.Quest{StartTime: ptr.Time(questStart)}, &storage.TaskGroup{}, task, hints, questStart.Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, taskScore{Score: 100 - 5 - 20 + 10, Bonus: 10, Decay: 20}, score)
+
+	ah.EXPECT().LockAnswerTries(gomock.Any(), gomock.Any()).Return(nil)
+	ah.EXPECT().GetTaskSolveCount(gomock.Any(), gomock.Any()).Return(2, nil)
+	score, err = s.getTaskScore(context.Background(), &storage.Quest{StartTime: ptr.Time(questStart)}, &storage.TaskGroup{}, task, nil, questStart)
+	require.NoError(t, err)
+	assert.Equal(t, taskScore{Score: 100}, score)
+}
+
+func TestService_GetTaskScore_MinReward(t *testing.T) {
+	s := NewService(nil, nil, nil, nil)
+
+	questStart := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	task := &storage.Task{
+		ID:     "task",
+		Reward: 100,
+		Scoring: &storage.Scoring{
+			Decay: &storage.RewardDecay{Type: storage.DecayStep, Interval: storage.Duration(time.Hour), Amount: 20, MinReward: 40},
+		},
+	}
+	hints := []storage.HintTake{{Hint: storage.Hint{Penalty: storage.NewScorePenalty(50)}}}
+
+	score, err := s.getTaskScore(context.Background(), &storage.Quest{StartTime: ptr.Time(questStart)}, &storage.TaskGroup{}, task, hints, questStart.Add(5*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, taskScore{Score: 40, Decay: 60}, score)
+
+	task.Scoring.Decay.MinReward = 0
+	hints = append(hints, hints[0], hints[0])
+	score, err = s.getTaskScore(context.Background(), &storage.Quest{StartTime: ptr.Time(questStart)}, &storage.TaskGroup{}, task, hints, questStart)
+	require.NoError(t, err)
+	assert.Equal(t, taskScore{Score: 0}, score)
+}
+
+func TestService_GetTaskScore_ReducedReward(t *testing.T) {
+	s := NewService(nil, nil, nil, nil)
+
+	questStart := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	task := &storage.Task{
+		ID:     "task",
+		Reward: 100,
+		Scoring: &storage.Scoring{
+			Decay: &storage.RewardDecay{Type: storage.DecayStep, Interval: storage.Duration(time.Hour), Amount: 20, MinReward: 50},
+		},
+	}
+
+	part := withReward(task, 20)
+	assert.Equal(t, 10, part.Scoring.Decay.MinReward)
+	assert.Equal(t, 50, task.Scoring.Decay.MinReward)
+
+	score, err := s.getTaskScore(context.Background(), &storage.Quest{StartTime: ptr.Time(questStart)}, &storage.TaskGroup{}, part, nil, questStart.Add(5*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, taskScore{Score: 10, Decay: 10}, score)
+
+	// least reward stored before validation is clamped by task reward
+	task.Reward = 20
+	score, err = s.getTaskScore(context.Background(), &storage.Quest{StartTime: ptr.Time(questStart)}, &storage.TaskGroup{}, task, nil, questStart)
+	require.NoError(t, err)
+	assert.Equal(t, taskScore{Score: 20}, score)
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+	"go.uber.org/zap"
+
+	"questspace/internal/qtime"
+	"questspace/internal/questspace/events"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/logging"
+	"questspace/pkg/storage"
+)
+
+type SkipTaskGroupRequest struct {
+	QuestID     storage.ID `json:"-"`
+	TaskGroupID storage.ID `json:"task_group_id"`
+}
+
+type SkipTaskGroupResponse struct {
+	TaskGroupID storage.ID `json:"task_group_id"`
+	// Penalty is subtracted from team score for skipping the group
+	Penalty int `json:"penalty"`
+}
+
+// SkipTaskGroup closes current group of linear quest before all its tasks are solved, so that team proceeds further by its route.
+// Group has to allow skipping. Percent of skip penalty is taken from total reward of tasks not solved by team.
+func (s *Service) SkipTaskGroup(ctx context.Context, user *storage.User, req *SkipTaskGroupRequest) (*SkipTaskGroupResponse, error) {
+	team, err := s.tms.GetTeam(ctx, &storage.GetTeamRequest{UserRegistration: &storage.UserRegistration{UserID: user.ID, QuestID: req.QuestID}})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.Errorf(http.StatusNotFound, "team for user %q not found", user.ID)
+		}
+		return nil, xerrors.Errorf("get team: %w", err)
+	}
+	if team.RegistrationStatus != storage.RegistrationStatusAccepted {
+		return nil, httperrors.New(http.StatusForbidden, "only accepted teams can skip task groups")
+	}
+	if err = checkQuestRunning(team.Quest); err != nil {
+		return nil, err
+	}
+	if team.Quest.QuestType != storage.TypeLinear {
+		return nil, httperrors.New(http.StatusNotAcceptable, "task groups can be skipped only in linear quests")
+	}
+
+	taskGroup, err := s.tgs.GetTaskGroup(ctx, &storage.GetTaskGroupRequest{
+		ID:           req.TaskGroupID,
+		IncludeTasks: true,
+		TeamData:     &storage.TeamData{TeamID: &team.ID},
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.Errorf(http.StatusNotFound, "task group %q not found", req.TaskGroupID)
+		}
+		return nil, xerrors.Errorf("get task group: %w", err)
+	}
+	now := qtime.Now()
+	if taskGroup.Quest.ID != req.QuestID || !isPublished(taskGroup.PubTime, now) {
+		return nil, httperrors.Errorf(http.StatusNotFound, "task group %q not found", req.TaskGroupID)
+	}
+	if !taskGroup.AllowSkip || taskGroup.Sticky {
+		return nil, httperrors.Errorf(http.StatusNotAcceptable, "task group %q cannot be skipped", req.TaskGroupID)
+	}
+	if taskGroup.TeamInfo == nil {
+		return nil, httperrors.Errorf(http.StatusNotAcceptable, "task group %q is not opened yet", req.TaskGroupID)
+	}
+	if taskGroup.TeamInfo.ClosingTime != nil {
+		return nil, httperrors.Errorf(http.StatusNotAcceptable, "task group %q is already closed", req.TaskGroupID)
+	}
+	if taskGroup.HasTimeLimit && taskGroup.TimeLimit != nil {
+		deadline := taskGroup.TeamInfo.OpeningTime.Add(time.Duration(*taskGroup.TimeLimit))
+		if deadline.Before(now) {
+			if err = s.closeGroup(ctx, team, taskGroup, deadline, events.GroupTimeLimit, nil); err != nil {
+				logging.Error(ctx, "could not close task group", zap.Error(err))
+			}
+			return nil, httperrors.Errorf(http.StatusNotAcceptable, "task group %q deadline exceeded", req.TaskGroupID)
+		}
+	}
+
+	resp := &SkipTaskGroupResponse{TaskGroupID: taskGroup.ID}
+	if taskGroup.SkipPenalty != nil {
+		acceptedTasks, err := s.ah.GetAcceptedTasks(ctx, &storage.GetAcceptedTasksRequest{TeamID: team.ID, QuestID: req.QuestID})
+		if err != nil {
+			return nil, xerrors.Errorf("get accepted tasks: %w", err)
+		}
+		resp.Penalty = taskGroup.SkipPenalty.GetPenaltyPoints(unsolvedReward(acceptedTasks, taskGroup.Tasks))
+	}
+	logging.Info(ctx, "task group skip",
+		zap.Stringer("team_id", team.ID),
+		zap.String("team_name", team.Name),
+		zap.Stringer("task_group_id", taskGroup.ID),
+		zap.Int("penalty", resp.Penalty),
+	)
+	// group is closed only if it is still open, so that concurrent skips charge the penalty once
+	closeReq := closeGroupRequest(team, taskGroup, now, nil)
+	closeReq.OnlyOpen = true
+	if _, err = s.tgs.UpsertTeamInfo(ctx, closeReq); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, httperrors.Errorf(http.StatusNotAcceptable, "task group %q is already closed", req.TaskGroupID)
+		}
+		return nil, xerrors.Errorf("close task group: %w", err)
+	}
+	if err = s.recordGroupClosed(ctx, team, taskGroup, events.GroupSkipped, closeReq.NextGroupID); err != nil {
+		return nil, xerrors.Errorf("record group closed: %w", err)
+	}
+	if resp.Penalty > 0 {
+		if err = s.ah.CreatePenalty(ctx, &storage.CreatePenaltyRequest{
+			TeamID:      team.ID,
+			Penalty:     resp.Penalty,
+			TaskGroupID: taskGroup.ID,
+		}); err != nil {
+			return nil, xerrors.Errorf("create skip penalty: %w", err)
+		}
+		s.events.Add(events.New(events.PenaltyAdded, team.Quest.ID, team.ID, events.PenaltyData{Penalty: resp.Penalty, TaskGroupID: taskGroup.ID}))
+	}
+	return resp, nil
+}
+
+func unsolvedReward(accepted storage.AcceptedTasks, tasks []storage.Task) int {
+	reward := 0
+	for _, task := range tasks {
+		reward += unsolvedTaskReward(&task, accepted)
+	}
+	return reward
+}
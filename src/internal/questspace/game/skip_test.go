@@ -0,0 +1,80 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestService_SkipTaskGroup(t *testing.T) {
+	replaceNowFunc(t)
+	ctrl := gomock.NewController(t)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	tgs := mocks.NewMockTaskGroupStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, tgs, tms, ah)
+
+	user := &storage.User{ID: "user"}
+	team := &storage.Team{
+		ID:                 "team",
+		RegistrationStatus: storage.RegistrationStatusAccepted,
+		Quest: &storage.Quest{
+			ID:        "quest",
+			QuestType: storage.TypeLinear,
+			StartTime: ptr.Time(visibilityNow.Add(-time.Hour)),
+		},
+	}
+	penalty, err := storage.NewPercentagePenalty(50)
+	require.NoError(t, err)
+	next := storage.ID("next")
+	taskGroup := &storage.TaskGroup{
+		ID:          "stuck",
+		Quest:       &storage.Quest{ID: "quest"},
+		Tasks:       []storage.Task{{ID: "solved", Reward: 100}, {ID: "hard", Reward: 60}, {ID: "harder", Reward: 40}},
+		Transitions: []storage.Transition{{NextGroupID: next}},
+		AllowSkip:   true,
+		SkipPenalty: &penalty,
+		TeamInfo:    &storage.TaskGroupTeamInfo{OpeningTime: visibilityNow.Add(-time.Hour)},
+	}
+
+	tms.EXPECT().GetTeam(gomock.Any(), gomock.Any()).Return(team, nil).AnyTimes()
+	tgs.EXPECT().GetTaskGroup(gomock.Any(), gomock.Any()).Return(taskGroup, nil)
+	ah.EXPECT().GetAcceptedTasks(gomock.Any(), gomock.Any()).Return(storage.AcceptedTasks{"solved": {Score: 100}}, nil)
+	ah.EXPECT().CreatePenalty(gomock.Any(), &storage.CreatePenaltyRequest{TeamID: "team", Penalty: 50, TaskGroupID: "stuck"}).Return(nil)
+	tgs.EXPECT().UpsertTeamInfo(gomock.Any(), &storage.UpsertTeamInfoRequest{
+		TeamID:      "team",
+		TaskGroupID: "stuck",
+		OpeningTime: taskGroup.TeamInfo.OpeningTime,
+		ClosingTime: &visibilityNow,
+		NextGroupID: &next,
+		OnlyOpen:    true,
+	}).Return(&storage.TaskGroupTeamInfo{}, nil)
+
+	resp, err := s.SkipTaskGroup(context.Background(), user, &SkipTaskGroupRequest{QuestID: "quest", TaskGroupID: "stuck"})
+	require.NoError(t, err)
+	assert.Equal(t, &SkipTaskGroupResponse{TaskGroupID: "stuck", Penalty: 50}, resp)
+
+	// concurrent skip has already closed the group, so penalty is not charged again
+	tgs.EXPECT().GetTaskGroup(gomock.Any(), gomock.Any()).Return(taskGroup, nil)
+	ah.EXPECT().GetAcceptedTasks(gomock.Any(), gomock.Any()).Return(storage.AcceptedTasks{"solved": {Score: 100}}, nil)
+	tgs.EXPECT().UpsertTeamInfo(gomock.Any(), gomock.Any()).Return(nil, storage.ErrNotFound)
+	_, err = s.SkipTaskGroup(context.Background(), user, &SkipTaskGroupRequest{QuestID: "quest", TaskGroupID: "stuck"})
+	requireHTTPCode(t, http.StatusNotAcceptable, err)
+
+	tgs.EXPECT().GetTaskGroup(gomock.Any(), gomock.Any()).Return(&storage.TaskGroup{
+		ID:       "strict",
+		Quest:    &storage.Quest{ID: "quest"},
+		TeamInfo: &storage.TaskGroupTeamInfo{OpeningTime: visibilityNow.Add(-time.Hour)},
+	}, nil)
+	_, err = s.SkipTaskGroup(context.Background(), user, &SkipTaskGroupRequest{QuestID: "quest", TaskGroupID: "strict"})
+	requireHTTPCode(t, http.StatusNotAcceptable, err)
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"questspace/pkg/spreadsheet"
+	"questspace/pkg/storage"
+)
+
+// Sheet converts results to table with human-readable headers, ordered by place
+func (r *TeamResults) Sheet() *spreadsheet.Sheet {
+	header := []string{"Place", "Team"}
+	// multi-part tasks get additional column with number of solved parts, so that score stays numeric
+	var withParts []bool
+	for _, tg := range r.TaskGroups {
+		for _, task := range tg.Tasks {
+			header = append(header, tg.Name+" / "+task.Name)
+			if task.Type == storage.TaskTypeParts {
+				header = append(header, tg.Name+" / "+task.Name+" / solved parts")
+			}
+			withParts = append(withParts, task.Type == storage.TaskTypeParts)
+		}
+	}
+	header = append(header, "Task score", "Bonus codes", "Penalty", "Total score", "Last correct answer")
+
+	rows := make([][]any, 0, len(r.Results))
+	for i, res := range r.Results {
+		row := make([]any, 0, len(header))
+		row = append(row, i+1, res.TeamName)
+		for j, taskRes := range res.TaskResults {
+			row = append(row, taskRes.Score)
+			if j < len(withParts) && withParts[j] {
+				row = append(row, taskRes.SolvedParts)
+			}
+		}
+		row = append(row, res.TaskScore, res.BonusCodes, res.Penalty, res.TotalScore, res.lastCorrectAnswerTime)
+		rows = append(rows, row)
+	}
+	return &spreadsheet.Sheet{Name: "Results", Header: header, Rows: rows}
+}
+
+// AnswerLogHeader is header of exported answer log table, which rows are built with AnswerLog.Row
+var AnswerLogHeader = []string{"Time", "Team", "User", "Task group", "Task", "Answer", "Accepted", "Score"}
+
+func (l *AnswerLog) Row() []any {
+	accepted := "no"
+	if l.Accepted {
+		accepted = "yes"
+	}
+	return []any{l.AnswerTime, l.Team, l.User, l.TaskGroup, l.Task, l.Answer, accepted, l.Score}
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+func TestTeamResults_Sheet(t *testing.T) {
+	lastAnswer := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	results := TeamResults{
+		TaskGroups: []storage.TaskGroup{
+			{Name: "Warmup", Tasks: []storage.Task{{Name: "First"}, {Name: "Second"}}},
+			{Name: "Final", Tasks: []storage.Task{{Name: "Boss"}}},
+		},
+		Results: []TeamResult{
+			{
+				TeamName:              "Winners",
+				TaskScore:             30,
+				BonusCodes:            3,
+				Penalty:               5,
+				TotalScore:            28,
+				TaskResults:           []TaskResult{{Score: 10}, {Score: 0}, {Score: 20}},
+				lastCorrectAnswerTime: ptr.Time(lastAnswer),
+			},
+			{
+				TeamName:    "Others",
+				TaskResults: []TaskResult{{}, {}, {}},
+			},
+		},
+	}
+
+	sheet := results.Sheet()
+	assert.Equal(t, []string{
+		"Place", "Team", "Warmup / First", "Warmup / Second", "Final / Boss",
+		"Task score", "Bonus codes", "Penalty", "Total score", "Last correct answer",
+	}, sheet.Header)
+	assert.Equal(t, [][]any{
+		{1, "Winners", 10, 0, 20, 30, 3, 5, 28, ptr.Time(lastAnswer)},
+		{2, "Others", 0, 0, 0, 0, 0, 0, 0, (*time.Time)(nil)},
+	}, sheet.Rows)
+}
+
+func TestTeamResults_Sheet_Parts(t *testing.T) {
+	results := TeamResults{
+		TaskGroups: []storage.TaskGroup{
+			{Name: "Warmup", Tasks: []storage.Task{{Name: "Parts", Type: storage.TaskTypeParts}, {Name: "Text"}}},
+		},
+		Results: []TeamResult{
+			{
+				TeamName:    "Winners",
+				TaskScore:   40,
+				TotalScore:  40,
+				TaskResults: []TaskResult{{Score: 30, SolvedParts: 1, Parts: 2}, {Score: 10}},
+			},
+		},
+	}
+
+	sheet := results.Sheet()
+	assert.Equal(t, []string{
+		"Place", "Team", "Warmup / Parts", "Warmup / Parts / solved parts", "Warmup / Text",
+		"Task score", "Bonus codes", "Penalty", "Total score", "Last correct answer",
+	}, sheet.Header)
+	assert.Equal(t, [][]any{
+		{1, "Winners", 30, 1, 10, 40, 0, 0, 40, (*time.Time)(nil)},
+	}, sheet.Rows)
+}
+
+func TestService_IterAnswerLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, nil, nil, ah)
+
+	answerTime := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	ah.EXPECT().
+		IterAnswerTries(gomock.Any(), &storage.GetAnswerTriesRequest{QuestID: "quest"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *storage.GetAnswerTriesRequest, fn func(*storage.AnswerLog) error, _ ...storage.FilteringOption) error {
+			return fn(&storage.AnswerLog{
+				Team:       &storage.Team{ID: "team", Name: "Winners"},
+				User:       &storage.User{ID: "user", Username: "player"},
+				TaskGroup:  &storage.TaskGroup{ID: "group", Name: "Warmup"},
+				Task:       &storage.Task{ID: "task", Name: "First"},
+				Accepted:   true,
+				Answer:     "42",
+				AnswerTime: answerTime,
+				Score:      10,
+			})
+		})
+
+	var rows [][]any
+	err := s.IterAnswerLogs(context.Background(), "quest", func(l *AnswerLog) error {
+		rows = append(rows, l.Row())
+		return nil
+	}, storage.WithOnlyAccepted())
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{answerTime, "Winners", "player", "Warmup", "First", "42", "yes", 10}}, rows)
+}
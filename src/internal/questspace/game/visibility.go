@@ -0,0 +1,37 @@
+package game
+
+import (
+	"net/http"
+	"time"
+
+	"questspace/internal/questspace/quests"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// checkQuestRunning returns error if teams cannot play quest at the moment
+func checkQuestRunning(q *storage.Quest) error {
+	quests.SetStatus(q)
+	switch q.Status {
+	case storage.StatusRunning:
+		return nil
+	case storage.StatusOnRegistration, storage.StatusRegistrationDone:
+		return httperrors.New(http.StatusNotAcceptable, "quest has not started yet")
+	default:
+		return httperrors.New(http.StatusNotAcceptable, "quest is already finished")
+	}
+}
+
+// isPublished reports whether content with given publication time can be shown to teams
+func isPublished(pubTime *time.Time, now time.Time) bool {
+	return pubTime == nil || !pubTime.After(now)
+}
+
+// checkTaskPublished returns not found error for tasks of unpublished groups or unpublished tasks,
+// so that teams cannot tell hidden tasks from missing ones
+func checkTaskPublished(taskGroup *storage.TaskGroup, task *storage.Task, now time.Time) error {
+	if !isPublished(taskGroup.PubTime, now) || !isPublished(task.PubTime, now) {
+		return httperrors.Errorf(http.StatusNotFound, "task %q not found", task.ID)
+	}
+	return nil
+}
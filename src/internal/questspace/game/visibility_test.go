@@ -0,0 +1,210 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/internal/qtime"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+var visibilityNow = time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+
+func replaceNowFunc(t *testing.T) {
+	qtime.SetNowFunc(t, func() time.Time {
+		return visibilityNow
+	})
+}
+
+func requireHTTPCode(t *testing.T, expectedCode int, err error) {
+	t.Helper()
+	var httpErr *httperrors.HTTPError
+	require.True(t, errors.As(err, &httpErr), "expected http error, got %v", err)
+	assert.Equal(t, expectedCode, httpErr.Code)
+}
+
+func TestCheckQuestRunning(t *testing.T) {
+	replaceNowFunc(t)
+	testCases := []struct {
+		name    string
+		quest   storage.Quest
+		running bool
+	}{
+		{
+			name:  "on registration",
+			quest: storage.Quest{StartTime: ptr.Time(visibilityNow.Add(time.Hour))},
+		},
+		{
+			name: "registration done",
+			quest: storage.Quest{
+				RegistrationDeadline: ptr.Time(visibilityNow.Add(-time.Hour)),
+				StartTime:            ptr.Time(visibilityNow.Add(time.Hour)),
+			},
+		},
+		{
+			name:    "running",
+			quest:   storage.Quest{StartTime: ptr.Time(visibilityNow.Add(-time.Hour))},
+			running: true,
+		},
+		{
+			name: "wait results",
+			quest: storage.Quest{
+				StartTime:  ptr.Time(visibilityNow.Add(-2 * time.Hour)),
+				FinishTime: ptr.Time(visibilityNow.Add(-time.Hour)),
+			},
+		},
+		{
+			name: "finished",
+			quest: storage.Quest{
+				StartTime: ptr.Time(visibilityNow.Add(-2 * time.Hour)),
+				Status:    storage.StatusFinished,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkQuestRunning(&tc.quest)
+			if tc.running {
+				assert.NoError(t, err)
+				return
+			}
+			requireHTTPCode(t, http.StatusNotAcceptable, err)
+		})
+	}
+}
+
+func TestCheckTaskPublished(t *testing.T) {
+	replaceNowFunc(t)
+	now := qtime.Now()
+	past, future := ptr.Time(now.Add(-time.Minute)), ptr.Time(now.Add(time.Minute))
+
+	assert.NoError(t, checkTaskPublished(&storage.TaskGroup{}, &storage.Task{}, now))
+	assert.NoError(t, checkTaskPublished(&storage.TaskGroup{PubTime: past}, &storage.Task{PubTime: ptr.Time(now)}, now))
+	requireHTTPCode(t, http.StatusNotFound, checkTaskPublished(&storage.TaskGroup{PubTime: future}, &storage.Task{}, now))
+	requireHTTPCode(t, http.StatusNotFound, checkTaskPublished(&storage.TaskGroup{PubTime: past}, &storage.Task{PubTime: future}, now))
+}
+
+func TestFillAnswerData_HidesUnpublished(t *testing.T) {
+	replaceNowFunc(t)
+	past, future := ptr.Time(visibilityNow.Add(-time.Hour)), ptr.Time(visibilityNow.Add(time.Hour))
+	taskGroups := []storage.TaskGroup{
+		{
+			ID:      "published",
+			PubTime: past,
+			Tasks: []storage.Task{
+				{ID: "visible", PubTime: past},
+				{ID: "hidden", PubTime: future},
+				{ID: "no pub time"},
+			},
+		},
+		{ID: "unpublished", PubTime: future, Tasks: []storage.Task{{ID: "hidden in group"}}},
+		{ID: "last", Tasks: []storage.Task{{ID: "last task"}}},
+	}
+
+	t.Run("assault", func(t *testing.T) {
+		s := &Service{}
+		resp := s.fillAnswerData(context.Background(), &AnswerDataRequest{
+			Quest:      &storage.Quest{QuestType: storage.TypeAssault},
+			Team:       &storage.Team{},
+			TaskGroups: taskGroups,
+		}, nil, nil, nil)
+
+		require.Len(t, resp.TaskGroups, 2)
+		assert.Equal(t, storage.ID("published"), resp.TaskGroups[0].ID)
+		require.Len(t, resp.TaskGroups[0].Tasks, 2)
+		assert.Equal(t, storage.ID("visible"), resp.TaskGroups[0].Tasks[0].ID)
+		assert.Equal(t, storage.ID("no pub time"), resp.TaskGroups[0].Tasks[1].ID)
+		assert.Equal(t, storage.ID("last"), resp.TaskGroups[1].ID)
+	})
+
+	t.Run("linear stops at unpublished group", func(t *testing.T) {
+		s := &Service{}
+		closed := visibilityNow.Add(-time.Minute)
+		linearGroups := append([]storage.TaskGroup(nil), taskGroups...)
+		linearGroups[0].TeamInfo = &storage.TaskGroupTeamInfo{OpeningTime: visibilityNow.Add(-time.Hour), ClosingTime: &closed}
+		resp := s.fillAnswerData(context.Background(), &AnswerDataRequest{
+			Quest:      &storage.Quest{QuestType: storage.TypeLinear},
+			Team:       &storage.Team{},
+			TaskGroups: linearGroups,
+		}, nil, nil, nil)
+
+		require.Len(t, resp.TaskGroups, 1)
+		assert.Equal(t, storage.ID("published"), resp.TaskGroups[0].ID)
+	})
+}
+
+func TestService_GetLeaderboard_Frozen(t *testing.T) {
+	replaceNowFunc(t)
+	ctrl := gomock.NewController(t)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, nil, tms, ah)
+
+	creator := &storage.User{ID: "creator"}
+	freeze := storage.Duration(time.Hour)
+	quest := &storage.Quest{
+		ID:                "quest",
+		Creator:           creator,
+		FinishTime:        ptr.Time(visibilityNow.Add(time.Minute)),
+		LeaderboardFreeze: &freeze,
+	}
+	freezeTime := visibilityNow.Add(time.Minute - time.Hour)
+
+	tms.EXPECT().GetTeams(gomock.Any(), gomock.Any()).Return([]storage.Team{{ID: "team"}}, nil).Times(2)
+	ah.EXPECT().GetScoreResults(gomock.Any(), &storage.GetResultsRequest{QuestID: "quest", Before: &freezeTime}).Return(storage.ScoreResults{}, nil)
+	ah.EXPECT().GetPenalties(gomock.Any(), &storage.GetPenaltiesRequest{QuestID: "quest", Before: &freezeTime}).Return(storage.TeamPenalties{}, nil)
+	ah.EXPECT().GetBonusCodeUses(gomock.Any(), &storage.GetBonusCodeUsesRequest{QuestID: "quest", Before: &freezeTime}).Return(storage.TeamBonusCodes{}, nil)
+	res, err := s.GetLeaderboard(context.Background(), quest, &storage.User{ID: "player"})
+	require.NoError(t, err)
+	assert.Equal(t, &freezeTime, res.FrozenAt)
+
+	ah.EXPECT().GetScoreResults(gomock.Any(), &storage.GetResultsRequest{QuestID: "quest"}).Return(storage.ScoreResults{}, nil)
+	ah.EXPECT().GetPenalties(gomock.Any(), &storage.GetPenaltiesRequest{QuestID: "quest"}).Return(storage.TeamPenalties{}, nil)
+	ah.EXPECT().GetBonusCodeUses(gomock.Any(), &storage.GetBonusCodeUsesRequest{QuestID: "quest"}).Return(storage.TeamBonusCodes{}, nil)
+	res, err = s.GetLeaderboard(context.Background(), quest, creator)
+	require.NoError(t, err)
+	assert.Equal(t, &freezeTime, res.FrozenAt)
+}
+
+func TestService_GetScoreHistory(t *testing.T) {
+	replaceNowFunc(t)
+	ctrl := gomock.NewController(t)
+	tms := mocks.NewMockTeamStorage(ctrl)
+	ah := mocks.NewMockAnswerHintStorage(ctrl)
+	s := NewService(nil, nil, tms, ah)
+
+	freeze := storage.Duration(time.Hour)
+	quest := &storage.Quest{
+		ID:                "quest",
+		Creator:           &storage.User{ID: "creator"},
+		FinishTime:        ptr.Time(visibilityNow.Add(time.Minute)),
+		LeaderboardFreeze: &freeze,
+	}
+	freezeTime := visibilityNow.Add(time.Minute - time.Hour)
+	point := storage.ScorePoint{Time: freezeTime.Add(-time.Minute), Score: 10}
+
+	tms.EXPECT().GetTeams(gomock.Any(), gomock.Any()).Return([]storage.Team{{ID: "team", Name: "Winners"}, {ID: "silent", Name: "Silent"}}, nil)
+	ah.EXPECT().
+		GetScoreHistory(gomock.Any(), &storage.GetScoreHistoryRequest{QuestID: "quest", Bucket: time.Minute, Before: &freezeTime}).
+		Return(storage.ScoreHistory{"team": {point}}, nil)
+	res, err := s.GetScoreHistory(context.Background(), quest, nil, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, &ScoreHistoryResponse{
+		Teams: []TeamScoreHistory{
+			{TeamID: "team", TeamName: "Winners", Points: []ScoreHistoryPoint{{Time: point.Time, Score: 10}}},
+			{TeamID: "silent", TeamName: "Silent", Points: []ScoreHistoryPoint{}},
+		},
+		FrozenAt: &freezeTime,
+	}, res)
+}
@@ -0,0 +1,141 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+	"go.uber.org/zap"
+
+	"questspace/internal/pgdb"
+	"questspace/internal/qtime"
+	"questspace/internal/questspace/events"
+	"questspace/internal/questspace/game"
+	"questspace/pkg/dbnode"
+	"questspace/pkg/logging"
+	"questspace/pkg/storage"
+)
+
+// Service moves quests through their lifecycle without waiting for requests of creators or teams.
+// Its methods are meant to be run periodically by scheduler.
+type Service struct {
+	clientFactory pgdb.QuestspaceClientFactory
+	events        events.Publisher
+}
+
+func NewService(cf pgdb.QuestspaceClientFactory, publisher events.Publisher) *Service {
+	return &Service{
+		clientFactory: cf,
+		events:        publisher,
+	}
+}
+
+// FinishExpiredQuests finishes quests with auto finish, which finish time has passed
+func (s *Service) FinishExpiredQuests(ctx context.Context) error {
+	st, err := s.clientFactory.NewStorage(ctx, dbnode.Master)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	questIDs, err := st.GetExpiredQuests(ctx, &storage.GetExpiredQuestsRequest{Now: qtime.Now()})
+	if err != nil {
+		return xerrors.Errorf("get expired quests: %w", err)
+	}
+	var errs []error
+	for _, questID := range questIDs {
+		if err = s.finishQuest(ctx, questID); err != nil {
+			errs = append(errs, xerrors.Errorf("finish quest %q: %w", questID, err))
+			continue
+		}
+		logging.Info(ctx, "finished expired quest", zap.Stringer("quest_id", questID))
+	}
+	return errors.Join(errs...)
+}
+
+// finishQuest sends QuestFinished event in the same transaction, so that it is not lost
+// when instance stops right after quest is finished
+func (s *Service) finishQuest(ctx context.Context, questID storage.ID) error {
+	st, tx, err := s.clientFactory.NewStorageTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("start tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = st.FinishQuest(ctx, &storage.FinishQuestRequest{ID: questID}); err != nil {
+		return xerrors.Errorf("finish quest: %w", err)
+	}
+	if err = pgdb.NotifyEvent(ctx, st, events.New(events.QuestFinished, questID, "", nil)); err != nil {
+		return xerrors.Errorf("notify: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return xerrors.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// CloseExpiredGroups closes task groups of running linear quests, which time limit is exceeded by teams
+func (s *Service) CloseExpiredGroups(ctx context.Context) error {
+	st, err := s.clientFactory.NewStorage(ctx, dbnode.Master)
+	if err != nil {
+		return xerrors.Errorf("get storage: %w", err)
+	}
+	questIDs, err := st.GetRunningQuests(ctx, &storage.GetRunningQuestsRequest{Now: qtime.Now(), QuestType: storage.TypeLinear})
+	if err != nil {
+		return xerrors.Errorf("get running quests: %w", err)
+	}
+	var errs []error
+	for _, questID := range questIDs {
+		if err = s.closeExpiredGroups(ctx, st, questID); err != nil {
+			errs = append(errs, xerrors.Errorf("quest %q: %w", questID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) closeExpiredGroups(ctx context.Context, st storage.QuestSpaceStorage, questID storage.ID) error {
+	taskGroups, err := st.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID})
+	if err != nil {
+		return xerrors.Errorf("get task groups: %w", err)
+	}
+	if !hasTimeLimit(taskGroups) {
+		return nil
+	}
+	quest, err := st.GetQuest(ctx, &storage.GetQuestRequest{ID: questID})
+	if err != nil {
+		return xerrors.Errorf("get quest: %w", err)
+	}
+	teams, err := st.GetTeams(ctx, &storage.GetTeamsRequest{QuestIDs: []storage.ID{questID}, AcceptedOnly: true})
+	if err != nil {
+		return xerrors.Errorf("get teams: %w", err)
+	}
+
+	batch := events.NewBatch(s.events)
+	defer batch.Publish()
+	srv := game.NewService(st, st, st, st, game.WithEvents(batch))
+	var errs []error
+	for i := range teams {
+		team := &teams[i]
+		team.Quest = quest
+		teamGroups, err := st.GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{
+			QuestID:      questID,
+			IncludeTasks: true,
+			TeamData:     &storage.TeamData{TeamID: &team.ID},
+		})
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("get task groups of team %q: %w", team.ID, err))
+			continue
+		}
+		if err = srv.CloseExpiredGroups(ctx, quest, team, teamGroups); err != nil {
+			errs = append(errs, xerrors.Errorf("team %q: %w", team.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func hasTimeLimit(taskGroups []storage.TaskGroup) bool {
+	for _, tg := range taskGroups {
+		if tg.HasTimeLimit && tg.TimeLimit != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/internal/pgdb"
+	"questspace/internal/qtime"
+	"questspace/internal/questspace/events"
+	"questspace/pkg/storage"
+	"questspace/pkg/storage/mocks"
+)
+
+var now = time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+
+func TestService_FinishExpiredQuests(t *testing.T) {
+	qtime.SetNowFunc(t, func() time.Time { return now })
+	ctrl := gomock.NewController(t)
+	s := mocks.NewMockQuestSpaceStorage(ctrl)
+	factory := pgdb.NewFakeClientFactory(s)
+	srv := NewService(factory, events.NewBroker())
+
+	s.EXPECT().GetExpiredQuests(gomock.Any(), &storage.GetExpiredQuestsRequest{Now: now}).Return([]storage.ID{"failed", "expired"}, nil)
+	s.EXPECT().FinishQuest(gomock.Any(), &storage.FinishQuestRequest{ID: "failed"}).Return(errors.New("db is down"))
+	s.EXPECT().FinishQuest(gomock.Any(), &storage.FinishQuestRequest{ID: "expired"}).Return(nil)
+	s.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.NotifyRequest) error {
+		e, err := events.Decode([]byte(req.Payload))
+		require.NoError(t, err)
+		assert.Equal(t, events.QuestFinished, e.Type)
+		assert.Equal(t, storage.ID("expired"), e.QuestID)
+		return nil
+	})
+
+	err := srv.FinishExpiredQuests(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `finish quest "failed"`)
+	factory.ExpectCommit(t)
+}
+
+func TestService_CloseExpiredGroups(t *testing.T) {
+	qtime.SetNowFunc(t, func() time.Time { return now })
+	ctrl := gomock.NewController(t)
+	s := mocks.NewMockQuestSpaceStorage(ctrl)
+	broker := events.NewBroker()
+	srv := NewService(pgdb.NewFakeClientFactory(s), broker)
+	sub := broker.Subscribe("linear", 2)
+	defer sub.Close()
+
+	start := now.Add(-2 * time.Hour)
+	limit := storage.Duration(30 * time.Minute)
+	quest := &storage.Quest{ID: "linear", QuestType: storage.TypeLinear, StartTime: &start}
+	first := storage.TaskGroup{ID: "first", OrderIdx: 0, HasTimeLimit: true, TimeLimit: &limit}
+	second := storage.TaskGroup{ID: "second", OrderIdx: 1}
+
+	s.EXPECT().GetRunningQuests(gomock.Any(), &storage.GetRunningQuestsRequest{Now: now, QuestType: storage.TypeLinear}).
+		Return([]storage.ID{"linear", "untimed"}, nil)
+	s.EXPECT().GetTaskGroups(gomock.Any(), &storage.GetTaskGroupsRequest{QuestID: "untimed"}).Return([]storage.TaskGroup{second}, nil)
+	s.EXPECT().GetTaskGroups(gomock.Any(), &storage.GetTaskGroupsRequest{QuestID: "linear"}).Return([]storage.TaskGroup{first, second}, nil)
+	s.EXPECT().GetQuest(gomock.Any(), &storage.GetQuestRequest{ID: "linear"}).Return(quest, nil)
+	s.EXPECT().GetTeams(gomock.Any(), &storage.GetTeamsRequest{QuestIDs: []storage.ID{"linear"}, AcceptedOnly: true}).
+		Return([]storage.Team{{ID: "team"}}, nil)
+
+	teamID := storage.ID("team")
+	teamFirst := first
+	teamFirst.TeamInfo = &storage.TaskGroupTeamInfo{OpeningTime: start}
+	s.EXPECT().GetTaskGroups(gomock.Any(), &storage.GetTaskGroupsRequest{
+		QuestID:      "linear",
+		IncludeTasks: true,
+		TeamData:     &storage.TeamData{TeamID: &teamID},
+	}).Return([]storage.TaskGroup{teamFirst, second}, nil)
+	deadline := start.Add(30 * time.Minute)
+	s.EXPECT().UpsertTeamInfo(gomock.Any(), &storage.UpsertTeamInfoRequest{
+		TeamID:      "team",
+		TaskGroupID: "first",
+		OpeningTime: start,
+		ClosingTime: &deadline,
+	}).Return(nil, nil)
+
+	require.NoError(t, srv.CloseExpiredGroups(context.Background()))
+
+	e := <-sub.Events()
+	assert.Equal(t, events.GroupClosed, e.Type)
+	assert.Equal(t, teamID, e.TeamID)
+	e = <-sub.Events()
+	assert.Equal(t, events.GroupOpened, e.Type)
+	assert.Equal(t, events.GroupData{TaskGroupID: "second"}, e.Data)
+}
+
+func TestService_CloseExpiredGroups_Error(t *testing.T) {
+	qtime.SetNowFunc(t, func() time.Time { return now })
+	ctrl := gomock.NewController(t)
+	s := mocks.NewMockQuestSpaceStorage(ctrl)
+	srv := NewService(pgdb.NewFakeClientFactory(s), events.NewBroker())
+
+	start := now.Add(-2 * time.Hour)
+	limit := storage.Duration(30 * time.Minute)
+	quest := &storage.Quest{ID: "linear", QuestType: storage.TypeLinear, StartTime: &start}
+	first := storage.TaskGroup{ID: "first", OrderIdx: 0, HasTimeLimit: true, TimeLimit: &limit}
+
+	s.EXPECT().GetRunningQuests(gomock.Any(), gomock.Any()).Return([]storage.ID{"linear"}, nil)
+	s.EXPECT().GetTaskGroups(gomock.Any(), &storage.GetTaskGroupsRequest{QuestID: "linear"}).Return([]storage.TaskGroup{first}, nil)
+	s.EXPECT().GetQuest(gomock.Any(), gomock.Any()).Return(quest, nil)
+	s.EXPECT().GetTeams(gomock.Any(), gomock.Any()).Return([]storage.Team{{ID: "broken"}, {ID: "team"}}, nil)
+	s.EXPECT().GetTaskGroups(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *storage.GetTaskGroupsRequest) ([]storage.TaskGroup, error) {
+		teamFirst := first
+		teamFirst.TeamInfo = &storage.TaskGroupTeamInfo{OpeningTime: start}
+		return []storage.TaskGroup{teamFirst}, nil
+	}).Times(2)
+	// the first team fails, while the second one is still handled
+	s.EXPECT().UpsertTeamInfo(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+	s.EXPECT().UpsertTeamInfo(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	err := srv.CloseExpiredGroups(context.Background())
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Contains(t, err.Error(), `team "broken"`)
+}
@@ -1,4 +1,5 @@
 // Package permutations is used to validate incoming order changes in tasks and task groups
+// and routes between task groups
 package permutations
 
 import "github.com/spkg/ptr"
@@ -108,3 +109,54 @@ func merge(parent []int, x int) int {
 	}
 	return x
 }
+
+// FindCycle returns vertices of some cycle in directed graph in order of traversal,
+// or nil if graph is acyclic. Unlike FindTreesAndCycles, vertices may have any number of outgoing edges.
+//
+// Panics when `edges` contain vertex greater or equal to `n`
+func FindCycle(edges []OrderChange, n int) []int {
+	graph := make([][]int, n)
+	for _, edge := range edges {
+		graph[edge.Prev] = append(graph[edge.Prev], edge.Next)
+	}
+
+	const (
+		unvisited = iota
+		inPath
+		visited
+	)
+	state := make([]int, n)
+	var path []int
+	var visit func(node int) []int
+	visit = func(node int) []int {
+		state[node] = inPath
+		path = append(path, node)
+		for _, next := range graph[node] {
+			switch state[next] {
+			case inPath:
+				for i, v := range path {
+					if v == next {
+						return append([]int(nil), path[i:]...)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[node] = visited
+		return nil
+	}
+
+	for node := range n {
+		if state[node] != unvisited {
+			continue
+		}
+		if cycle := visit(node); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
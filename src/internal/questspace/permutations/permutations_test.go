@@ -229,3 +229,76 @@ func BenchmarkFindConnectedComponents(b *testing.B) {
 		_ = findConnectedComponents(inputLen, inputGraph)
 	}
 }
+
+func TestFindCycle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		edges         []OrderChange
+		n             int
+		expectedCycle []int
+		panics        bool
+	}{
+		{
+			name:  "no edges",
+			n:     3,
+			edges: nil,
+		},
+		{
+			name: "branching dag",
+			edges: []OrderChange{
+				{0, 1},
+				{0, 2},
+				{1, 3},
+				{2, 3},
+				{3, 1},
+			},
+			n:             4,
+			expectedCycle: []int{1, 3},
+		},
+		{
+			name: "diamond",
+			edges: []OrderChange{
+				{2, 0},
+				{2, 1},
+				{0, 3},
+				{1, 3},
+			},
+			n: 4,
+		},
+		{
+			name:          "self loop",
+			edges:         []OrderChange{{0, 1}, {1, 1}},
+			n:             2,
+			expectedCycle: []int{1},
+		},
+		{
+			name: "cycle in second component",
+			edges: []OrderChange{
+				{0, 1},
+				{2, 4},
+				{4, 3},
+				{3, 2},
+			},
+			n:             5,
+			expectedCycle: []int{2, 4, 3},
+		},
+		{
+			name:   "panics on incorrect len",
+			edges:  []OrderChange{{0, 256}},
+			n:      10,
+			panics: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.panics {
+				require.Panics(t, func() {
+					FindCycle(tc.edges, tc.n)
+				})
+				return
+			}
+			assert.Equal(t, tc.expectedCycle, FindCycle(tc.edges, tc.n))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package quests
+
+import (
+	"time"
+
+	"questspace/pkg/storage"
+)
+
+// CloneRequest overrides fields of quest copy. When only start time is set,
+// other times of the quest are moved together with it.
+type CloneRequest struct {
+	QuestID              storage.ID `json:"-"`
+	Name                 string     `json:"name,omitempty"`
+	RegistrationDeadline *time.Time `json:"registration_deadline,omitempty" example:"2024-04-14T12:00:00+05:00"`
+	StartTime            *time.Time `json:"start_time,omitempty" example:"2024-04-14T14:00:00+05:00"`
+	FinishTime           *time.Time `json:"finish_time,omitempty" example:"2024-04-21T14:00:00+05:00"`
+}
+
+// CloneQuestRequest returns request to create copy of quest owned by creator and shift of its start time.
+// Teams, results and finished state are not copied, so the copy starts with open registration.
+func CloneQuestRequest(q *storage.Quest, creator *storage.User, req *CloneRequest) (*storage.CreateQuestRequest, time.Duration) {
+	var shift time.Duration
+	if req.StartTime != nil && q.StartTime != nil {
+		shift = req.StartTime.Sub(*q.StartTime)
+	}
+	createReq := &storage.CreateQuestRequest{
+		Name:                 q.Name,
+		Description:          q.Description,
+		Access:               q.Access,
+		Creator:              creator,
+		RegistrationDeadline: shiftTime(q.RegistrationDeadline, shift),
+		StartTime:            shiftTime(q.StartTime, shift),
+		FinishTime:           shiftTime(q.FinishTime, shift),
+		MediaLink:            q.MediaLink,
+		MaxTeamCap:           q.MaxTeamCap,
+		HasBrief:             q.HasBrief,
+		Brief:                q.Brief,
+		MaxTeamsAmount:       q.MaxTeamsAmount,
+		RegistrationType:     q.RegistrationType,
+		QuestType:            q.QuestType,
+		FeedbackLink:         q.FeedbackLink,
+		AnswerLimits:         q.AnswerLimits,
+		LeaderboardFreeze:    q.LeaderboardFreeze,
+		DefaultHintPenalty:   q.DefaultHintPenalty,
+		AutoFinish:           q.AutoFinish,
+	}
+	if len(req.Name) > 0 {
+		createReq.Name = req.Name
+	}
+	if req.RegistrationDeadline != nil {
+		createReq.RegistrationDeadline = req.RegistrationDeadline
+	}
+	if req.StartTime != nil {
+		createReq.StartTime = req.StartTime
+	}
+	if req.FinishTime != nil {
+		createReq.FinishTime = req.FinishTime
+	}
+	return createReq, shift
+}
+
+func shiftTime(t *time.Time, shift time.Duration) *time.Time {
+	if t == nil {
+		return nil
+	}
+	shifted := t.Add(shift)
+	return &shifted
+}
@@ -0,0 +1,51 @@
+package quests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+
+	"questspace/pkg/storage"
+)
+
+func TestCloneQuestRequest(t *testing.T) {
+	original := &storage.Quest{
+		ID:                   "original",
+		Name:                 "Night run",
+		Access:               storage.AccessPublic,
+		Creator:              &storage.User{ID: "author"},
+		RegistrationDeadline: ptr.Time(wantNow.Add(-48 * time.Hour)),
+		StartTime:            ptr.Time(wantNow.Add(-24 * time.Hour)),
+		FinishTime:           ptr.Time(wantNow.Add(-20 * time.Hour)),
+		Status:               storage.StatusFinished,
+		QuestType:            storage.TypeLinear,
+		LeaderboardRevealed:  true,
+	}
+	creator := &storage.User{ID: "organizer"}
+
+	t.Run("start time moves other times", func(t *testing.T) {
+		req, shift := CloneQuestRequest(original, creator, &CloneRequest{StartTime: ptr.Time(wantNow.Add(24 * time.Hour))})
+		assert.Equal(t, 48*time.Hour, shift)
+		assert.Equal(t, "Night run", req.Name)
+		assert.Equal(t, creator, req.Creator)
+		assert.Equal(t, original.QuestType, req.QuestType)
+		assert.Equal(t, wantNow, *req.RegistrationDeadline)
+		assert.Equal(t, wantNow.Add(24*time.Hour), *req.StartTime)
+		assert.Equal(t, wantNow.Add(28*time.Hour), *req.FinishTime)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		req, shift := CloneQuestRequest(original, creator, &CloneRequest{
+			Name:                 "Night run for group B",
+			RegistrationDeadline: ptr.Time(wantNow),
+			FinishTime:           ptr.Time(wantNow.Add(time.Hour)),
+		})
+		assert.Zero(t, shift)
+		assert.Equal(t, "Night run for group B", req.Name)
+		assert.Equal(t, wantNow, *req.RegistrationDeadline)
+		assert.Equal(t, *original.StartTime, *req.StartTime)
+		assert.Equal(t, wantNow.Add(time.Hour), *req.FinishTime)
+	})
+}
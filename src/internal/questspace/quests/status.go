@@ -1,6 +1,8 @@
 package quests
 
 import (
+	"time"
+
 	"questspace/internal/qtime"
 	"questspace/pkg/storage"
 )
@@ -25,3 +27,15 @@ func SetStatus(q *storage.Quest) {
 	}
 	q.Status = storage.StatusWaitResults
 }
+
+// LeaderboardFreezeTime returns moment since which leaderboard is frozen for teams, or nil if they see live results
+func LeaderboardFreezeTime(q *storage.Quest) *time.Time {
+	if q.LeaderboardFreeze == nil || *q.LeaderboardFreeze <= 0 || q.FinishTime == nil || q.LeaderboardRevealed {
+		return nil
+	}
+	freezeTime := q.FinishTime.Add(-time.Duration(*q.LeaderboardFreeze))
+	if qtime.Now().Before(freezeTime) {
+		return nil
+	}
+	return &freezeTime
+}
@@ -90,3 +90,50 @@ func TestSetStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestLeaderboardFreezeTime(t *testing.T) {
+	replaceNowFunc(t)
+	freeze := storage.Duration(time.Hour)
+	testCases := []struct {
+		name     string
+		quest    storage.Quest
+		expected *time.Time
+	}{
+		{
+			name:  "no freeze",
+			quest: storage.Quest{FinishTime: ptr.Time(wantNow.Add(time.Minute))},
+		},
+		{
+			name:  "no finish time",
+			quest: storage.Quest{LeaderboardFreeze: &freeze},
+		},
+		{
+			name:  "before freeze",
+			quest: storage.Quest{LeaderboardFreeze: &freeze, FinishTime: ptr.Time(wantNow.Add(2 * time.Hour))},
+		},
+		{
+			name:     "frozen",
+			quest:    storage.Quest{LeaderboardFreeze: &freeze, FinishTime: ptr.Time(wantNow.Add(time.Minute))},
+			expected: ptr.Time(wantNow.Add(time.Minute - time.Hour)),
+		},
+		{
+			name:     "frozen after finish",
+			quest:    storage.Quest{LeaderboardFreeze: &freeze, FinishTime: ptr.Time(wantNow.Add(-time.Minute)), Status: storage.StatusFinished},
+			expected: ptr.Time(wantNow.Add(-time.Minute - time.Hour)),
+		},
+		{
+			name: "revealed",
+			quest: storage.Quest{
+				LeaderboardFreeze:   &freeze,
+				FinishTime:          ptr.Time(wantNow.Add(-time.Minute)),
+				LeaderboardRevealed: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, LeaderboardFreezeTime(&tc.quest))
+		})
+	}
+}
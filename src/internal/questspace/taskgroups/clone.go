@@ -0,0 +1,153 @@
+package taskgroups
+
+import (
+	"context"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/storage"
+)
+
+// Clone copies task groups with their tasks and hints into quest. Publication times are moved by shift.
+// Transitions and prerequisites refer to groups and tasks by id, so they are set after all copies are created.
+func (s *Service) Clone(ctx context.Context, questID storage.ID, taskGroups []storage.TaskGroup, shift time.Duration) ([]storage.TaskGroup, error) {
+	createRequest := storage.TaskGroupsBulkUpdateRequest{QuestID: questID}
+	for i, tg := range taskGroups {
+		createReq := storage.CreateTaskGroupRequest{
+			QuestID:      questID,
+			OrderIdx:     i,
+			Name:         tg.Name,
+			Description:  tg.Description,
+			PubTime:      shiftTime(tg.PubTime, shift),
+			Sticky:       tg.Sticky,
+			HasTimeLimit: tg.HasTimeLimit,
+			TimeLimit:    tg.TimeLimit,
+			AllowSkip:    tg.AllowSkip,
+			SkipPenalty:  tg.SkipPenalty,
+			Location:     tg.Location,
+		}
+		for j, t := range tg.Tasks {
+			createReq.Tasks = append(createReq.Tasks, cloneTask(&t, j, shift))
+		}
+		createRequest.Create = append(createRequest.Create, createReq)
+	}
+	created, err := s.updater.BulkUpdateTaskGroups(ctx, &createRequest)
+	if err != nil {
+		return nil, xerrors.Errorf("create task groups: %w", err)
+	}
+	if len(created) != len(taskGroups) {
+		return nil, xerrors.Errorf("expected %d task groups in quest %q, got %d", len(taskGroups), questID, len(created))
+	}
+
+	ids := make(map[storage.ID]storage.ID)
+	for i, tg := range taskGroups {
+		ids[tg.ID] = created[i].ID
+		for j, t := range tg.Tasks {
+			ids[t.ID] = created[i].Tasks[j].ID
+		}
+	}
+
+	linkRequest := storage.TaskGroupsBulkUpdateRequest{QuestID: questID}
+	for i, tg := range taskGroups {
+		var tasksUpdate []storage.UpdateTaskRequest
+		for j, t := range tg.Tasks {
+			if t.Requires.Empty() {
+				continue
+			}
+			tasksUpdate = append(tasksUpdate, storage.UpdateTaskRequest{
+				ID:       created[i].Tasks[j].ID,
+				OrderIdx: j,
+				Requires: clonePrerequisites(t.Requires, ids),
+			})
+		}
+		if len(tg.Transitions) == 0 && tg.Requires.Empty() && len(tasksUpdate) == 0 {
+			continue
+		}
+
+		updateReq := storage.UpdateTaskGroupRequest{
+			ID:       created[i].ID,
+			OrderIdx: i,
+		}
+		if !tg.Requires.Empty() {
+			updateReq.Requires = clonePrerequisites(tg.Requires, ids)
+		}
+		if len(tg.Transitions) > 0 {
+			transitions := make([]storage.Transition, 0, len(tg.Transitions))
+			for _, t := range tg.Transitions {
+				transitions = append(transitions, storage.Transition{
+					NextGroupID: ids[t.NextGroupID],
+					TaskID:      ids[t.TaskID],
+					Answers:     t.Answers,
+				})
+			}
+			updateReq.Transitions = &transitions
+		}
+		if len(tasksUpdate) > 0 {
+			updateReq.Tasks = &storage.TasksBulkUpdateRequest{Update: tasksUpdate}
+		}
+		linkRequest.Update = append(linkRequest.Update, updateReq)
+	}
+	if len(linkRequest.Update) == 0 {
+		return created, nil
+	}
+
+	linked, err := s.updater.BulkUpdateTaskGroups(ctx, &linkRequest)
+	if err != nil {
+		return nil, xerrors.Errorf("link task groups: %w", err)
+	}
+	return linked, nil
+}
+
+func cloneTask(t *storage.Task, orderIdx int, shift time.Duration) storage.CreateTaskRequest {
+	hints := make([]storage.CreateHintRequest, 0, len(t.FullHints))
+	for _, h := range t.FullHints {
+		hints = append(hints, storage.CreateHintRequest{
+			Name:        h.Name,
+			Text:        h.Text,
+			Penalty:     h.Penalty,
+			OpensAfter:  h.OpensAfter,
+			AutoPenalty: h.AutoPenalty,
+		})
+	}
+	return storage.CreateTaskRequest{
+		OrderIdx:       orderIdx,
+		Name:           t.Name,
+		Question:       t.Question,
+		Reward:         t.Reward,
+		CorrectAnswers: t.CorrectAnswers,
+		Matcher:        t.Matcher,
+		AnswerLimits:   t.AnswerLimits,
+		Scoring:        t.Scoring,
+		Type:           t.Type,
+		Choice:         t.Choice,
+		Location:       t.Location,
+		Parts:          t.Parts,
+		OrderedHints:   t.OrderedHints,
+		Verification:   t.VerificationNew,
+		FullHints:      hints,
+		PubTime:        shiftTime(t.PubTime, shift),
+		MediaLinks:     t.MediaLinks,
+		MediaLink:      t.MediaLink,
+	}
+}
+
+// clonePrerequisites replaces ids of original groups and tasks with ids of their copies
+func clonePrerequisites(p *storage.Prerequisites, ids map[storage.ID]storage.ID) *storage.Prerequisites {
+	res := &storage.Prerequisites{}
+	for _, taskID := range p.SolvedTasks {
+		res.SolvedTasks = append(res.SolvedTasks, ids[taskID])
+	}
+	for _, solved := range p.SolvedInGroups {
+		res.SolvedInGroups = append(res.SolvedInGroups, storage.SolvedInGroup{TaskGroupID: ids[solved.TaskGroupID], Count: solved.Count})
+	}
+	return res
+}
+
+func shiftTime(t *time.Time, shift time.Duration) *time.Time {
+	if t == nil {
+		return nil
+	}
+	shifted := t.Add(shift)
+	return &shifted
+}
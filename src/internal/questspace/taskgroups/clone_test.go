@@ -0,0 +1,152 @@
+package taskgroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/internal/questspace/taskgroups/requests"
+	"questspace/pkg/storage"
+	storagemock "questspace/pkg/storage/mocks"
+)
+
+// fakeTaskGroups keeps task groups and tasks created through mock storage
+type fakeTaskGroups struct {
+	groups []storage.TaskGroup
+	tasks  map[storage.ID][]storage.Task
+}
+
+func (f *fakeTaskGroups) expect(s *storagemock.MockQuestSpaceStorage) {
+	f.tasks = make(map[storage.ID][]storage.Task)
+	s.EXPECT().GetTaskGroups(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.GetTaskGroupsRequest) ([]storage.TaskGroup, error) {
+		res := make([]storage.TaskGroup, 0, len(f.groups))
+		for _, tg := range f.groups {
+			if req.IncludeTasks {
+				tg.Tasks = f.tasks[tg.ID]
+			}
+			res = append(res, tg)
+		}
+		return res, nil
+	}).AnyTimes()
+	s.EXPECT().CreateTaskGroup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.CreateTaskGroupRequest) (*storage.TaskGroup, error) {
+		tg := storage.TaskGroup{
+			ID:       storage.ID("new-" + req.Name),
+			OrderIdx: req.OrderIdx,
+			Quest:    &storage.Quest{ID: req.QuestID},
+			Name:     req.Name,
+			PubTime:  req.PubTime,
+		}
+		f.groups = append(f.groups, tg)
+		return &tg, nil
+	}).AnyTimes()
+	s.EXPECT().UpdateTaskGroup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.UpdateTaskGroupRequest) (*storage.TaskGroup, error) {
+		for i, tg := range f.groups {
+			if tg.ID != req.ID {
+				continue
+			}
+			if req.Transitions != nil {
+				f.groups[i].Transitions = *req.Transitions
+			}
+			if req.Requires != nil {
+				f.groups[i].Requires = req.Requires
+			}
+			tg = f.groups[i]
+			return &tg, nil
+		}
+		return nil, storage.ErrNotFound
+	}).AnyTimes()
+	s.EXPECT().GetTasks(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.GetTasksRequest) (storage.GetTasksResponse, error) {
+		res := make(storage.GetTasksResponse)
+		for _, id := range req.GroupIDs {
+			res[id] = f.tasks[id]
+		}
+		return res, nil
+	}).AnyTimes()
+	s.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.CreateTaskRequest) (*storage.Task, error) {
+		task := storage.Task{
+			ID:             storage.ID("new-" + req.Name),
+			OrderIdx:       req.OrderIdx,
+			Name:           req.Name,
+			Reward:         req.Reward,
+			CorrectAnswers: req.CorrectAnswers,
+			PubTime:        req.PubTime,
+		}
+		for i, h := range req.FullHints {
+			task.FullHints = append(task.FullHints, storage.Hint{Index: i, Text: h.Text, Penalty: h.Penalty})
+		}
+		f.tasks[req.GroupID] = append(f.tasks[req.GroupID], task)
+		return &task, nil
+	}).AnyTimes()
+	s.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.UpdateTaskRequest) (*storage.Task, error) {
+		for groupID, tasks := range f.tasks {
+			for i, task := range tasks {
+				if task.ID != req.ID {
+					continue
+				}
+				if req.Requires != nil {
+					f.tasks[groupID][i].Requires = req.Requires
+				}
+				task = f.tasks[groupID][i]
+				return &task, nil
+			}
+		}
+		return nil, storage.ErrNotFound
+	}).AnyTimes()
+}
+
+func TestService_Clone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := storagemock.NewMockQuestSpaceStorage(ctrl)
+	var fake fakeTaskGroups
+	fake.expect(s)
+	serv := NewService(s, s, requests.NopValidator{}, storage.NewScorePenalty(0))
+
+	pubTime := time.Date(2024, time.April, 14, 12, 0, 0, 0, time.UTC)
+	original := []storage.TaskGroup{
+		{
+			ID:          "A",
+			Name:        "A",
+			PubTime:     ptr.Time(pubTime),
+			Transitions: []storage.Transition{{NextGroupID: "B", TaskID: "a", Answers: []string{"left"}}},
+			Tasks: []storage.Task{{
+				ID:             "a",
+				Name:           "a",
+				Reward:         10,
+				CorrectAnswers: []string{"left", "right"},
+				FullHints:      []storage.Hint{{Text: "look around", Penalty: storage.NewScorePenalty(3)}},
+			}},
+		},
+		{
+			ID:       "B",
+			OrderIdx: 1,
+			Name:     "B",
+			Requires: &storage.Prerequisites{SolvedInGroups: []storage.SolvedInGroup{{TaskGroupID: "A", Count: 1}}},
+			Tasks: []storage.Task{{
+				ID:             "b",
+				Name:           "b",
+				Reward:         10,
+				CorrectAnswers: []string{"up"},
+				Requires:       &storage.Prerequisites{SolvedTasks: []storage.ID{"a"}},
+			}},
+		},
+	}
+
+	cloned, err := serv.Clone(context.Background(), "new", original, 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, cloned, 2)
+
+	assert.Equal(t, storage.ID("new-A"), cloned[0].ID)
+	assert.Equal(t, pubTime.Add(24*time.Hour), *cloned[0].PubTime)
+	assert.Equal(t, []storage.Transition{{NextGroupID: "new-B", TaskID: "new-a", Answers: []string{"left"}}}, cloned[0].Transitions)
+	require.Len(t, cloned[0].Tasks, 1)
+	assert.Equal(t, []storage.Hint{{Text: "look around", Penalty: storage.NewScorePenalty(3)}}, cloned[0].Tasks[0].FullHints)
+
+	assert.Equal(t, &storage.Prerequisites{SolvedInGroups: []storage.SolvedInGroup{{TaskGroupID: "new-A", Count: 1}}}, cloned[1].Requires)
+	require.Len(t, cloned[1].Tasks, 1)
+	assert.Equal(t, &storage.Prerequisites{SolvedTasks: []storage.ID{"new-a"}}, cloned[1].Tasks[0].Requires)
+}
@@ -18,8 +18,8 @@ type Service struct {
 	updater *Updater
 }
 
-func NewService(tg storage.TaskGroupStorage, ts storage.TaskStorage, v requests.ImageValidator) *Service {
-	upd := NewUpdater(tg, tasks.NewUpdater(ts), v)
+func NewService(tg storage.TaskGroupStorage, ts storage.TaskStorage, v requests.ImageValidator, hintPenalty storage.PenaltyOneOf) *Service {
+	upd := NewUpdater(tg, tasks.NewUpdater(ts, hintPenalty), v)
 	return &Service{
 		tg:      tg,
 		updater: upd,
@@ -71,8 +71,12 @@ func (s *Service) Create(ctx context.Context, req *requests.CreateFullRequest) (
 				MediaLinks:     t.MediaLinks,
 				Reward:         t.Reward,
 				CorrectAnswers: t.CorrectAnswers,
+				Matcher:        t.Matcher,
+				AnswerLimits:   t.AnswerLimits,
+				Scoring:        t.Scoring,
 				Hints:          t.Hints,
 				FullHints:      t.FullHints,
+				OrderedHints:   t.OrderedHints,
 				Verification:   t.Verification,
 			})
 		}
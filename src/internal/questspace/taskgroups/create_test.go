@@ -15,7 +15,7 @@ import (
 func TestService_Create_Basic(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	s := storagemock.NewMockQuestSpaceStorage(ctrl)
-	serv := NewService(s, s, requests.NopValidator{})
+	serv := NewService(s, s, requests.NopValidator{}, storage.NewScorePenalty(0))
 	ctx := context.Background()
 
 	req := requests.CreateFullRequest{
@@ -83,3 +83,41 @@ func TestService_Create_Basic(t *testing.T) {
 	_, err := serv.Create(ctx, &req)
 	require.NoError(t, err)
 }
+
+func TestService_Create_HintPenalty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := storagemock.NewMockQuestSpaceStorage(ctrl)
+	serv := NewService(s, s, requests.NopValidator{}, storage.NewScorePenalty(5))
+	ctx := context.Background()
+
+	ownPenalty, err := storage.NewPercentagePenalty(50)
+	require.NoError(t, err)
+	req := requests.CreateFullRequest{
+		QuestID: "qid",
+		TaskGroups: []requests.CreateRequest{
+			{
+				Name: "1",
+				Tasks: []requests.CreateTaskRequest{
+					{Name: "plain", Question: "1", Reward: 10, Hints: []string{"first", "second"}},
+					{Name: "full", Question: "2", Reward: 10, FullHints: []storage.CreateHintRequest{{Text: "own", Penalty: ownPenalty}, {Text: "inherited"}}},
+				},
+			},
+		},
+	}
+
+	s.EXPECT().GetTaskGroups(ctx, gomock.Any()).Return(nil, nil).Times(3)
+	s.EXPECT().CreateTaskGroup(ctx, gomock.Any()).Return(&storage.TaskGroup{ID: "tg1", Quest: &storage.Quest{ID: req.QuestID}}, nil)
+	s.EXPECT().GetTasks(ctx, gomock.Any()).Return(nil, nil).Times(2)
+	var created [][]storage.CreateHintRequest
+	s.EXPECT().CreateTask(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req *storage.CreateTaskRequest) (*storage.Task, error) {
+		created = append(created, req.FullHints)
+		return &storage.Task{ID: storage.ID(req.Name), OrderIdx: req.OrderIdx, Reward: req.Reward}, nil
+	}).Times(2)
+
+	_, err = serv.Create(ctx, &req)
+	require.NoError(t, err)
+	require.Equal(t, [][]storage.CreateHintRequest{
+		{{Text: "first", Penalty: storage.NewScorePenalty(5)}, {Text: "second", Penalty: storage.NewScorePenalty(5)}},
+		{{Text: "own", Penalty: ownPenalty}, {Text: "inherited", Penalty: storage.NewScorePenalty(5)}},
+	}, created)
+}
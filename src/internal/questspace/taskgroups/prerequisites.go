@@ -0,0 +1,83 @@
+package taskgroups
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/permutations"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// validatePrerequisites checks that tasks and task groups are unlocked by existing tasks and groups of the quest
+// and that no item waits for itself. Task is locked while its group is locked, so it depends on the group prerequisites too.
+func validatePrerequisites(taskGroups []storage.TaskGroup) error {
+	// vertices are task groups followed by tasks
+	indexByID := make(map[storage.ID]int)
+	var names []string
+	var taskCounts []int
+	for i, tg := range taskGroups {
+		indexByID[tg.ID] = i
+		names = append(names, tg.Name)
+		taskCounts = append(taskCounts, len(tg.Tasks))
+	}
+	for _, tg := range taskGroups {
+		for _, task := range tg.Tasks {
+			indexByID[task.ID] = len(names)
+			names = append(names, task.Name)
+		}
+	}
+
+	var errs []error
+	var edges []permutations.OrderChange
+	addEdges := func(from int, item string, p *storage.Prerequisites) {
+		if p == nil {
+			return
+		}
+		for _, taskID := range p.SolvedTasks {
+			idx, ok := indexByID[taskID]
+			if !ok || idx < len(taskGroups) {
+				errs = append(errs, xerrors.Errorf("%s requires unknown task %q", item, taskID))
+				continue
+			}
+			edges = append(edges, permutations.OrderChange{Prev: from, Next: idx})
+		}
+		for _, solved := range p.SolvedInGroups {
+			idx, ok := indexByID[solved.TaskGroupID]
+			if !ok || idx >= len(taskGroups) {
+				errs = append(errs, xerrors.Errorf("%s requires unknown task group %q", item, solved.TaskGroupID))
+				continue
+			}
+			if solved.Count <= 0 || solved.Count > taskCounts[idx] {
+				errs = append(errs, xerrors.Errorf("%s requires %d solved tasks in task group %q of %d tasks", item, solved.Count, names[idx], taskCounts[idx]))
+				continue
+			}
+			edges = append(edges, permutations.OrderChange{Prev: from, Next: idx})
+		}
+	}
+	for i, tg := range taskGroups {
+		addEdges(i, fmt.Sprintf("task group %q", tg.Name), tg.Requires)
+		for _, task := range tg.Tasks {
+			idx := indexByID[task.ID]
+			edges = append(edges, permutations.OrderChange{Prev: idx, Next: i})
+			addEdges(idx, fmt.Sprintf("task %q", task.Name), task.Requires)
+		}
+	}
+	if len(errs) > 0 {
+		return httperrors.WrapWithCode(http.StatusBadRequest, errors.Join(errs...))
+	}
+
+	if cycle := permutations.FindCycle(edges, len(names)); cycle != nil {
+		cycleNames := make([]string, 0, len(cycle)+1)
+		for _, idx := range cycle {
+			cycleNames = append(cycleNames, names[idx])
+		}
+		cycleNames = append(cycleNames, names[cycle[0]])
+		return httperrors.Errorf(http.StatusBadRequest, "prerequisites form a cycle: %s", strings.Join(cycleNames, " -> "))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package taskgroups
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+func TestValidatePrerequisites(t *testing.T) {
+	testCases := []struct {
+		name        string
+		taskGroups  []storage.TaskGroup
+		expectedErr string
+	}{
+		{
+			name: "chain of unlocks",
+			taskGroups: []storage.TaskGroup{
+				{
+					ID:   "1",
+					Name: "first",
+					Tasks: []storage.Task{
+						{ID: "a", Name: "a"},
+						{ID: "b", Name: "b", Requires: &storage.Prerequisites{
+							SolvedTasks:    []storage.ID{"a"},
+							SolvedInGroups: []storage.SolvedInGroup{{TaskGroupID: "1", Count: 1}},
+						}},
+					},
+				},
+				{
+					ID:       "2",
+					Name:     "second",
+					Requires: &storage.Prerequisites{SolvedInGroups: []storage.SolvedInGroup{{TaskGroupID: "1", Count: 2}}},
+					Tasks:    []storage.Task{{ID: "c", Name: "c"}},
+				},
+			},
+		},
+		{
+			name: "group requires its own task",
+			taskGroups: []storage.TaskGroup{
+				{
+					ID:       "1",
+					Name:     "first",
+					Requires: &storage.Prerequisites{SolvedTasks: []storage.ID{"a"}},
+					Tasks:    []storage.Task{{ID: "a", Name: "a"}},
+				},
+			},
+			expectedErr: `prerequisites form a cycle: first -> a -> first`,
+		},
+		{
+			name: "tasks require each other",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first", Tasks: []storage.Task{
+					{ID: "a", Name: "a", Requires: &storage.Prerequisites{SolvedTasks: []storage.ID{"b"}}},
+				}},
+				{ID: "2", Name: "second", Tasks: []storage.Task{
+					{ID: "b", Name: "b", Requires: &storage.Prerequisites{SolvedTasks: []storage.ID{"a"}}},
+				}},
+			},
+			expectedErr: `prerequisites form a cycle: a -> b -> a`,
+		},
+		{
+			name: "unknown task",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first", Requires: &storage.Prerequisites{SolvedTasks: []storage.ID{"1"}}},
+			},
+			expectedErr: `task group "first" requires unknown task "1"`,
+		},
+		{
+			name: "too many solved tasks",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first", Tasks: []storage.Task{{ID: "a", Name: "a"}}},
+				{ID: "2", Name: "second", Tasks: []storage.Task{
+					{ID: "b", Name: "b", Requires: &storage.Prerequisites{SolvedInGroups: []storage.SolvedInGroup{{TaskGroupID: "1", Count: 2}}}},
+				}},
+			},
+			expectedErr: `task "b" requires 2 solved tasks in task group "first" of 1 tasks`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validatePrerequisites(tc.taskGroups)
+			if len(tc.expectedErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var httpErr *httperrors.HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+}
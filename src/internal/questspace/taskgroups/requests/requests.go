@@ -22,9 +22,13 @@ type CreateTaskRequest struct {
 	Question       string                      `json:"question"`
 	Reward         int                         `json:"reward"`
 	CorrectAnswers []string                    `json:"correct_answers"`
+	Matcher        *storage.AnswerMatcher      `json:"matcher,omitempty"`
+	AnswerLimits   *storage.AnswerLimits       `json:"answer_limits,omitempty"`
+	Scoring        *storage.Scoring            `json:"scoring,omitempty"`
 	Verification   storage.VerificationType    `json:"verification" enums:"auto,manual"`
 	Hints          []string                    `json:"hints" maxLength:"3"`
 	FullHints      []storage.CreateHintRequest `json:"hints_full" maxLength:"3"`
+	OrderedHints   bool                        `json:"ordered_hints,omitempty"`
 	PubTime        *time.Time                  `json:"pub_time,omitempty"`
 	MediaLinks     []string                    `json:"media_links,omitempty"`
 	// Deprecated
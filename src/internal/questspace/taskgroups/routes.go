@@ -0,0 +1,153 @@
+package taskgroups
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/internal/questspace/permutations"
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+// validateRoutes checks that transitions between task groups lead to non-sticky groups of the quest
+// and that teams cannot walk the same group twice. Groups without default transition lead to the next group by order,
+// so these edges are also taken into account when searching for cycles.
+func validateRoutes(taskGroups []storage.TaskGroup) error {
+	indexByID := make(map[storage.ID]int, len(taskGroups))
+	for i, tg := range taskGroups {
+		indexByID[tg.ID] = i
+	}
+
+	var errs []error
+	var edges []permutations.OrderChange
+	for i, tg := range taskGroups {
+		if tg.Sticky {
+			if len(tg.Transitions) > 0 {
+				errs = append(errs, xerrors.Errorf("sticky task group %q cannot have transitions", tg.Name))
+			}
+			continue
+		}
+		taskIDs := make(map[storage.ID]struct{}, len(tg.Tasks))
+		for _, task := range tg.Tasks {
+			taskIDs[task.ID] = struct{}{}
+		}
+
+		var hasDefault bool
+		for _, t := range tg.Transitions {
+			next, ok := indexByID[t.NextGroupID]
+			if !ok {
+				errs = append(errs, xerrors.Errorf("task group %q has transition to unknown task group %q", tg.Name, t.NextGroupID))
+				continue
+			}
+			if taskGroups[next].Sticky {
+				errs = append(errs, xerrors.Errorf("task group %q has transition to sticky task group %q", tg.Name, taskGroups[next].Name))
+				continue
+			}
+			if len(t.TaskID) == 0 {
+				if hasDefault {
+					errs = append(errs, xerrors.Errorf("task group %q has more than one default transition", tg.Name))
+					continue
+				}
+				hasDefault = true
+			} else if _, ok = taskIDs[t.TaskID]; !ok {
+				errs = append(errs, xerrors.Errorf("task group %q has transition by task %q of another group", tg.Name, t.TaskID))
+				continue
+			}
+			edges = append(edges, permutations.OrderChange{Prev: i, Next: next})
+		}
+		if hasDefault {
+			continue
+		}
+		for j := i + 1; j < len(taskGroups); j++ {
+			if !taskGroups[j].Sticky {
+				edges = append(edges, permutations.OrderChange{Prev: i, Next: j})
+				break
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return httperrors.WrapWithCode(http.StatusBadRequest, errors.Join(errs...))
+	}
+
+	if cycle := permutations.FindCycle(edges, len(taskGroups)); cycle != nil {
+		names := make([]string, 0, len(cycle)+1)
+		for _, idx := range cycle {
+			names = append(names, taskGroups[idx].Name)
+		}
+		names = append(names, taskGroups[cycle[0]].Name)
+		return httperrors.Errorf(http.StatusBadRequest, "task group transitions form a cycle: %s", strings.Join(names, " -> "))
+	}
+	return nil
+}
+
+// routeLinks keeps transitions, which reference groups and tasks created by the same bulk request by their keys.
+// These transitions are saved after all groups and tasks are created, since new ones get ids only on creation.
+type routeLinks struct {
+	keys    map[string]storage.ID
+	pending map[storage.ID][]storage.Transition
+}
+
+func newRouteLinks(req *storage.TaskGroupsBulkUpdateRequest) (*routeLinks, error) {
+	links := &routeLinks{
+		keys:    make(map[string]storage.ID),
+		pending: make(map[storage.ID][]storage.Transition),
+	}
+	var errs []error
+	addKey := func(key string) {
+		if len(key) == 0 {
+			return
+		}
+		if _, ok := links.keys[key]; ok {
+			errs = append(errs, xerrors.Errorf("key %q is used more than once", key))
+		}
+		links.keys[key] = ""
+	}
+	for _, createReq := range req.Create {
+		addKey(createReq.Key)
+		for _, t := range createReq.Tasks {
+			addKey(t.Key)
+		}
+	}
+	for _, updateReq := range req.Update {
+		if updateReq.Tasks == nil {
+			continue
+		}
+		for _, t := range updateReq.Tasks.Create {
+			addKey(t.Key)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, httperrors.WrapWithCode(http.StatusBadRequest, errors.Join(errs...))
+	}
+	return links, nil
+}
+
+// references reports whether any of transitions leads to new group or is taken by new task
+func (l *routeLinks) references(transitions []storage.Transition) bool {
+	for _, t := range transitions {
+		_, toKey := l.keys[string(t.NextGroupID)]
+		_, byKey := l.keys[string(t.TaskID)]
+		if toKey || byKey {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve replaces keys of new groups and tasks in transitions with their ids
+func (l *routeLinks) resolve(transitions []storage.Transition) []storage.Transition {
+	resolved := make([]storage.Transition, 0, len(transitions))
+	for _, t := range transitions {
+		if id, ok := l.keys[string(t.NextGroupID)]; ok {
+			t.NextGroupID = id
+		}
+		if id, ok := l.keys[string(t.TaskID)]; ok {
+			t.TaskID = id
+		}
+		resolved = append(resolved, t)
+	}
+	return resolved
+}
@@ -0,0 +1,102 @@
+package taskgroups
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/httperrors"
+	"questspace/pkg/storage"
+)
+
+func TestValidateRoutes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		taskGroups  []storage.TaskGroup
+		expectedErr string
+	}{
+		{
+			name: "no transitions",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first"},
+				{ID: "2", Name: "second"},
+			},
+		},
+		{
+			name: "branches join",
+			taskGroups: []storage.TaskGroup{
+				{
+					ID:    "1",
+					Name:  "fork",
+					Tasks: []storage.Task{{ID: "left"}, {ID: "right"}},
+					Transitions: []storage.Transition{
+						{NextGroupID: "2", TaskID: "left"},
+						{NextGroupID: "3", TaskID: "right"},
+					},
+				},
+				{ID: "2", Name: "left", Transitions: []storage.Transition{{NextGroupID: "4"}}},
+				{ID: "3", Name: "right"},
+				{ID: "4", Name: "join"},
+			},
+		},
+		{
+			name: "transition back to start",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first"},
+				{ID: "2", Name: "sticky", Sticky: true},
+				{ID: "3", Name: "second", Transitions: []storage.Transition{{NextGroupID: "1"}}},
+			},
+			expectedErr: "task group transitions form a cycle: first -> second -> first",
+		},
+		{
+			name: "cycle through next group by order",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first", Transitions: []storage.Transition{{NextGroupID: "3"}}},
+				{ID: "2", Name: "second"},
+				{ID: "3", Name: "third", Transitions: []storage.Transition{{NextGroupID: "2"}}},
+			},
+			expectedErr: "task group transitions form a cycle: third -> second -> third",
+		},
+		{
+			name: "transition to sticky group",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first", Transitions: []storage.Transition{{NextGroupID: "2"}}},
+				{ID: "2", Name: "sticky", Sticky: true},
+			},
+			expectedErr: `task group "first" has transition to sticky task group "sticky"`,
+		},
+		{
+			name: "task of another group",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first", Transitions: []storage.Transition{{NextGroupID: "2", TaskID: "task"}}},
+				{ID: "2", Name: "second", Tasks: []storage.Task{{ID: "task"}}},
+			},
+			expectedErr: `task group "first" has transition by task "task" of another group`,
+		},
+		{
+			name: "two default transitions",
+			taskGroups: []storage.TaskGroup{
+				{ID: "1", Name: "first", Transitions: []storage.Transition{{NextGroupID: "2"}, {NextGroupID: "3"}}},
+				{ID: "2", Name: "second"},
+				{ID: "3", Name: "third"},
+			},
+			expectedErr: `task group "first" has more than one default transition`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRoutes(tc.taskGroups)
+			if len(tc.expectedErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var httpErr *httperrors.HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"questspace/internal/questspace/permutations"
 	"questspace/internal/questspace/taskgroups/requests"
 	"questspace/internal/questspace/tasks"
+	"questspace/internal/validate"
 	"questspace/pkg/httperrors"
 	"questspace/pkg/storage"
 )
@@ -151,13 +152,20 @@ func (u *Updater) reorderUpdatedTaskGroups(taskGroups *taskGroupsPacked, updateR
 	return nil
 }
 
-func (u *Updater) updateTaskGroups(ctx context.Context, taskGroups *taskGroupsPacked, updateReqs []storage.UpdateTaskGroupRequest, questID storage.ID) error {
+func (u *Updater) updateTaskGroups(ctx context.Context, taskGroups *taskGroupsPacked, updateReqs []storage.UpdateTaskGroupRequest, questID storage.ID, links *routeLinks) error {
 	var errs []error
 	for _, updateReq := range updateReqs {
 		updateReq := updateReq
 		updateReq.QuestID = questID
+		if updateReq.Transitions != nil && links.references(*updateReq.Transitions) {
+			links.pending[updateReq.ID] = *updateReq.Transitions
+			updateReq.Transitions = nil
+		}
 		taskGroup, err := u.s.UpdateTaskGroup(ctx, &updateReq)
 		if err != nil {
+			if errors.Is(err, storage.ErrValidation) {
+				err = httperrors.WrapWithCode(http.StatusBadRequest, err)
+			}
 			errs = append(errs, xerrors.Errorf("update task group %s: %w", updateReq.ID, err))
 			continue
 		}
@@ -166,7 +174,7 @@ func (u *Updater) updateTaskGroups(ctx context.Context, taskGroups *taskGroupsPa
 		if updateReq.Tasks != nil {
 			updateReq.Tasks.GroupID = updateReq.ID
 			updateReq.Tasks.QuestID = questID
-			taskGroup.Tasks, err = u.taskUpdater.BulkUpdate(ctx, updateReq.Tasks)
+			taskGroup.Tasks, err = u.taskUpdater.BulkUpdate(ctx, updateReq.Tasks, links.keys)
 			if err != nil {
 				errs = append(errs, xerrors.Errorf("update tasks for group %q: %w", updateReq.ID, err))
 			}
@@ -178,7 +186,7 @@ func (u *Updater) updateTaskGroups(ctx context.Context, taskGroups *taskGroupsPa
 	return nil
 }
 
-func (u *Updater) createTaskGroups(ctx context.Context, taskGroups *taskGroupsPacked, createReqs []storage.CreateTaskGroupRequest, questID storage.ID) error {
+func (u *Updater) createTaskGroups(ctx context.Context, taskGroups *taskGroupsPacked, createReqs []storage.CreateTaskGroupRequest, questID storage.ID, links *routeLinks) error {
 	var errs []error
 	for _, createReq := range createReqs {
 		if taskGroups.ordered[createReq.OrderIdx] != nil {
@@ -193,6 +201,10 @@ func (u *Updater) createTaskGroups(ctx context.Context, taskGroups *taskGroupsPa
 	for _, createReq := range createReqs {
 		createReq := createReq
 		createReq.QuestID = questID
+		var pending []storage.Transition
+		if links.references(createReq.Transitions) {
+			pending, createReq.Transitions = createReq.Transitions, nil
+		}
 		taskGroup, err := u.s.CreateTaskGroup(ctx, &createReq)
 		if err != nil {
 			errs = append(errs, xerrors.Errorf("create task group: %w", err))
@@ -200,12 +212,18 @@ func (u *Updater) createTaskGroups(ctx context.Context, taskGroups *taskGroupsPa
 		}
 		taskGroups.byID[taskGroup.ID] = taskGroup
 		taskGroups.ordered[taskGroup.OrderIdx] = taskGroup
+		if len(createReq.Key) > 0 {
+			links.keys[createReq.Key] = taskGroup.ID
+		}
+		if pending != nil {
+			links.pending[taskGroup.ID] = pending
+		}
 		if createReq.Tasks != nil {
 			taskGroup.Tasks, err = u.taskUpdater.BulkUpdate(ctx, &storage.TasksBulkUpdateRequest{
 				QuestID: questID,
 				GroupID: taskGroup.ID,
 				Create:  createReq.Tasks,
-			})
+			}, links.keys)
 			if err != nil {
 				errs = append(errs, xerrors.Errorf("create tasks for group %q: %w", taskGroup.ID, err))
 			}
@@ -217,10 +235,38 @@ func (u *Updater) createTaskGroups(ctx context.Context, taskGroups *taskGroupsPa
 	return nil
 }
 
+// linkRoutes saves transitions, which referenced new groups and tasks, when ids of all of them are known
+func (u *Updater) linkRoutes(ctx context.Context, taskGroups *taskGroupsPacked, links *routeLinks, questID storage.ID) error {
+	var errs []error
+	for id, transitions := range links.pending {
+		resolved := links.resolve(transitions)
+		_, err := u.s.UpdateTaskGroup(ctx, &storage.UpdateTaskGroupRequest{
+			ID:          id,
+			QuestID:     questID,
+			OrderIdx:    taskGroups.byID[id].OrderIdx,
+			Transitions: &resolved,
+		})
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("link task group %s: %w", id, err))
+		}
+	}
+	if len(errs) > 0 {
+		return xerrors.Errorf("%d error(s) occured during task groups link: %w", len(errs), errors.Join(errs...))
+	}
+	return nil
+}
+
 func (u *Updater) BulkUpdateTaskGroups(ctx context.Context, req *storage.TaskGroupsBulkUpdateRequest) ([]storage.TaskGroup, error) {
 	if err := u.validateImageURLs(ctx, req); err != nil {
 		return nil, err
 	}
+	if err := validateGroupSettings(req); err != nil {
+		return nil, err
+	}
+	links, err := newRouteLinks(req)
+	if err != nil {
+		return nil, xerrors.Errorf("collect keys: %w", err)
+	}
 	taskGroups, err := u.getOldTaskGroups(ctx, req.QuestID)
 	if err != nil {
 		return nil, xerrors.Errorf("get old task groups: %w", err)
@@ -236,10 +282,13 @@ func (u *Updater) BulkUpdateTaskGroups(ctx context.Context, req *storage.TaskGro
 	if err := u.reorderUpdatedTaskGroups(taskGroups, req.Update); err != nil {
 		return nil, xerrors.Errorf("reorder updated task groups: %w", err)
 	}
-	if err := u.updateTaskGroups(ctx, taskGroups, req.Update, req.QuestID); err != nil {
+	if err := u.updateTaskGroups(ctx, taskGroups, req.Update, req.QuestID, links); err != nil {
 		return nil, xerrors.Errorf("%w", err)
 	}
-	if err := u.createTaskGroups(ctx, taskGroups, req.Create, req.QuestID); err != nil {
+	if err := u.createTaskGroups(ctx, taskGroups, req.Create, req.QuestID, links); err != nil {
+		return nil, xerrors.Errorf("%w", err)
+	}
+	if err := u.linkRoutes(ctx, taskGroups, links, req.QuestID); err != nil {
 		return nil, xerrors.Errorf("%w", err)
 	}
 	if err = u.clapPack(ctx, taskGroups); err != nil {
@@ -251,6 +300,12 @@ func (u *Updater) BulkUpdateTaskGroups(ctx context.Context, req *storage.TaskGro
 	if err != nil {
 		return nil, xerrors.Errorf("get all task groups: %w", err)
 	}
+	if err = validateRoutes(newTaskGroups); err != nil {
+		return nil, xerrors.Errorf("validate routes: %w", err)
+	}
+	if err = validatePrerequisites(newTaskGroups); err != nil {
+		return nil, xerrors.Errorf("validate prerequisites: %w", err)
+	}
 	return newTaskGroups, nil
 }
 
@@ -286,6 +341,30 @@ func (u *Updater) validateImageURLs(ctx context.Context, req *storage.TaskGroups
 	return nil
 }
 
+func validateGroupSettings(req *storage.TaskGroupsBulkUpdateRequest) error {
+	var errs []error
+	for _, createReq := range req.Create {
+		if err := validate.Penalty("skip penalty", createReq.SkipPenalty); err != nil {
+			errs = append(errs, xerrors.Errorf("task group %q: %w", createReq.Name, err))
+		}
+		if err := validate.Location(createReq.Location); err != nil {
+			errs = append(errs, xerrors.Errorf("task group %q: %w", createReq.Name, err))
+		}
+	}
+	for _, updateReq := range req.Update {
+		if err := validate.Penalty("skip penalty", updateReq.SkipPenalty); err != nil {
+			errs = append(errs, xerrors.Errorf("task group %q: %w", updateReq.ID, err))
+		}
+		if err := validate.Location(updateReq.Location); err != nil {
+			errs = append(errs, xerrors.Errorf("task group %q: %w", updateReq.ID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return httperrors.WrapWithCode(http.StatusBadRequest, errors.Join(errs...))
+	}
+	return nil
+}
+
 func (u *Updater) clapPack(ctx context.Context, pack *taskGroupsPacked) error {
 	var errs []error
 	var l, r int
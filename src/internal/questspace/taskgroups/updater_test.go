@@ -2,12 +2,14 @@ package taskgroups
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
 	"questspace/internal/questspace/taskgroups/requests"
+	"questspace/pkg/httperrors"
 	"questspace/pkg/storage"
 	storagemock "questspace/pkg/storage/mocks"
 )
@@ -371,3 +373,111 @@ func TestUpdater_BulkUpdateTaskGroups_DeleteReorderAndCreate(t *testing.T) {
 	_, err := updater.BulkUpdateTaskGroups(ctx, req)
 	require.NoError(t, err)
 }
+
+func TestUpdater_BulkUpdateTaskGroups_TransitionCycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := storagemock.NewMockTaskGroupStorage(ctrl)
+	updater := NewUpdater(s, nil, requests.NopValidator{})
+	ctx := context.Background()
+
+	const questID = "quest-id"
+	transitions := []storage.Transition{{NextGroupID: "1"}}
+	req := &storage.TaskGroupsBulkUpdateRequest{
+		QuestID: questID,
+		Update: []storage.UpdateTaskGroupRequest{
+			{ID: "3", OrderIdx: 2, Transitions: &transitions},
+		},
+	}
+	changedTaskGroup := storage.TaskGroup{ID: "3", Name: "third", OrderIdx: 2, Transitions: transitions}
+
+	updateIdIncluded := req.Update[0]
+	updateIdIncluded.QuestID = questID
+	gomock.InOrder(
+		s.EXPECT().GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID}).Return([]storage.TaskGroup{
+			taskGroupsForTest[0],
+			taskGroupsForTest[1],
+			taskGroupsForTest[2],
+		}, nil),
+		s.EXPECT().UpdateTaskGroup(ctx, &updateIdIncluded).Return(&changedTaskGroup, nil),
+		s.EXPECT().GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID, IncludeTasks: true}).Return([]storage.TaskGroup{
+			taskGroupsForTest[0],
+			taskGroupsForTest[1],
+			changedTaskGroup,
+		}, nil),
+	)
+
+	_, err := updater.BulkUpdateTaskGroups(ctx, req)
+	var httpErr *httperrors.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestUpdater_BulkUpdateTaskGroups_TransitionToNewGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := storagemock.NewMockTaskGroupStorage(ctrl)
+	updater := NewUpdater(s, nil, requests.NopValidator{})
+	ctx := context.Background()
+
+	const questID = "quest-id"
+	transitions := []storage.Transition{{NextGroupID: "new"}}
+	req := &storage.TaskGroupsBulkUpdateRequest{
+		QuestID: questID,
+		Update: []storage.UpdateTaskGroupRequest{
+			{ID: "1", OrderIdx: 0, Transitions: &transitions},
+		},
+		Create: []storage.CreateTaskGroupRequest{
+			{Name: "new group", OrderIdx: 3, Key: "new"},
+		},
+	}
+	createdTaskGroup := storage.TaskGroup{ID: "28", Name: "new group", OrderIdx: 3}
+	resolved := []storage.Transition{{NextGroupID: "28"}}
+	linkedTaskGroup := storage.TaskGroup{ID: "1", OrderIdx: 0, Transitions: resolved}
+
+	updateWithoutTransitions := req.Update[0]
+	updateWithoutTransitions.QuestID = questID
+	updateWithoutTransitions.Transitions = nil
+	createIDIncluded := req.Create[0]
+	createIDIncluded.QuestID = questID
+	gomock.InOrder(
+		s.EXPECT().GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID}).Return([]storage.TaskGroup{
+			taskGroupsForTest[0],
+			taskGroupsForTest[1],
+			taskGroupsForTest[2],
+		}, nil),
+		s.EXPECT().UpdateTaskGroup(ctx, &updateWithoutTransitions).Return(&taskGroupsForTest[0], nil),
+		s.EXPECT().CreateTaskGroup(ctx, &createIDIncluded).Return(&createdTaskGroup, nil),
+		s.EXPECT().UpdateTaskGroup(ctx, &storage.UpdateTaskGroupRequest{
+			ID:          "1",
+			QuestID:     questID,
+			OrderIdx:    0,
+			Transitions: &resolved,
+		}).Return(&linkedTaskGroup, nil),
+		s.EXPECT().GetTaskGroups(ctx, &storage.GetTaskGroupsRequest{QuestID: questID, IncludeTasks: true}).Return([]storage.TaskGroup{
+			linkedTaskGroup,
+			taskGroupsForTest[1],
+			taskGroupsForTest[2],
+			createdTaskGroup,
+		}, nil),
+	)
+
+	_, err := updater.BulkUpdateTaskGroups(ctx, req)
+	require.NoError(t, err)
+}
+
+func TestUpdater_BulkUpdateTaskGroups_DuplicateKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := storagemock.NewMockTaskGroupStorage(ctrl)
+	updater := NewUpdater(s, nil, requests.NopValidator{})
+
+	req := &storage.TaskGroupsBulkUpdateRequest{
+		QuestID: "quest-id",
+		Create: []storage.CreateTaskGroupRequest{
+			{Name: "first", OrderIdx: 0, Key: "group", Tasks: []storage.CreateTaskRequest{{Name: "task", Key: "group"}}},
+		},
+	}
+
+	_, err := updater.BulkUpdateTaskGroups(context.Background(), req)
+	var httpErr *httperrors.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
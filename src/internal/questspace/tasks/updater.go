@@ -9,20 +9,42 @@ import (
 	"github.com/yandex/perforator/library/go/core/xerrors"
 
 	"questspace/internal/questspace/permutations"
+	"questspace/internal/validate"
 	"questspace/pkg/httperrors"
 	"questspace/pkg/storage"
 )
 
 type Updater struct {
-	s storage.TaskStorage
+	s           storage.TaskStorage
+	hintPenalty storage.PenaltyOneOf
 }
 
-func NewUpdater(s storage.TaskStorage) *Updater {
+// NewUpdater returns updater of tasks, which sets hintPenalty to new hints created without penalty
+func NewUpdater(s storage.TaskStorage, hintPenalty storage.PenaltyOneOf) *Updater {
 	return &Updater{
-		s: s,
+		s:           s,
+		hintPenalty: hintPenalty,
 	}
 }
 
+// fullHints returns hints with penalties, so that plain text hints and hints without penalty inherit default penalty
+func (u *Updater) fullHints(hints []string, fullHints []storage.CreateHintRequest) []storage.CreateHintRequest {
+	if len(fullHints) == 0 && len(hints) > 0 {
+		fullHints = make([]storage.CreateHintRequest, 0, len(hints))
+		for _, hintText := range hints {
+			fullHints = append(fullHints, storage.CreateHintRequest{Text: hintText})
+		}
+	} else {
+		fullHints = slices.Clone(fullHints)
+	}
+	for i := range fullHints {
+		if fullHints[i].Penalty.Empty() {
+			fullHints[i].Penalty = u.hintPenalty
+		}
+	}
+	return fullHints
+}
+
 type tasksPacked struct {
 	byID  map[storage.ID]*storage.Task
 	order []*storage.Task
@@ -133,6 +155,12 @@ func (u *Updater) validatePenalties(score int, hints []storage.Hint) error {
 	totalPenalty := 0
 	for _, h := range hints {
 		totalPenalty += h.Penalty.GetPenaltyPoints(score)
+		if h.OpensAfter != nil && *h.OpensAfter <= 0 {
+			return httperrors.Errorf(http.StatusBadRequest, "hint #%d should open after positive delay", h.Index+1)
+		}
+		if h.AutoPenalty != nil && h.AutoPenalty.GetPenaltyPoints(score) > h.Penalty.GetPenaltyPoints(score) {
+			return httperrors.Errorf(http.StatusBadRequest, "auto penalty of hint #%d should not exceed its penalty", h.Index+1)
+		}
 	}
 	if totalPenalty > score {
 		return httperrors.Errorf(http.StatusBadRequest, "total hints penalty should not exceed maximum score: score %d vs penalty %d", score, totalPenalty)
@@ -140,18 +168,79 @@ func (u *Updater) validatePenalties(score int, hints []storage.Hint) error {
 	return nil
 }
 
+func (u *Updater) validateAnswerSettings(task *storage.Task) error {
+	if err := validate.Matcher(task.Matcher, task.CorrectAnswers); err != nil {
+		return xerrors.Errorf("bad answer matcher: %w", err)
+	}
+	if err := validate.AnswerLimits(task.AnswerLimits); err != nil {
+		return xerrors.Errorf("bad answer limits: %w", err)
+	}
+	if err := validate.Scoring(task.Scoring, task.Reward); err != nil {
+		return xerrors.Errorf("bad scoring: %w", err)
+	}
+	if err := validate.Choice(task.Type, task.Choice); err != nil {
+		return xerrors.Errorf("bad choice: %w", err)
+	}
+	if task.Verification == storage.VerificationManual && task.Scoring != nil && len(task.Scoring.FirstSolveBonuses) > 0 {
+		return httperrors.New(http.StatusBadRequest, "first solve bonuses are not supported for manually verified tasks")
+	}
+	if task.Type == storage.TaskTypeChoice && task.Verification == storage.VerificationManual {
+		return httperrors.New(http.StatusBadRequest, "choice tasks can be verified only automatically")
+	}
+	if task.Type == storage.TaskTypeFile && task.Verification != storage.VerificationManual {
+		return httperrors.New(http.StatusBadRequest, "file answers can be verified only manually")
+	}
+	if err := validate.Location(task.Location); err != nil {
+		return xerrors.Errorf("bad location: %w", err)
+	}
+	if task.Type == storage.TaskTypeLocation && task.Location == nil {
+		return httperrors.New(http.StatusBadRequest, "location task should have location")
+	}
+	if err := validate.Parts(task.Type, task.Parts); err != nil {
+		return xerrors.Errorf("bad parts: %w", err)
+	}
+	if task.Type == storage.TaskTypeParts {
+		if task.Verification == storage.VerificationManual {
+			return httperrors.New(http.StatusBadRequest, "multi-part tasks can be verified only automatically")
+		}
+		if task.Scoring != nil && len(task.Scoring.FirstSolveBonuses) > 0 {
+			return httperrors.New(http.StatusBadRequest, "first solve bonuses are not supported for multi-part tasks")
+		}
+		for i, part := range task.Parts {
+			if err := validate.Matcher(task.Matcher, part.CorrectAnswers); err != nil {
+				return xerrors.Errorf("bad answer matcher of part #%d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
 func (u *Updater) updateTasks(ctx context.Context, tasks *tasksPacked, updateReqs []storage.UpdateTaskRequest) error {
 	var errs []error
 	for _, updateReq := range updateReqs {
 		updateReq := updateReq
+		if updateReq.FullHints != nil || len(updateReq.Hints) > 0 {
+			var fullHints []storage.CreateHintRequest
+			if updateReq.FullHints != nil {
+				fullHints = *updateReq.FullHints
+			}
+			fullHints = u.fullHints(updateReq.Hints, fullHints)
+			updateReq.FullHints = &fullHints
+		}
 		task, err := u.s.UpdateTask(ctx, &updateReq)
 		if err != nil {
+			if errors.Is(err, storage.ErrValidation) {
+				err = httperrors.WrapWithCode(http.StatusBadRequest, err)
+			}
 			errs = append(errs, xerrors.Errorf("update task %q: %w", updateReq.ID, err))
 			continue
 		}
 		if err = u.validatePenalties(task.Reward, task.FullHints); err != nil {
 			errs = append(errs, xerrors.Errorf("task %q: %w", task.ID, err))
 		}
+		if err = u.validateAnswerSettings(task); err != nil {
+			errs = append(errs, xerrors.Errorf("task %q: %w", task.ID, err))
+		}
 		tasks.byID[task.ID] = task
 		tasks.order[task.OrderIdx] = task
 	}
@@ -161,7 +250,7 @@ func (u *Updater) updateTasks(ctx context.Context, tasks *tasksPacked, updateReq
 	return nil
 }
 
-func (u *Updater) createTasks(ctx context.Context, tasks *tasksPacked, createReqs []storage.CreateTaskRequest, groupID storage.ID) error {
+func (u *Updater) createTasks(ctx context.Context, tasks *tasksPacked, createReqs []storage.CreateTaskRequest, groupID storage.ID, keys map[string]storage.ID) error {
 	var errs []error
 	for _, createReq := range createReqs {
 		if tasks.order[createReq.OrderIdx] != nil {
@@ -176,6 +265,7 @@ func (u *Updater) createTasks(ctx context.Context, tasks *tasksPacked, createReq
 	for _, createReq := range createReqs {
 		createReq := createReq
 		createReq.GroupID = groupID
+		createReq.FullHints = u.fullHints(createReq.Hints, createReq.FullHints)
 		task, err := u.s.CreateTask(ctx, &createReq)
 		if err != nil {
 			errs = append(errs, xerrors.Errorf("create task group: %w", err))
@@ -184,8 +274,14 @@ func (u *Updater) createTasks(ctx context.Context, tasks *tasksPacked, createReq
 		if err = u.validatePenalties(task.Reward, task.FullHints); err != nil {
 			errs = append(errs, xerrors.Errorf("task %q: %w", task.ID, err))
 		}
+		if err = u.validateAnswerSettings(task); err != nil {
+			errs = append(errs, xerrors.Errorf("task %q: %w", task.ID, err))
+		}
 		tasks.byID[task.ID] = task
 		tasks.order[task.OrderIdx] = task
+		if len(createReq.Key) > 0 && keys != nil {
+			keys[createReq.Key] = task.ID
+		}
 	}
 	if len(errs) > 0 {
 		return xerrors.Errorf("%d error(s) occured during task groups create: %w", len(errs), errors.Join(errs...))
@@ -193,9 +289,9 @@ func (u *Updater) createTasks(ctx context.Context, tasks *tasksPacked, createReq
 	return nil
 }
 
-// BulkUpdate
+// BulkUpdate applies changes to tasks of the group. When keys is not nil, ids of created tasks are saved there by their keys.
 // TODO: unit-tests
-func (u *Updater) BulkUpdate(ctx context.Context, req *storage.TasksBulkUpdateRequest) ([]storage.Task, error) {
+func (u *Updater) BulkUpdate(ctx context.Context, req *storage.TasksBulkUpdateRequest, keys map[string]storage.ID) ([]storage.Task, error) {
 	oldTasks, err := u.s.GetTasks(ctx, &storage.GetTasksRequest{GroupIDs: []storage.ID{req.GroupID}})
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -219,7 +315,7 @@ func (u *Updater) BulkUpdate(ctx context.Context, req *storage.TasksBulkUpdateRe
 	if err := u.updateTasks(ctx, pack, req.Update); err != nil {
 		return nil, xerrors.Errorf("update tasks: %w", err)
 	}
-	if err := u.createTasks(ctx, pack, req.Create, req.GroupID); err != nil {
+	if err := u.createTasks(ctx, pack, req.Create, req.GroupID, keys); err != nil {
 		return nil, xerrors.Errorf("create tasks: %w", err)
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/yandex/perforator/library/go/core/xerrors"
 	"go.uber.org/zap"
 
+	"questspace/internal/questspace/events"
 	"questspace/pkg/httperrors"
 	"questspace/pkg/logging"
 	"questspace/pkg/storage"
@@ -21,13 +22,27 @@ type TeamServiceStorage interface {
 type Service struct {
 	s                TeamServiceStorage
 	inviteLinkPrefix string
+	events           *events.Batch
 }
 
-func NewService(s TeamServiceStorage, inviteLinkPrefix string) *Service {
-	return &Service{
+type Option func(s *Service)
+
+// WithEvents makes service record team events into batch, which should be published after transaction commit
+func WithEvents(batch *events.Batch) Option {
+	return func(s *Service) {
+		s.events = batch
+	}
+}
+
+func NewService(s TeamServiceStorage, inviteLinkPrefix string, opts ...Option) *Service {
+	service := &Service{
 		s:                s,
 		inviteLinkPrefix: inviteLinkPrefix,
 	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
 }
 
 func (s *Service) getRegistrationStatus(ctx context.Context, questID storage.ID) (storage.RegistrationStatus, error) {
@@ -158,6 +173,7 @@ func (s *Service) JoinTeam(ctx context.Context, req *storage.JoinTeamRequest) (*
 	}
 	team.InviteLink = s.inviteLinkPrefix + team.InviteLink
 	team.Members = append(team.Members, *user)
+	s.events.Add(events.New(events.MemberJoined, team.Quest.ID, team.ID, events.MemberData{UserID: user.ID}))
 	return team, nil
 }
 
@@ -195,6 +211,7 @@ func (s *Service) ChangeLeader(ctx context.Context, user *storage.User, req *sto
 		return nil, xerrors.Errorf("change leader: %w", err)
 	}
 	newTeam.InviteLink = s.inviteLinkPrefix + newTeam.InviteLink
+	s.events.Add(events.New(events.CaptainChanged, team.Quest.ID, team.ID, events.MemberData{UserID: req.CaptainID}))
 
 	return newTeam, nil
 }
@@ -232,11 +249,13 @@ func (s *Service) LeaveTeam(ctx context.Context, user *storage.User, teamID, new
 			zap.Stringer("old_captain", user.ID),
 			zap.Stringer("new_captain", newCaptainID),
 		)
+		s.events.Add(events.New(events.CaptainChanged, team.Quest.ID, team.ID, events.MemberData{UserID: newCaptainID}))
 	}
 
 	if err := s.s.RemoveUser(ctx, &storage.RemoveUserRequest{ID: teamID, UserID: user.ID}); err != nil {
 		return nil, xerrors.Errorf("leave team: %w", err)
 	}
+	s.events.Add(events.New(events.MemberLeft, team.Quest.ID, team.ID, events.MemberData{UserID: user.ID}))
 
 	members := make([]storage.User, 0, len(team.Members)-1)
 	for _, member := range team.Members {
@@ -273,6 +292,7 @@ func (s *Service) RemoveUser(ctx context.Context, user *storage.User, req *stora
 	if err := s.s.RemoveUser(ctx, req); err != nil {
 		return nil, xerrors.Errorf("remove user: %w", err)
 	}
+	s.events.Add(events.New(events.MemberLeft, team.Quest.ID, team.ID, events.MemberData{UserID: req.UserID}))
 
 	members := make([]storage.User, 0, len(team.Members)-1)
 	for _, member := range team.Members {
@@ -320,6 +340,7 @@ func (s *Service) AcceptTeam(ctx context.Context, user *storage.User, questID, t
 			}
 			return nil, xerrors.Errorf("accept team: %w", err)
 		}
+		s.events.Add(events.New(events.TeamAccepted, questID, teamID, nil))
 	}
 	allTeams, err := s.s.GetTeams(ctx, &storage.GetTeamsRequest{QuestIDs: []storage.ID{questID}, IncludeMembers: true})
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"questspace/internal/questspace/events"
 	"questspace/pkg/httperrors"
 	"questspace/pkg/storage"
 	storagemock "questspace/pkg/storage/mocks"
@@ -116,7 +117,9 @@ func TestTeamService_JoinTeam(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	s := storagemock.NewMockQuestSpaceStorage(ctrl)
-	service := NewService(s, linkPrefix)
+	broker := events.NewBroker()
+	batch := events.NewBatch(broker)
+	service := NewService(s, linkPrefix, WithEvents(batch))
 
 	newMember := storage.User{
 		ID:        storage.NewID(),
@@ -161,6 +164,14 @@ func TestTeamService_JoinTeam(t *testing.T) {
 	assert.Equal(t, team.ID, got.ID)
 	require.Len(t, got.Members, 2)
 	assert.ElementsMatch(t, []storage.User{teamCreator, newMember}, got.Members)
+
+	sub := broker.Subscribe(questID, 1)
+	defer sub.Close()
+	batch.Publish()
+	e := <-sub.Events()
+	assert.Equal(t, events.MemberJoined, e.Type)
+	assert.Equal(t, team.ID, e.TeamID)
+	assert.Equal(t, events.MemberData{UserID: newMember.ID}, e.Data)
 }
 
 func TestTeamService_JoinTeam_AlreadyInvited(t *testing.T) {
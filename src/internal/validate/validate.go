@@ -11,6 +11,8 @@ import (
 	"github.com/yandex/perforator/library/go/core/xerrors"
 
 	"questspace/pkg/httperrors"
+	"questspace/pkg/matchers"
+	"questspace/pkg/storage"
 )
 
 const imgHeadTimeout = time.Second * 5
@@ -48,3 +50,175 @@ func ImageURL(ctx context.Context, client http.Client, imgUrl string) error {
 	}
 	return nil
 }
+
+func AnswerLimits(l *storage.AnswerLimits) error {
+	if l == nil {
+		return nil
+	}
+	if l.MaxAttempts != nil && *l.MaxAttempts <= 0 {
+		return httperrors.Errorf(http.StatusBadRequest, "max_attempts should be positive, but got %d", *l.MaxAttempts)
+	}
+	if l.CooldownAttempts != nil && *l.CooldownAttempts <= 0 {
+		return httperrors.Errorf(http.StatusBadRequest, "cooldown_attempts should be positive, but got %d", *l.CooldownAttempts)
+	}
+	if l.Cooldown != nil && *l.Cooldown < 0 {
+		return httperrors.New(http.StatusBadRequest, "cooldown should not be negative")
+	}
+	if (l.CooldownAttempts == nil) != (l.Cooldown == nil) {
+		return httperrors.New(http.StatusBadRequest, "cooldown and cooldown_attempts should be set together")
+	}
+	return Penalty("wrong answer penalty", l.WrongAnswerPenalty)
+}
+
+// Penalty checks penalty set either by score or by percent of reward. Name is used in error messages
+func Penalty(name string, p *storage.PenaltyOneOf) error {
+	if p == nil {
+		return nil
+	}
+	if p.IsPercent() && (p.Percent() < 0 || p.Percent() > 100) {
+		return httperrors.Errorf(http.StatusBadRequest, "%s percent should be in bounds [0; 100], but got %d", name, p.Percent())
+	}
+	if p.IsScore() && p.Score() < 0 {
+		return httperrors.Errorf(http.StatusBadRequest, "%s should not be negative", name)
+	}
+	return nil
+}
+
+// Matcher checks that matcher of the given type can be built for every correct answer, the same way as on answer.
+// Nil matcher means exact matching, which accepts any answers
+func Matcher(m *storage.AnswerMatcher, correctAnswers []string) error {
+	if _, err := matchers.New(m, correctAnswers); err != nil {
+		return httperrors.WrapWithCode(http.StatusBadRequest, err)
+	}
+	return nil
+}
+
+func Parts(taskType storage.TaskType, parts []storage.TaskPart) error {
+	if taskType != storage.TaskTypeParts {
+		if len(parts) > 0 {
+			return httperrors.New(http.StatusBadRequest, "parts are allowed only for multi-part tasks")
+		}
+		return nil
+	}
+	if len(parts) < 2 {
+		return httperrors.New(http.StatusBadRequest, "multi-part task should have at least 2 parts")
+	}
+	total := 0
+	for i, part := range parts {
+		if len(part.CorrectAnswers) == 0 {
+			return httperrors.Errorf(http.StatusBadRequest, "part #%d should have correct answers", i+1)
+		}
+		if part.Share <= 0 {
+			return httperrors.Errorf(http.StatusBadRequest, "share of part #%d should be positive", i+1)
+		}
+		total += part.Share
+	}
+	if total != 100 {
+		return httperrors.Errorf(http.StatusBadRequest, "shares of parts should sum up to 100, but got %d", total)
+	}
+	return nil
+}
+
+func Location(l *storage.Location) error {
+	if l == nil {
+		return nil
+	}
+	if l.Lat < -90 || l.Lat > 90 {
+		return httperrors.Errorf(http.StatusBadRequest, "latitude should be in bounds [-90; 90], but got %g", l.Lat)
+	}
+	if l.Lon < -180 || l.Lon > 180 {
+		return httperrors.Errorf(http.StatusBadRequest, "longitude should be in bounds [-180; 180], but got %g", l.Lon)
+	}
+	if l.Radius <= 0 {
+		return httperrors.New(http.StatusBadRequest, "location radius should be positive")
+	}
+	return nil
+}
+
+func LeaderboardFreeze(d *storage.Duration) error {
+	if d != nil && *d < 0 {
+		return httperrors.Errorf(http.StatusBadRequest, "leaderboard_freeze should not be negative, but got %s", time.Duration(*d))
+	}
+	return nil
+}
+
+func Scoring(s *storage.Scoring, reward int) error {
+	if s == nil {
+		return nil
+	}
+	if d := s.Decay; d != nil {
+		if d.Type != storage.DecayLinear && d.Type != storage.DecayStep {
+			return httperrors.Errorf(http.StatusBadRequest, "unknown decay type %q", d.Type)
+		}
+		if d.From != "" && d.From != storage.DecayFromQuestStart && d.From != storage.DecayFromGroupOpening {
+			return httperrors.Errorf(http.StatusBadRequest, "unknown decay start %q", d.From)
+		}
+		if d.Interval <= 0 {
+			return httperrors.New(http.StatusBadRequest, "decay interval should be positive")
+		}
+		if d.Amount < 0 || d.MinReward < 0 {
+			return httperrors.New(http.StatusBadRequest, "decay amount and min reward should not be negative")
+		}
+		if d.MinReward > reward {
+			return httperrors.Errorf(http.StatusBadRequest, "decay min reward should not exceed task reward: %d vs %d", d.MinReward, reward)
+		}
+	}
+	for i, bonus := range s.FirstSolveBonuses {
+		if bonus < 0 {
+			return httperrors.Errorf(http.StatusBadRequest, "first solve bonus #%d should not be negative", i+1)
+		}
+	}
+	return nil
+}
+
+func Choice(taskType storage.TaskType, c *storage.Choice) error {
+	switch taskType {
+	case "", storage.TaskTypeText, storage.TaskTypeFile, storage.TaskTypeLocation, storage.TaskTypeParts:
+		if c != nil {
+			return httperrors.New(http.StatusBadRequest, "options are allowed only for choice tasks")
+		}
+		return nil
+	case storage.TaskTypeChoice:
+	default:
+		return httperrors.Errorf(http.StatusBadRequest, "unknown task type %q", taskType)
+	}
+	if c == nil || len(c.Options) < 2 {
+		return httperrors.New(http.StatusBadRequest, "choice task should have at least 2 options")
+	}
+	if len(c.Correct) == 0 {
+		return httperrors.New(http.StatusBadRequest, "choice task should have correct option")
+	}
+	if !c.Multiple && len(c.Correct) > 1 {
+		return httperrors.New(http.StatusBadRequest, "single choice task should have exactly one correct option")
+	}
+	if c.PartialScoring && !c.Multiple {
+		return httperrors.New(http.StatusBadRequest, "partial scoring is allowed only for multiple choice")
+	}
+	seen := make(map[int]struct{}, len(c.Correct))
+	for _, idx := range c.Correct {
+		if idx < 0 || idx >= len(c.Options) {
+			return httperrors.Errorf(http.StatusBadRequest, "correct option %d is out of options range", idx)
+		}
+		if _, ok := seen[idx]; ok {
+			return httperrors.Errorf(http.StatusBadRequest, "correct option %d is duplicated", idx)
+		}
+		seen[idx] = struct{}{}
+	}
+	return nil
+}
+
+func BonusCode(req *storage.CreateBonusCodeRequest) error {
+	if len(strings.TrimSpace(req.Code)) == 0 {
+		return httperrors.New(http.StatusBadRequest, "bonus code should not be empty")
+	}
+	if req.Value == 0 {
+		return httperrors.New(http.StatusBadRequest, "bonus code value should not be zero")
+	}
+	if req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidFrom.Before(*req.ValidUntil) {
+		return httperrors.New(http.StatusBadRequest, "valid_from should be before valid_until")
+	}
+	if req.MaxUses != nil && *req.MaxUses <= 0 {
+		return httperrors.Errorf(http.StatusBadRequest, "max_uses should be positive, but got %d", *req.MaxUses)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistance(t *testing.T) {
+	moscow := Point{Lat: 55.7539, Lon: 37.6208}
+	petersburg := Point{Lat: 59.9398, Lon: 30.3146}
+
+	assert.Zero(t, Distance(moscow, moscow))
+	assert.InDelta(t, 634_000, Distance(moscow, petersburg), 2_000)
+	assert.InDelta(t, Distance(moscow, petersburg), Distance(petersburg, moscow), 1e-6)
+	// one degree of latitude is about 111 km everywhere
+	assert.InDelta(t, 111_195, Distance(Point{Lat: 10, Lon: 20}, Point{Lat: 11, Lon: 20}), 10)
+}
+
+func TestFeatureCollection_JSON(t *testing.T) {
+	data, err := json.Marshal(NewFeatureCollection(NewPointFeature(Point{Lat: 1.5, Lon: 2.5}, map[string]any{"name": "start"})))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [2.5, 1.5]},
+			"properties": {"name": "start"}
+		}]
+	}`, string(data))
+
+	data, err = json.Marshal(NewFeatureCollection())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "FeatureCollection", "features": []}`, string(data))
+}
@@ -0,0 +1,231 @@
+// Package matchers builds answer matchers of tasks. It is shared by game, which checks answers, and validate,
+// which checks matcher settings, and lives outside of game because game imports validate
+package matchers
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+	"golang.org/x/text/unicode/norm"
+
+	"questspace/pkg/storage"
+)
+
+// Matcher checks team answer against correct answers of a single task
+type Matcher interface {
+	Match(answer string) bool
+}
+
+// Factory builds Matcher for task correct answers with given matcher params
+type Factory func(params *storage.AnswerMatcher, correctAnswers []string) (Matcher, error)
+
+var factories = map[storage.MatcherType]Factory{
+	storage.MatcherExact:             newExactMatcher,
+	storage.MatcherRegex:             newRegexMatcher,
+	storage.MatcherNumeric:           newNumericMatcher,
+	storage.MatcherIgnorePunctuation: newIgnorePunctuationMatcher,
+	storage.MatcherNormalized:        newNormalizedMatcher,
+	storage.MatcherUnordered:         newUnorderedMatcher,
+	storage.MatcherFuzzy:             newFuzzyMatcher,
+}
+
+// New returns matcher for task answers. Nil params fall back to exact case-insensitive matching
+func New(params *storage.AnswerMatcher, correctAnswers []string) (Matcher, error) {
+	if params == nil {
+		params = &storage.AnswerMatcher{Type: storage.MatcherExact}
+	}
+	factory, ok := factories[params.Type]
+	if !ok {
+		return nil, xerrors.Errorf("unknown matcher type %q", params.Type)
+	}
+	m, err := factory(params, correctAnswers)
+	if err != nil {
+		return nil, xerrors.Errorf("%s matcher: %w", params.Type, err)
+	}
+	return m, nil
+}
+
+type funcMatcher struct {
+	correct   []string
+	normalize func(string) string
+	equal     func(answer, correct string) bool
+}
+
+func (m *funcMatcher) Match(answer string) bool {
+	answer = m.normalize(answer)
+	for _, correct := range m.correct {
+		if m.equal(answer, correct) {
+			return true
+		}
+	}
+	return false
+}
+
+func newFuncMatcher(correctAnswers []string, normalize func(string) string, equal func(answer, correct string) bool) *funcMatcher {
+	correct := make([]string, 0, len(correctAnswers))
+	for _, c := range correctAnswers {
+		correct = append(correct, normalize(c))
+	}
+	return &funcMatcher{correct: correct, normalize: normalize, equal: equal}
+}
+
+func stringsEqual(answer, correct string) bool {
+	return answer == correct
+}
+
+func lowerTrimmed(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func newExactMatcher(_ *storage.AnswerMatcher, correctAnswers []string) (Matcher, error) {
+	return newFuncMatcher(correctAnswers, lowerTrimmed, stringsEqual), nil
+}
+
+type regexMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+func (m *regexMatcher) Match(answer string) bool {
+	answer = strings.TrimSpace(answer)
+	for _, p := range m.patterns {
+		if p.MatchString(answer) {
+			return true
+		}
+	}
+	return false
+}
+
+func newRegexMatcher(_ *storage.AnswerMatcher, correctAnswers []string) (Matcher, error) {
+	patterns := make([]*regexp.Regexp, 0, len(correctAnswers))
+	for _, c := range correctAnswers {
+		p, err := regexp.Compile("(?i)^(?:" + strings.TrimSpace(c) + ")$")
+		if err != nil {
+			return nil, xerrors.Errorf("bad pattern %q: %w", c, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return &regexMatcher{patterns: patterns}, nil
+}
+
+type numericMatcher struct {
+	correct   []float64
+	tolerance float64
+}
+
+func parseNumber(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	return strconv.ParseFloat(s, 64)
+}
+
+func (m *numericMatcher) Match(answer string) bool {
+	val, err := parseNumber(answer)
+	if err != nil {
+		return false
+	}
+	for _, c := range m.correct {
+		if math.Abs(val-c) <= m.tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+func newNumericMatcher(params *storage.AnswerMatcher, correctAnswers []string) (Matcher, error) {
+	m := &numericMatcher{correct: make([]float64, 0, len(correctAnswers))}
+	if params.Tolerance != nil {
+		if *params.Tolerance < 0 {
+			return nil, xerrors.Errorf("tolerance should be non-negative, but got %v", *params.Tolerance)
+		}
+		m.tolerance = *params.Tolerance
+	}
+	for _, c := range correctAnswers {
+		val, err := parseNumber(c)
+		if err != nil {
+			return nil, xerrors.Errorf("bad number %q: %w", c, err)
+		}
+		m.correct = append(m.correct, val)
+	}
+	return m, nil
+}
+
+func stripPunctuation(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSpace(r) || unicode.IsSymbol(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+func newIgnorePunctuationMatcher(_ *storage.AnswerMatcher, correctAnswers []string) (Matcher, error) {
+	return newFuncMatcher(correctAnswers, stripPunctuation, stringsEqual), nil
+}
+
+// normalizeUnicode lowercases string, collapses spaces and drops diacritics, so "Ёлка" matches "елка" and "café" matches "cafe"
+func normalizeUnicode(s string) string {
+	decomposed := norm.NFD.String(lowerTrimmed(s))
+	s = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, decomposed)
+	return norm.NFC.String(strings.Join(strings.Fields(s), " "))
+}
+
+func newNormalizedMatcher(_ *storage.AnswerMatcher, correctAnswers []string) (Matcher, error) {
+	return newFuncMatcher(correctAnswers, normalizeUnicode, stringsEqual), nil
+}
+
+func sortedTokens(s string) string {
+	tokens := strings.FieldsFunc(normalizeUnicode(s), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == ';'
+	})
+	slices.Sort(tokens)
+	return strings.Join(tokens, " ")
+}
+
+func newUnorderedMatcher(_ *storage.AnswerMatcher, correctAnswers []string) (Matcher, error) {
+	return newFuncMatcher(correctAnswers, sortedTokens, stringsEqual), nil
+}
+
+const defaultFuzzyThreshold = 1
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func newFuzzyMatcher(params *storage.AnswerMatcher, correctAnswers []string) (Matcher, error) {
+	threshold := defaultFuzzyThreshold
+	if params.Threshold != nil {
+		if *params.Threshold < 0 {
+			return nil, xerrors.Errorf("threshold should be non-negative, but got %d", *params.Threshold)
+		}
+		threshold = *params.Threshold
+	}
+	return newFuncMatcher(correctAnswers, normalizeUnicode, func(answer, correct string) bool {
+		return levenshtein(answer, correct) <= threshold
+	}), nil
+}
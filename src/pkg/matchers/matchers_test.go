@@ -0,0 +1,154 @@
+package matchers
+
+import (
+	"testing"
+
+	"github.com/spkg/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"questspace/pkg/storage"
+)
+
+func TestMatchers(t *testing.T) {
+	testCases := []struct {
+		name     string
+		params   *storage.AnswerMatcher
+		correct  []string
+		answer   string
+		expected bool
+	}{
+		{
+			name:     "default exact",
+			correct:  []string{"Moscow"},
+			answer:   "  moscow ",
+			expected: true,
+		},
+		{
+			name:     "exact mismatch",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherExact},
+			correct:  []string{"Moscow"},
+			answer:   "Moskow",
+			expected: false,
+		},
+		{
+			name:     "regex",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherRegex},
+			correct:  []string{`colou?r`},
+			answer:   "COLOR",
+			expected: true,
+		},
+		{
+			name:     "regex is anchored",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherRegex},
+			correct:  []string{`colou?r`},
+			answer:   "colors",
+			expected: false,
+		},
+		{
+			name:     "numeric within tolerance",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherNumeric, Tolerance: ptr.Float64(0.01)},
+			correct:  []string{"3.14"},
+			answer:   "3,141",
+			expected: true,
+		},
+		{
+			name:     "numeric out of tolerance",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherNumeric},
+			correct:  []string{"3.14"},
+			answer:   "3.141",
+			expected: false,
+		},
+		{
+			name:     "ignore punctuation",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherIgnorePunctuation},
+			correct:  []string{"Rock'n'Roll"},
+			answer:   "rock n roll!",
+			expected: true,
+		},
+		{
+			name:     "normalized cyrillic",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherNormalized},
+			correct:  []string{"Ёлка"},
+			answer:   "елка",
+			expected: true,
+		},
+		{
+			name:     "normalized diacritics",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherNormalized},
+			correct:  []string{"Café  crème"},
+			answer:   "cafe creme",
+			expected: true,
+		},
+		{
+			name:     "unordered",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherUnordered},
+			correct:  []string{"red, green, blue"},
+			answer:   "Blue Red green",
+			expected: true,
+		},
+		{
+			name:     "unordered missing token",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherUnordered},
+			correct:  []string{"red green blue"},
+			answer:   "red blue",
+			expected: false,
+		},
+		{
+			name:     "fuzzy default threshold",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherFuzzy},
+			correct:  []string{"Dostoevsky"},
+			answer:   "dostoevski",
+			expected: true,
+		},
+		{
+			name:     "fuzzy over threshold",
+			params:   &storage.AnswerMatcher{Type: storage.MatcherFuzzy, Threshold: ptr.Int(1)},
+			correct:  []string{"Dostoevsky"},
+			answer:   "dostoyevskiy",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := New(tc.params, tc.correct)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, m.Match(tc.answer))
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		params  *storage.AnswerMatcher
+		correct []string
+	}{
+		{
+			name:   "unknown type",
+			params: &storage.AnswerMatcher{Type: "soundex"},
+		},
+		{
+			name:    "bad regex",
+			params:  &storage.AnswerMatcher{Type: storage.MatcherRegex},
+			correct: []string{"(unclosed"},
+		},
+		{
+			name:    "not a number",
+			params:  &storage.AnswerMatcher{Type: storage.MatcherNumeric},
+			correct: []string{"pi"},
+		},
+		{
+			name:   "negative threshold",
+			params: &storage.AnswerMatcher{Type: storage.MatcherFuzzy, Threshold: ptr.Int(-1)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.params, tc.correct)
+			assert.Error(t, err)
+		})
+	}
+}
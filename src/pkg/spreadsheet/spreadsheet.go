@@ -0,0 +1,153 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+
+	"questspace/pkg/httperrors"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// TimeLayout is recognized as date and time by common spreadsheet editors
+const TimeLayout = time.DateTime
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatXLSX:
+		return f, nil
+	default:
+		return "", httperrors.Errorf(http.StatusBadRequest, "unknown format %q, expected one of: csv, xlsx", s)
+	}
+}
+
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Sheet is a table with header row.
+// Cells may be strings, integers, floats, time.Time or *time.Time, nil pointers are written as empty cells.
+type Sheet struct {
+	Name   string
+	Header []string
+	Rows   [][]any
+}
+
+func Write(w io.Writer, f Format, sheet *Sheet) error {
+	switch f {
+	case FormatCSV:
+		return writeCSV(w, sheet)
+	case FormatXLSX:
+		return writeXLSX(w, sheet)
+	default:
+		return xerrors.Errorf("unsupported format %q", f)
+	}
+}
+
+func writeCSV(w io.Writer, sheet *Sheet) error {
+	cw, err := NewCSVWriter(w, sheet.Header)
+	if err != nil {
+		return err
+	}
+	for _, row := range sheet.Rows {
+		if err = cw.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+// CSVWriter writes table row by row, so that large tables can be streamed without keeping them in memory
+type CSVWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+// NewCSVWriter writes header and returns writer for the rest of rows
+func NewCSVWriter(w io.Writer, header []string) (*CSVWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return nil, xerrors.Errorf("write header: %w", err)
+	}
+	return &CSVWriter{w: cw, record: make([]string, 0, len(header))}, nil
+}
+
+func (cw *CSVWriter) WriteRow(row []any) error {
+	cw.record = cw.record[:0]
+	for _, cell := range row {
+		value, kind := formatCell(cell)
+		if kind == cellText {
+			value = escapeFormula(value)
+		}
+		cw.record = append(cw.record, value)
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return xerrors.Errorf("write row: %w", err)
+	}
+	return nil
+}
+
+// Flush writes buffered rows to underlying writer
+func (cw *CSVWriter) Flush() error {
+	cw.w.Flush()
+	if err := cw.w.Error(); err != nil {
+		return xerrors.Errorf("flush: %w", err)
+	}
+	return nil
+}
+
+type cellKind int
+
+const (
+	cellEmpty cellKind = iota
+	cellText
+	cellNumber
+	cellTime
+)
+
+func formatCell(cell any) (string, cellKind) {
+	switch v := cell.(type) {
+	case nil:
+		return "", cellEmpty
+	case string:
+		return v, cellText
+	case int:
+		return strconv.Itoa(v), cellNumber
+	case int64:
+		return strconv.FormatInt(v, 10), cellNumber
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), cellNumber
+	case time.Time:
+		return v.Format(TimeLayout), cellTime
+	case *time.Time:
+		if v == nil {
+			return "", cellEmpty
+		}
+		return v.Format(TimeLayout), cellTime
+	default:
+		return fmt.Sprint(v), cellText
+	}
+}
+
+// escapeFormula prevents user-provided text from being evaluated as formula, when CSV is opened in spreadsheet editor
+func escapeFormula(s string) string {
+	if len(s) > 0 && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
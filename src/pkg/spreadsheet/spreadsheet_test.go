@@ -0,0 +1,72 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSheet = Sheet{
+	Name:   "Results: final",
+	Header: []string{"Team", "Score", "Last answer"},
+	Rows: [][]any{
+		{"=cmd|' /C calc'!A0", 10, time.Date(2024, time.April, 14, 12, 30, 0, 0, time.UTC)},
+		{"Team <2> & co", -5, (*time.Time)(nil)},
+	},
+}
+
+func TestWrite_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, &testSheet))
+	expected := "Team,Score,Last answer\n" +
+		"'=cmd|' /C calc'!A0,10,2024-04-14 12:30:00\n" +
+		"Team <2> & co,-5,\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWrite_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatXLSX, &testSheet))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(data)
+	}
+
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files, "xl/workbook.xml")
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Results final"`)
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="B2"><v>10</v></c>`)
+	assert.Contains(t, sheet, `<c r="A3" t="inlineStr"><is><t xml:space="preserve">Team &lt;2&gt; &amp; co</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="C2" t="inlineStr"><is><t xml:space="preserve">2024-04-14 12:30:00</t></is></c>`)
+	assert.NotContains(t, sheet, `r="C3"`)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, f)
+	_, err = ParseFormat("pdf")
+	assert.Error(t, err)
+}
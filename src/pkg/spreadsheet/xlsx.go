@@ -0,0 +1,159 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+)
+
+// Minimal SpreadsheetML package with single worksheet. Strings are written inline, so no shared strings table is needed.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+	xlsxWorkbookStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="`
+	xlsxWorkbookEnd = `" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+const (
+	maxSheetNameLen  = 31
+	defaultSheetName = "Sheet1"
+)
+
+func writeXLSX(w io.Writer, sheet *Sheet) error {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{name: "[Content_Types].xml", write: writeString(xlsxContentTypes)},
+		{name: "_rels/.rels", write: writeString(xlsxRels)},
+		{name: "xl/_rels/workbook.xml.rels", write: writeString(xlsxWorkbookRels)},
+		{name: "xl/workbook.xml", write: func(w io.Writer) error {
+			if _, err := io.WriteString(w, xlsxWorkbookStart); err != nil {
+				return err
+			}
+			if err := xml.EscapeText(w, []byte(sheetName(sheet.Name))); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, xlsxWorkbookEnd)
+			return err
+		}},
+		{name: "xl/worksheets/sheet1.xml", write: sheet.writeXLSXWorksheet},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return xerrors.Errorf("create %q: %w", f.name, err)
+		}
+		if err = f.write(fw); err != nil {
+			return xerrors.Errorf("write %q: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return xerrors.Errorf("close archive: %w", err)
+	}
+	return nil
+}
+
+func writeString(s string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+}
+
+func (s *Sheet) writeXLSXWorksheet(w io.Writer) error {
+	if _, err := io.WriteString(w, xlsxSheetStart); err != nil {
+		return err
+	}
+	header := make([]any, 0, len(s.Header))
+	for _, h := range s.Header {
+		header = append(header, h)
+	}
+	if err := writeXLSXRow(w, 1, header); err != nil {
+		return err
+	}
+	for i, row := range s.Rows {
+		if err := writeXLSXRow(w, i+2, row); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, xlsxSheetEnd)
+	return err
+}
+
+func writeXLSXRow(w io.Writer, rowNum int, row []any) error {
+	var b strings.Builder
+	b.WriteString(`<row r="`)
+	b.WriteString(strconv.Itoa(rowNum))
+	b.WriteString(`">`)
+	for i, cell := range row {
+		value, kind := formatCell(cell)
+		if kind == cellEmpty || len(value) == 0 {
+			continue
+		}
+		ref := columnName(i) + strconv.Itoa(rowNum)
+		if kind != cellNumber {
+			// times are written as text, since dates require styles which are not worth it for exports
+			b.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(&b, []byte(value)); err != nil {
+				return err
+			}
+			b.WriteString(`</t></is></c>`)
+			continue
+		}
+		b.WriteString(`<c r="` + ref + `"><v>` + value + `</v></c>`)
+	}
+	b.WriteString(`</row>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// columnName converts zero-based column index to its letter name: A, B, ..., Z, AA, AB, ...
+func columnName(idx int) string {
+	var name []byte
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = append([]byte{byte('A' + (idx-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// sheetName drops characters forbidden in sheet names and truncates name to allowed length
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > maxSheetNameLen {
+		name = string(runes[:maxSheetNameLen])
+	}
+	if strings.TrimSpace(name) == "" {
+		return defaultSheetName
+	}
+	return name
+}
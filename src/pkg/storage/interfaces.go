@@ -29,6 +29,10 @@ type QuestStorage interface {
 	UpdateQuest(context.Context, *UpdateQuestRequest) (*Quest, error)
 	DeleteQuest(context.Context, *DeleteQuestRequest) error
 	FinishQuest(context.Context, *FinishQuestRequest) error
+	GetExpiredQuests(context.Context, *GetExpiredQuestsRequest) ([]ID, error)
+	GetRunningQuests(context.Context, *GetRunningQuestsRequest) ([]ID, error)
+	RevealLeaderboard(context.Context, *RevealLeaderboardRequest) error
+	Notify(context.Context, *NotifyRequest) error
 }
 
 type TaskGroupStorage interface {
@@ -67,6 +71,7 @@ type AnswerHintStorage interface {
 	AnswerStorage
 	HintStorage
 	PenaltyStorage
+	BonusCodeStorage
 }
 
 type HintStorage interface {
@@ -77,13 +82,33 @@ type HintStorage interface {
 type AnswerStorage interface {
 	GetAcceptedTasks(context.Context, *GetAcceptedTasksRequest) (AcceptedTasks, error)
 	CreateAnswerTry(context.Context, *CreateAnswerTryRequest) error
+	LockAnswerTries(context.Context, *LockAnswerTriesRequest) error
+	GetAnswerTryStats(context.Context, *GetAnswerTryStatsRequest) (*AnswerTryStats, error)
+	GetTaskSolveCount(context.Context, *GetTaskSolveCountRequest) (int, error)
 	GetScoreResults(context.Context, *GetResultsRequest) (ScoreResults, error)
+	GetScoreHistory(context.Context, *GetScoreHistoryRequest) (ScoreHistory, error)
 	GetAnswerTries(context.Context, *GetAnswerTriesRequest, ...FilteringOption) (*AnswerLogRecords, error)
+	IterAnswerTries(context.Context, *GetAnswerTriesRequest, func(*AnswerLog) error, ...FilteringOption) error
+	GetAnswerTry(context.Context, *GetAnswerTryRequest) (*AnswerTry, error)
+	GetReviewAnswerTries(context.Context, *GetReviewAnswerTriesRequest) ([]AnswerTry, error)
+	ReviewAnswerTry(context.Context, *ReviewAnswerTryRequest) error
+	RejectPendingAnswerTries(context.Context, *RejectPendingAnswerTriesRequest) error
 }
 
 type PenaltyStorage interface {
 	GetPenalties(context.Context, *GetPenaltiesRequest) (TeamPenalties, error)
 	CreatePenalty(context.Context, *CreatePenaltyRequest) error
+	GetAdjustments(context.Context, *GetAdjustmentsRequest) ([]Adjustment, error)
+	RevokeAdjustment(context.Context, *RevokeAdjustmentRequest) error
+}
+
+type BonusCodeStorage interface {
+	CreateBonusCode(context.Context, *CreateBonusCodeRequest) (*BonusCode, error)
+	GetBonusCode(context.Context, *GetBonusCodeRequest) (*BonusCode, error)
+	GetBonusCodes(context.Context, *GetBonusCodesRequest) ([]BonusCode, error)
+	DeleteBonusCode(context.Context, *DeleteBonusCodeRequest) error
+	UseBonusCode(context.Context, *UseBonusCodeRequest) error
+	GetBonusCodeUses(context.Context, *GetBonusCodeUsesRequest) (TeamBonusCodes, error)
 }
 
 type TeamInfoStorage interface {
@@ -94,6 +94,21 @@ func (mr *MockQuestSpaceStorageMockRecorder) CreateAnswerTry(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnswerTry", reflect.TypeOf((*MockQuestSpaceStorage)(nil).CreateAnswerTry), arg0, arg1)
 }
 
+// CreateBonusCode mocks base method.
+func (m *MockQuestSpaceStorage) CreateBonusCode(arg0 context.Context, arg1 *storage.CreateBonusCodeRequest) (*storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(*storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBonusCode indicates an expected call of CreateBonusCode.
+func (mr *MockQuestSpaceStorageMockRecorder) CreateBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBonusCode", reflect.TypeOf((*MockQuestSpaceStorage)(nil).CreateBonusCode), arg0, arg1)
+}
+
 // CreateOrUpdateByExternalID mocks base method.
 func (m *MockQuestSpaceStorage) CreateOrUpdateByExternalID(arg0 context.Context, arg1 *storage.CreateOrUpdateRequest) (*storage.User, error) {
 	m.ctrl.T.Helper()
@@ -198,6 +213,20 @@ func (mr *MockQuestSpaceStorageMockRecorder) CreateUser(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockQuestSpaceStorage)(nil).CreateUser), arg0, arg1)
 }
 
+// DeleteBonusCode mocks base method.
+func (m *MockQuestSpaceStorage) DeleteBonusCode(arg0 context.Context, arg1 *storage.DeleteBonusCodeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBonusCode indicates an expected call of DeleteBonusCode.
+func (mr *MockQuestSpaceStorageMockRecorder) DeleteBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBonusCode", reflect.TypeOf((*MockQuestSpaceStorage)(nil).DeleteBonusCode), arg0, arg1)
+}
+
 // DeleteQuest mocks base method.
 func (m *MockQuestSpaceStorage) DeleteQuest(arg0 context.Context, arg1 *storage.DeleteQuestRequest) error {
 	m.ctrl.T.Helper()
@@ -297,6 +326,21 @@ func (mr *MockQuestSpaceStorageMockRecorder) GetAcceptedTasks(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAcceptedTasks", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetAcceptedTasks), arg0, arg1)
 }
 
+// GetAdjustments mocks base method.
+func (m *MockQuestSpaceStorage) GetAdjustments(arg0 context.Context, arg1 *storage.GetAdjustmentsRequest) ([]storage.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjustments", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjustments indicates an expected call of GetAdjustments.
+func (mr *MockQuestSpaceStorageMockRecorder) GetAdjustments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjustments", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetAdjustments), arg0, arg1)
+}
+
 // GetAnswerData mocks base method.
 func (m *MockQuestSpaceStorage) GetAnswerData(arg0 context.Context, arg1 *storage.GetTaskRequest) (*storage.Task, error) {
 	m.ctrl.T.Helper()
@@ -332,6 +376,96 @@ func (mr *MockQuestSpaceStorageMockRecorder) GetAnswerTries(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTries", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetAnswerTries), varargs...)
 }
 
+// GetAnswerTry mocks base method.
+func (m *MockQuestSpaceStorage) GetAnswerTry(arg0 context.Context, arg1 *storage.GetAnswerTryRequest) (*storage.AnswerTry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswerTry", arg0, arg1)
+	ret0, _ := ret[0].(*storage.AnswerTry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswerTry indicates an expected call of GetAnswerTry.
+func (mr *MockQuestSpaceStorageMockRecorder) GetAnswerTry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTry", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetAnswerTry), arg0, arg1)
+}
+
+// GetAnswerTryStats mocks base method.
+func (m *MockQuestSpaceStorage) GetAnswerTryStats(arg0 context.Context, arg1 *storage.GetAnswerTryStatsRequest) (*storage.AnswerTryStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswerTryStats", arg0, arg1)
+	ret0, _ := ret[0].(*storage.AnswerTryStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswerTryStats indicates an expected call of GetAnswerTryStats.
+func (mr *MockQuestSpaceStorageMockRecorder) GetAnswerTryStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTryStats", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetAnswerTryStats), arg0, arg1)
+}
+
+// GetBonusCode mocks base method.
+func (m *MockQuestSpaceStorage) GetBonusCode(arg0 context.Context, arg1 *storage.GetBonusCodeRequest) (*storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(*storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCode indicates an expected call of GetBonusCode.
+func (mr *MockQuestSpaceStorageMockRecorder) GetBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCode", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetBonusCode), arg0, arg1)
+}
+
+// GetBonusCodeUses mocks base method.
+func (m *MockQuestSpaceStorage) GetBonusCodeUses(arg0 context.Context, arg1 *storage.GetBonusCodeUsesRequest) (storage.TeamBonusCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCodeUses", arg0, arg1)
+	ret0, _ := ret[0].(storage.TeamBonusCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCodeUses indicates an expected call of GetBonusCodeUses.
+func (mr *MockQuestSpaceStorageMockRecorder) GetBonusCodeUses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCodeUses", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetBonusCodeUses), arg0, arg1)
+}
+
+// GetBonusCodes mocks base method.
+func (m *MockQuestSpaceStorage) GetBonusCodes(arg0 context.Context, arg1 *storage.GetBonusCodesRequest) ([]storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCodes", arg0, arg1)
+	ret0, _ := ret[0].([]storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCodes indicates an expected call of GetBonusCodes.
+func (mr *MockQuestSpaceStorageMockRecorder) GetBonusCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCodes", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetBonusCodes), arg0, arg1)
+}
+
+// GetExpiredQuests mocks base method.
+func (m *MockQuestSpaceStorage) GetExpiredQuests(arg0 context.Context, arg1 *storage.GetExpiredQuestsRequest) ([]storage.ID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredQuests", arg0, arg1)
+	ret0, _ := ret[0].([]storage.ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredQuests indicates an expected call of GetExpiredQuests.
+func (mr *MockQuestSpaceStorageMockRecorder) GetExpiredQuests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredQuests", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetExpiredQuests), arg0, arg1)
+}
+
 // GetHintTakes mocks base method.
 func (m *MockQuestSpaceStorage) GetHintTakes(arg0 context.Context, arg1 *storage.GetHintTakesRequest) (storage.HintTakes, error) {
 	m.ctrl.T.Helper()
@@ -392,6 +526,51 @@ func (mr *MockQuestSpaceStorageMockRecorder) GetQuests(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuests", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetQuests), arg0, arg1)
 }
 
+// GetReviewAnswerTries mocks base method.
+func (m *MockQuestSpaceStorage) GetReviewAnswerTries(arg0 context.Context, arg1 *storage.GetReviewAnswerTriesRequest) ([]storage.AnswerTry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].([]storage.AnswerTry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewAnswerTries indicates an expected call of GetReviewAnswerTries.
+func (mr *MockQuestSpaceStorageMockRecorder) GetReviewAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewAnswerTries", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetReviewAnswerTries), arg0, arg1)
+}
+
+// GetRunningQuests mocks base method.
+func (m *MockQuestSpaceStorage) GetRunningQuests(arg0 context.Context, arg1 *storage.GetRunningQuestsRequest) ([]storage.ID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunningQuests", arg0, arg1)
+	ret0, _ := ret[0].([]storage.ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunningQuests indicates an expected call of GetRunningQuests.
+func (mr *MockQuestSpaceStorageMockRecorder) GetRunningQuests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningQuests", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetRunningQuests), arg0, arg1)
+}
+
+// GetScoreHistory mocks base method.
+func (m *MockQuestSpaceStorage) GetScoreHistory(arg0 context.Context, arg1 *storage.GetScoreHistoryRequest) (storage.ScoreHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScoreHistory", arg0, arg1)
+	ret0, _ := ret[0].(storage.ScoreHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScoreHistory indicates an expected call of GetScoreHistory.
+func (mr *MockQuestSpaceStorageMockRecorder) GetScoreHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreHistory", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetScoreHistory), arg0, arg1)
+}
+
 // GetScoreResults mocks base method.
 func (m *MockQuestSpaceStorage) GetScoreResults(arg0 context.Context, arg1 *storage.GetResultsRequest) (storage.ScoreResults, error) {
 	m.ctrl.T.Helper()
@@ -452,6 +631,21 @@ func (mr *MockQuestSpaceStorageMockRecorder) GetTaskGroups(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskGroups", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetTaskGroups), arg0, arg1)
 }
 
+// GetTaskSolveCount mocks base method.
+func (m *MockQuestSpaceStorage) GetTaskSolveCount(arg0 context.Context, arg1 *storage.GetTaskSolveCountRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskSolveCount", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskSolveCount indicates an expected call of GetTaskSolveCount.
+func (mr *MockQuestSpaceStorageMockRecorder) GetTaskSolveCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskSolveCount", reflect.TypeOf((*MockQuestSpaceStorage)(nil).GetTaskSolveCount), arg0, arg1)
+}
+
 // GetTasks mocks base method.
 func (m *MockQuestSpaceStorage) GetTasks(arg0 context.Context, arg1 *storage.GetTasksRequest) (storage.GetTasksResponse, error) {
 	m.ctrl.T.Helper()
@@ -572,6 +766,25 @@ func (mr *MockQuestSpaceStorageMockRecorder) HasAccess(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasAccess", reflect.TypeOf((*MockQuestSpaceStorage)(nil).HasAccess), arg0, arg1)
 }
 
+// IterAnswerTries mocks base method.
+func (m *MockQuestSpaceStorage) IterAnswerTries(arg0 context.Context, arg1 *storage.GetAnswerTriesRequest, arg2 func(*storage.AnswerLog) error, arg3 ...storage.FilteringOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IterAnswerTries", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterAnswerTries indicates an expected call of IterAnswerTries.
+func (mr *MockQuestSpaceStorageMockRecorder) IterAnswerTries(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterAnswerTries", reflect.TypeOf((*MockQuestSpaceStorage)(nil).IterAnswerTries), varargs...)
+}
+
 // JoinTeam mocks base method.
 func (m *MockQuestSpaceStorage) JoinTeam(arg0 context.Context, arg1 *storage.JoinTeamRequest) (*storage.User, error) {
 	m.ctrl.T.Helper()
@@ -587,6 +800,48 @@ func (mr *MockQuestSpaceStorageMockRecorder) JoinTeam(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinTeam", reflect.TypeOf((*MockQuestSpaceStorage)(nil).JoinTeam), arg0, arg1)
 }
 
+// LockAnswerTries mocks base method.
+func (m *MockQuestSpaceStorage) LockAnswerTries(arg0 context.Context, arg1 *storage.LockAnswerTriesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAnswerTries indicates an expected call of LockAnswerTries.
+func (mr *MockQuestSpaceStorageMockRecorder) LockAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAnswerTries", reflect.TypeOf((*MockQuestSpaceStorage)(nil).LockAnswerTries), arg0, arg1)
+}
+
+// Notify mocks base method.
+func (m *MockQuestSpaceStorage) Notify(arg0 context.Context, arg1 *storage.NotifyRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockQuestSpaceStorageMockRecorder) Notify(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockQuestSpaceStorage)(nil).Notify), arg0, arg1)
+}
+
+// RejectPendingAnswerTries mocks base method.
+func (m *MockQuestSpaceStorage) RejectPendingAnswerTries(arg0 context.Context, arg1 *storage.RejectPendingAnswerTriesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectPendingAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectPendingAnswerTries indicates an expected call of RejectPendingAnswerTries.
+func (mr *MockQuestSpaceStorageMockRecorder) RejectPendingAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectPendingAnswerTries", reflect.TypeOf((*MockQuestSpaceStorage)(nil).RejectPendingAnswerTries), arg0, arg1)
+}
+
 // RemoveUser mocks base method.
 func (m *MockQuestSpaceStorage) RemoveUser(arg0 context.Context, arg1 *storage.RemoveUserRequest) error {
 	m.ctrl.T.Helper()
@@ -601,6 +856,48 @@ func (mr *MockQuestSpaceStorageMockRecorder) RemoveUser(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUser", reflect.TypeOf((*MockQuestSpaceStorage)(nil).RemoveUser), arg0, arg1)
 }
 
+// RevealLeaderboard mocks base method.
+func (m *MockQuestSpaceStorage) RevealLeaderboard(arg0 context.Context, arg1 *storage.RevealLeaderboardRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevealLeaderboard", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevealLeaderboard indicates an expected call of RevealLeaderboard.
+func (mr *MockQuestSpaceStorageMockRecorder) RevealLeaderboard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevealLeaderboard", reflect.TypeOf((*MockQuestSpaceStorage)(nil).RevealLeaderboard), arg0, arg1)
+}
+
+// ReviewAnswerTry mocks base method.
+func (m *MockQuestSpaceStorage) ReviewAnswerTry(arg0 context.Context, arg1 *storage.ReviewAnswerTryRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewAnswerTry", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewAnswerTry indicates an expected call of ReviewAnswerTry.
+func (mr *MockQuestSpaceStorageMockRecorder) ReviewAnswerTry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewAnswerTry", reflect.TypeOf((*MockQuestSpaceStorage)(nil).ReviewAnswerTry), arg0, arg1)
+}
+
+// RevokeAdjustment mocks base method.
+func (m *MockQuestSpaceStorage) RevokeAdjustment(arg0 context.Context, arg1 *storage.RevokeAdjustmentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAdjustment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAdjustment indicates an expected call of RevokeAdjustment.
+func (mr *MockQuestSpaceStorageMockRecorder) RevokeAdjustment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAdjustment", reflect.TypeOf((*MockQuestSpaceStorage)(nil).RevokeAdjustment), arg0, arg1)
+}
+
 // SetInviteLink mocks base method.
 func (m *MockQuestSpaceStorage) SetInviteLink(arg0 context.Context, arg1 *storage.SetInvitePathRequest) error {
 	m.ctrl.T.Helper()
@@ -705,6 +1002,20 @@ func (mr *MockQuestSpaceStorageMockRecorder) UpsertTeamInfo(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTeamInfo", reflect.TypeOf((*MockQuestSpaceStorage)(nil).UpsertTeamInfo), arg0, arg1)
 }
 
+// UseBonusCode mocks base method.
+func (m *MockQuestSpaceStorage) UseBonusCode(arg0 context.Context, arg1 *storage.UseBonusCodeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseBonusCode indicates an expected call of UseBonusCode.
+func (mr *MockQuestSpaceStorageMockRecorder) UseBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseBonusCode", reflect.TypeOf((*MockQuestSpaceStorage)(nil).UseBonusCode), arg0, arg1)
+}
+
 // MockUserStorage is a mock of UserStorage interface.
 type MockUserStorage struct {
 	ctrl     *gomock.Controller
@@ -883,6 +1194,21 @@ func (mr *MockQuestStorageMockRecorder) FinishQuest(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishQuest", reflect.TypeOf((*MockQuestStorage)(nil).FinishQuest), arg0, arg1)
 }
 
+// GetExpiredQuests mocks base method.
+func (m *MockQuestStorage) GetExpiredQuests(arg0 context.Context, arg1 *storage.GetExpiredQuestsRequest) ([]storage.ID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredQuests", arg0, arg1)
+	ret0, _ := ret[0].([]storage.ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredQuests indicates an expected call of GetExpiredQuests.
+func (mr *MockQuestStorageMockRecorder) GetExpiredQuests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredQuests", reflect.TypeOf((*MockQuestStorage)(nil).GetExpiredQuests), arg0, arg1)
+}
+
 // GetQuest mocks base method.
 func (m *MockQuestStorage) GetQuest(arg0 context.Context, arg1 *storage.GetQuestRequest) (*storage.Quest, error) {
 	m.ctrl.T.Helper()
@@ -913,6 +1239,49 @@ func (mr *MockQuestStorageMockRecorder) GetQuests(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuests", reflect.TypeOf((*MockQuestStorage)(nil).GetQuests), arg0, arg1)
 }
 
+// GetRunningQuests mocks base method.
+func (m *MockQuestStorage) GetRunningQuests(arg0 context.Context, arg1 *storage.GetRunningQuestsRequest) ([]storage.ID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunningQuests", arg0, arg1)
+	ret0, _ := ret[0].([]storage.ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunningQuests indicates an expected call of GetRunningQuests.
+func (mr *MockQuestStorageMockRecorder) GetRunningQuests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningQuests", reflect.TypeOf((*MockQuestStorage)(nil).GetRunningQuests), arg0, arg1)
+}
+
+// Notify mocks base method.
+func (m *MockQuestStorage) Notify(arg0 context.Context, arg1 *storage.NotifyRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockQuestStorageMockRecorder) Notify(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockQuestStorage)(nil).Notify), arg0, arg1)
+}
+
+// RevealLeaderboard mocks base method.
+func (m *MockQuestStorage) RevealLeaderboard(arg0 context.Context, arg1 *storage.RevealLeaderboardRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevealLeaderboard", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevealLeaderboard indicates an expected call of RevealLeaderboard.
+func (mr *MockQuestStorageMockRecorder) RevealLeaderboard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevealLeaderboard", reflect.TypeOf((*MockQuestStorage)(nil).RevealLeaderboard), arg0, arg1)
+}
+
 // UpdateQuest mocks base method.
 func (m *MockQuestStorage) UpdateQuest(arg0 context.Context, arg1 *storage.UpdateQuestRequest) (*storage.Quest, error) {
 	m.ctrl.T.Helper()
@@ -1388,6 +1757,21 @@ func (mr *MockAnswerHintStorageMockRecorder) CreateAnswerTry(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnswerTry", reflect.TypeOf((*MockAnswerHintStorage)(nil).CreateAnswerTry), arg0, arg1)
 }
 
+// CreateBonusCode mocks base method.
+func (m *MockAnswerHintStorage) CreateBonusCode(arg0 context.Context, arg1 *storage.CreateBonusCodeRequest) (*storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(*storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBonusCode indicates an expected call of CreateBonusCode.
+func (mr *MockAnswerHintStorageMockRecorder) CreateBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBonusCode", reflect.TypeOf((*MockAnswerHintStorage)(nil).CreateBonusCode), arg0, arg1)
+}
+
 // CreatePenalty mocks base method.
 func (m *MockAnswerHintStorage) CreatePenalty(arg0 context.Context, arg1 *storage.CreatePenaltyRequest) error {
 	m.ctrl.T.Helper()
@@ -1402,6 +1786,20 @@ func (mr *MockAnswerHintStorageMockRecorder) CreatePenalty(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePenalty", reflect.TypeOf((*MockAnswerHintStorage)(nil).CreatePenalty), arg0, arg1)
 }
 
+// DeleteBonusCode mocks base method.
+func (m *MockAnswerHintStorage) DeleteBonusCode(arg0 context.Context, arg1 *storage.DeleteBonusCodeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBonusCode indicates an expected call of DeleteBonusCode.
+func (mr *MockAnswerHintStorageMockRecorder) DeleteBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBonusCode", reflect.TypeOf((*MockAnswerHintStorage)(nil).DeleteBonusCode), arg0, arg1)
+}
+
 // GetAcceptedTasks mocks base method.
 func (m *MockAnswerHintStorage) GetAcceptedTasks(arg0 context.Context, arg1 *storage.GetAcceptedTasksRequest) (storage.AcceptedTasks, error) {
 	m.ctrl.T.Helper()
@@ -1417,6 +1815,21 @@ func (mr *MockAnswerHintStorageMockRecorder) GetAcceptedTasks(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAcceptedTasks", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetAcceptedTasks), arg0, arg1)
 }
 
+// GetAdjustments mocks base method.
+func (m *MockAnswerHintStorage) GetAdjustments(arg0 context.Context, arg1 *storage.GetAdjustmentsRequest) ([]storage.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjustments", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjustments indicates an expected call of GetAdjustments.
+func (mr *MockAnswerHintStorageMockRecorder) GetAdjustments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjustments", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetAdjustments), arg0, arg1)
+}
+
 // GetAnswerTries mocks base method.
 func (m *MockAnswerHintStorage) GetAnswerTries(arg0 context.Context, arg1 *storage.GetAnswerTriesRequest, arg2 ...storage.FilteringOption) (*storage.AnswerLogRecords, error) {
 	m.ctrl.T.Helper()
@@ -1437,6 +1850,81 @@ func (mr *MockAnswerHintStorageMockRecorder) GetAnswerTries(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTries", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetAnswerTries), varargs...)
 }
 
+// GetAnswerTry mocks base method.
+func (m *MockAnswerHintStorage) GetAnswerTry(arg0 context.Context, arg1 *storage.GetAnswerTryRequest) (*storage.AnswerTry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswerTry", arg0, arg1)
+	ret0, _ := ret[0].(*storage.AnswerTry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswerTry indicates an expected call of GetAnswerTry.
+func (mr *MockAnswerHintStorageMockRecorder) GetAnswerTry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTry", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetAnswerTry), arg0, arg1)
+}
+
+// GetAnswerTryStats mocks base method.
+func (m *MockAnswerHintStorage) GetAnswerTryStats(arg0 context.Context, arg1 *storage.GetAnswerTryStatsRequest) (*storage.AnswerTryStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswerTryStats", arg0, arg1)
+	ret0, _ := ret[0].(*storage.AnswerTryStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswerTryStats indicates an expected call of GetAnswerTryStats.
+func (mr *MockAnswerHintStorageMockRecorder) GetAnswerTryStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTryStats", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetAnswerTryStats), arg0, arg1)
+}
+
+// GetBonusCode mocks base method.
+func (m *MockAnswerHintStorage) GetBonusCode(arg0 context.Context, arg1 *storage.GetBonusCodeRequest) (*storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(*storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCode indicates an expected call of GetBonusCode.
+func (mr *MockAnswerHintStorageMockRecorder) GetBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCode", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetBonusCode), arg0, arg1)
+}
+
+// GetBonusCodeUses mocks base method.
+func (m *MockAnswerHintStorage) GetBonusCodeUses(arg0 context.Context, arg1 *storage.GetBonusCodeUsesRequest) (storage.TeamBonusCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCodeUses", arg0, arg1)
+	ret0, _ := ret[0].(storage.TeamBonusCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCodeUses indicates an expected call of GetBonusCodeUses.
+func (mr *MockAnswerHintStorageMockRecorder) GetBonusCodeUses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCodeUses", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetBonusCodeUses), arg0, arg1)
+}
+
+// GetBonusCodes mocks base method.
+func (m *MockAnswerHintStorage) GetBonusCodes(arg0 context.Context, arg1 *storage.GetBonusCodesRequest) ([]storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCodes", arg0, arg1)
+	ret0, _ := ret[0].([]storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCodes indicates an expected call of GetBonusCodes.
+func (mr *MockAnswerHintStorageMockRecorder) GetBonusCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCodes", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetBonusCodes), arg0, arg1)
+}
+
 // GetHintTakes mocks base method.
 func (m *MockAnswerHintStorage) GetHintTakes(arg0 context.Context, arg1 *storage.GetHintTakesRequest) (storage.HintTakes, error) {
 	m.ctrl.T.Helper()
@@ -1467,6 +1955,36 @@ func (mr *MockAnswerHintStorageMockRecorder) GetPenalties(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPenalties", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetPenalties), arg0, arg1)
 }
 
+// GetReviewAnswerTries mocks base method.
+func (m *MockAnswerHintStorage) GetReviewAnswerTries(arg0 context.Context, arg1 *storage.GetReviewAnswerTriesRequest) ([]storage.AnswerTry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].([]storage.AnswerTry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewAnswerTries indicates an expected call of GetReviewAnswerTries.
+func (mr *MockAnswerHintStorageMockRecorder) GetReviewAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewAnswerTries", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetReviewAnswerTries), arg0, arg1)
+}
+
+// GetScoreHistory mocks base method.
+func (m *MockAnswerHintStorage) GetScoreHistory(arg0 context.Context, arg1 *storage.GetScoreHistoryRequest) (storage.ScoreHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScoreHistory", arg0, arg1)
+	ret0, _ := ret[0].(storage.ScoreHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScoreHistory indicates an expected call of GetScoreHistory.
+func (mr *MockAnswerHintStorageMockRecorder) GetScoreHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreHistory", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetScoreHistory), arg0, arg1)
+}
+
 // GetScoreResults mocks base method.
 func (m *MockAnswerHintStorage) GetScoreResults(arg0 context.Context, arg1 *storage.GetResultsRequest) (storage.ScoreResults, error) {
 	m.ctrl.T.Helper()
@@ -1482,6 +2000,96 @@ func (mr *MockAnswerHintStorageMockRecorder) GetScoreResults(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreResults", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetScoreResults), arg0, arg1)
 }
 
+// GetTaskSolveCount mocks base method.
+func (m *MockAnswerHintStorage) GetTaskSolveCount(arg0 context.Context, arg1 *storage.GetTaskSolveCountRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskSolveCount", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskSolveCount indicates an expected call of GetTaskSolveCount.
+func (mr *MockAnswerHintStorageMockRecorder) GetTaskSolveCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskSolveCount", reflect.TypeOf((*MockAnswerHintStorage)(nil).GetTaskSolveCount), arg0, arg1)
+}
+
+// IterAnswerTries mocks base method.
+func (m *MockAnswerHintStorage) IterAnswerTries(arg0 context.Context, arg1 *storage.GetAnswerTriesRequest, arg2 func(*storage.AnswerLog) error, arg3 ...storage.FilteringOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IterAnswerTries", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterAnswerTries indicates an expected call of IterAnswerTries.
+func (mr *MockAnswerHintStorageMockRecorder) IterAnswerTries(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterAnswerTries", reflect.TypeOf((*MockAnswerHintStorage)(nil).IterAnswerTries), varargs...)
+}
+
+// LockAnswerTries mocks base method.
+func (m *MockAnswerHintStorage) LockAnswerTries(arg0 context.Context, arg1 *storage.LockAnswerTriesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAnswerTries indicates an expected call of LockAnswerTries.
+func (mr *MockAnswerHintStorageMockRecorder) LockAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAnswerTries", reflect.TypeOf((*MockAnswerHintStorage)(nil).LockAnswerTries), arg0, arg1)
+}
+
+// RejectPendingAnswerTries mocks base method.
+func (m *MockAnswerHintStorage) RejectPendingAnswerTries(arg0 context.Context, arg1 *storage.RejectPendingAnswerTriesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectPendingAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectPendingAnswerTries indicates an expected call of RejectPendingAnswerTries.
+func (mr *MockAnswerHintStorageMockRecorder) RejectPendingAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectPendingAnswerTries", reflect.TypeOf((*MockAnswerHintStorage)(nil).RejectPendingAnswerTries), arg0, arg1)
+}
+
+// ReviewAnswerTry mocks base method.
+func (m *MockAnswerHintStorage) ReviewAnswerTry(arg0 context.Context, arg1 *storage.ReviewAnswerTryRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewAnswerTry", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewAnswerTry indicates an expected call of ReviewAnswerTry.
+func (mr *MockAnswerHintStorageMockRecorder) ReviewAnswerTry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewAnswerTry", reflect.TypeOf((*MockAnswerHintStorage)(nil).ReviewAnswerTry), arg0, arg1)
+}
+
+// RevokeAdjustment mocks base method.
+func (m *MockAnswerHintStorage) RevokeAdjustment(arg0 context.Context, arg1 *storage.RevokeAdjustmentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAdjustment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAdjustment indicates an expected call of RevokeAdjustment.
+func (mr *MockAnswerHintStorageMockRecorder) RevokeAdjustment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAdjustment", reflect.TypeOf((*MockAnswerHintStorage)(nil).RevokeAdjustment), arg0, arg1)
+}
+
 // TakeHint mocks base method.
 func (m *MockAnswerHintStorage) TakeHint(arg0 context.Context, arg1 *storage.TakeHintRequest) (*storage.Hint, error) {
 	m.ctrl.T.Helper()
@@ -1497,6 +2105,20 @@ func (mr *MockAnswerHintStorageMockRecorder) TakeHint(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeHint", reflect.TypeOf((*MockAnswerHintStorage)(nil).TakeHint), arg0, arg1)
 }
 
+// UseBonusCode mocks base method.
+func (m *MockAnswerHintStorage) UseBonusCode(arg0 context.Context, arg1 *storage.UseBonusCodeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseBonusCode indicates an expected call of UseBonusCode.
+func (mr *MockAnswerHintStorageMockRecorder) UseBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseBonusCode", reflect.TypeOf((*MockAnswerHintStorage)(nil).UseBonusCode), arg0, arg1)
+}
+
 // MockHintStorage is a mock of HintStorage interface.
 type MockHintStorage struct {
 	ctrl     *gomock.Controller
@@ -1622,6 +2244,66 @@ func (mr *MockAnswerStorageMockRecorder) GetAnswerTries(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTries", reflect.TypeOf((*MockAnswerStorage)(nil).GetAnswerTries), varargs...)
 }
 
+// GetAnswerTry mocks base method.
+func (m *MockAnswerStorage) GetAnswerTry(arg0 context.Context, arg1 *storage.GetAnswerTryRequest) (*storage.AnswerTry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswerTry", arg0, arg1)
+	ret0, _ := ret[0].(*storage.AnswerTry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswerTry indicates an expected call of GetAnswerTry.
+func (mr *MockAnswerStorageMockRecorder) GetAnswerTry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTry", reflect.TypeOf((*MockAnswerStorage)(nil).GetAnswerTry), arg0, arg1)
+}
+
+// GetAnswerTryStats mocks base method.
+func (m *MockAnswerStorage) GetAnswerTryStats(arg0 context.Context, arg1 *storage.GetAnswerTryStatsRequest) (*storage.AnswerTryStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswerTryStats", arg0, arg1)
+	ret0, _ := ret[0].(*storage.AnswerTryStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswerTryStats indicates an expected call of GetAnswerTryStats.
+func (mr *MockAnswerStorageMockRecorder) GetAnswerTryStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswerTryStats", reflect.TypeOf((*MockAnswerStorage)(nil).GetAnswerTryStats), arg0, arg1)
+}
+
+// GetReviewAnswerTries mocks base method.
+func (m *MockAnswerStorage) GetReviewAnswerTries(arg0 context.Context, arg1 *storage.GetReviewAnswerTriesRequest) ([]storage.AnswerTry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].([]storage.AnswerTry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewAnswerTries indicates an expected call of GetReviewAnswerTries.
+func (mr *MockAnswerStorageMockRecorder) GetReviewAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewAnswerTries", reflect.TypeOf((*MockAnswerStorage)(nil).GetReviewAnswerTries), arg0, arg1)
+}
+
+// GetScoreHistory mocks base method.
+func (m *MockAnswerStorage) GetScoreHistory(arg0 context.Context, arg1 *storage.GetScoreHistoryRequest) (storage.ScoreHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScoreHistory", arg0, arg1)
+	ret0, _ := ret[0].(storage.ScoreHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScoreHistory indicates an expected call of GetScoreHistory.
+func (mr *MockAnswerStorageMockRecorder) GetScoreHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreHistory", reflect.TypeOf((*MockAnswerStorage)(nil).GetScoreHistory), arg0, arg1)
+}
+
 // GetScoreResults mocks base method.
 func (m *MockAnswerStorage) GetScoreResults(arg0 context.Context, arg1 *storage.GetResultsRequest) (storage.ScoreResults, error) {
 	m.ctrl.T.Helper()
@@ -1637,6 +2319,82 @@ func (mr *MockAnswerStorageMockRecorder) GetScoreResults(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScoreResults", reflect.TypeOf((*MockAnswerStorage)(nil).GetScoreResults), arg0, arg1)
 }
 
+// GetTaskSolveCount mocks base method.
+func (m *MockAnswerStorage) GetTaskSolveCount(arg0 context.Context, arg1 *storage.GetTaskSolveCountRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskSolveCount", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskSolveCount indicates an expected call of GetTaskSolveCount.
+func (mr *MockAnswerStorageMockRecorder) GetTaskSolveCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskSolveCount", reflect.TypeOf((*MockAnswerStorage)(nil).GetTaskSolveCount), arg0, arg1)
+}
+
+// IterAnswerTries mocks base method.
+func (m *MockAnswerStorage) IterAnswerTries(arg0 context.Context, arg1 *storage.GetAnswerTriesRequest, arg2 func(*storage.AnswerLog) error, arg3 ...storage.FilteringOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "IterAnswerTries", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterAnswerTries indicates an expected call of IterAnswerTries.
+func (mr *MockAnswerStorageMockRecorder) IterAnswerTries(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterAnswerTries", reflect.TypeOf((*MockAnswerStorage)(nil).IterAnswerTries), varargs...)
+}
+
+// LockAnswerTries mocks base method.
+func (m *MockAnswerStorage) LockAnswerTries(arg0 context.Context, arg1 *storage.LockAnswerTriesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAnswerTries indicates an expected call of LockAnswerTries.
+func (mr *MockAnswerStorageMockRecorder) LockAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAnswerTries", reflect.TypeOf((*MockAnswerStorage)(nil).LockAnswerTries), arg0, arg1)
+}
+
+// RejectPendingAnswerTries mocks base method.
+func (m *MockAnswerStorage) RejectPendingAnswerTries(arg0 context.Context, arg1 *storage.RejectPendingAnswerTriesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectPendingAnswerTries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectPendingAnswerTries indicates an expected call of RejectPendingAnswerTries.
+func (mr *MockAnswerStorageMockRecorder) RejectPendingAnswerTries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectPendingAnswerTries", reflect.TypeOf((*MockAnswerStorage)(nil).RejectPendingAnswerTries), arg0, arg1)
+}
+
+// ReviewAnswerTry mocks base method.
+func (m *MockAnswerStorage) ReviewAnswerTry(arg0 context.Context, arg1 *storage.ReviewAnswerTryRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewAnswerTry", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewAnswerTry indicates an expected call of ReviewAnswerTry.
+func (mr *MockAnswerStorageMockRecorder) ReviewAnswerTry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewAnswerTry", reflect.TypeOf((*MockAnswerStorage)(nil).ReviewAnswerTry), arg0, arg1)
+}
+
 // MockPenaltyStorage is a mock of PenaltyStorage interface.
 type MockPenaltyStorage struct {
 	ctrl     *gomock.Controller
@@ -1674,6 +2432,21 @@ func (mr *MockPenaltyStorageMockRecorder) CreatePenalty(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePenalty", reflect.TypeOf((*MockPenaltyStorage)(nil).CreatePenalty), arg0, arg1)
 }
 
+// GetAdjustments mocks base method.
+func (m *MockPenaltyStorage) GetAdjustments(arg0 context.Context, arg1 *storage.GetAdjustmentsRequest) ([]storage.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjustments", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjustments indicates an expected call of GetAdjustments.
+func (mr *MockPenaltyStorageMockRecorder) GetAdjustments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjustments", reflect.TypeOf((*MockPenaltyStorage)(nil).GetAdjustments), arg0, arg1)
+}
+
 // GetPenalties mocks base method.
 func (m *MockPenaltyStorage) GetPenalties(arg0 context.Context, arg1 *storage.GetPenaltiesRequest) (storage.TeamPenalties, error) {
 	m.ctrl.T.Helper()
@@ -1689,6 +2462,131 @@ func (mr *MockPenaltyStorageMockRecorder) GetPenalties(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPenalties", reflect.TypeOf((*MockPenaltyStorage)(nil).GetPenalties), arg0, arg1)
 }
 
+// RevokeAdjustment mocks base method.
+func (m *MockPenaltyStorage) RevokeAdjustment(arg0 context.Context, arg1 *storage.RevokeAdjustmentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAdjustment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAdjustment indicates an expected call of RevokeAdjustment.
+func (mr *MockPenaltyStorageMockRecorder) RevokeAdjustment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAdjustment", reflect.TypeOf((*MockPenaltyStorage)(nil).RevokeAdjustment), arg0, arg1)
+}
+
+// MockBonusCodeStorage is a mock of BonusCodeStorage interface.
+type MockBonusCodeStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBonusCodeStorageMockRecorder
+}
+
+// MockBonusCodeStorageMockRecorder is the mock recorder for MockBonusCodeStorage.
+type MockBonusCodeStorageMockRecorder struct {
+	mock *MockBonusCodeStorage
+}
+
+// NewMockBonusCodeStorage creates a new mock instance.
+func NewMockBonusCodeStorage(ctrl *gomock.Controller) *MockBonusCodeStorage {
+	mock := &MockBonusCodeStorage{ctrl: ctrl}
+	mock.recorder = &MockBonusCodeStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBonusCodeStorage) EXPECT() *MockBonusCodeStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateBonusCode mocks base method.
+func (m *MockBonusCodeStorage) CreateBonusCode(arg0 context.Context, arg1 *storage.CreateBonusCodeRequest) (*storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(*storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBonusCode indicates an expected call of CreateBonusCode.
+func (mr *MockBonusCodeStorageMockRecorder) CreateBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBonusCode", reflect.TypeOf((*MockBonusCodeStorage)(nil).CreateBonusCode), arg0, arg1)
+}
+
+// DeleteBonusCode mocks base method.
+func (m *MockBonusCodeStorage) DeleteBonusCode(arg0 context.Context, arg1 *storage.DeleteBonusCodeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBonusCode indicates an expected call of DeleteBonusCode.
+func (mr *MockBonusCodeStorageMockRecorder) DeleteBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBonusCode", reflect.TypeOf((*MockBonusCodeStorage)(nil).DeleteBonusCode), arg0, arg1)
+}
+
+// GetBonusCode mocks base method.
+func (m *MockBonusCodeStorage) GetBonusCode(arg0 context.Context, arg1 *storage.GetBonusCodeRequest) (*storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(*storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCode indicates an expected call of GetBonusCode.
+func (mr *MockBonusCodeStorageMockRecorder) GetBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCode", reflect.TypeOf((*MockBonusCodeStorage)(nil).GetBonusCode), arg0, arg1)
+}
+
+// GetBonusCodeUses mocks base method.
+func (m *MockBonusCodeStorage) GetBonusCodeUses(arg0 context.Context, arg1 *storage.GetBonusCodeUsesRequest) (storage.TeamBonusCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCodeUses", arg0, arg1)
+	ret0, _ := ret[0].(storage.TeamBonusCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCodeUses indicates an expected call of GetBonusCodeUses.
+func (mr *MockBonusCodeStorageMockRecorder) GetBonusCodeUses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCodeUses", reflect.TypeOf((*MockBonusCodeStorage)(nil).GetBonusCodeUses), arg0, arg1)
+}
+
+// GetBonusCodes mocks base method.
+func (m *MockBonusCodeStorage) GetBonusCodes(arg0 context.Context, arg1 *storage.GetBonusCodesRequest) ([]storage.BonusCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBonusCodes", arg0, arg1)
+	ret0, _ := ret[0].([]storage.BonusCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBonusCodes indicates an expected call of GetBonusCodes.
+func (mr *MockBonusCodeStorageMockRecorder) GetBonusCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBonusCodes", reflect.TypeOf((*MockBonusCodeStorage)(nil).GetBonusCodes), arg0, arg1)
+}
+
+// UseBonusCode mocks base method.
+func (m *MockBonusCodeStorage) UseBonusCode(arg0 context.Context, arg1 *storage.UseBonusCodeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseBonusCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseBonusCode indicates an expected call of UseBonusCode.
+func (mr *MockBonusCodeStorageMockRecorder) UseBonusCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseBonusCode", reflect.TypeOf((*MockBonusCodeStorage)(nil).UseBonusCode), arg0, arg1)
+}
+
 // MockTeamInfoStorage is a mock of TeamInfoStorage interface.
 type MockTeamInfoStorage struct {
 	ctrl     *gomock.Controller
@@ -28,6 +28,7 @@ var (
 	ErrNotFound        = xerrors.NewSentinel("not found")
 	ErrValidation      = xerrors.NewSentinel("validation error")
 	ErrTeamAlreadyFull = xerrors.NewSentinel("team already has maximum amount of members")
+	ErrUsesExhausted   = xerrors.NewSentinel("bonus code has no uses left")
 )
 
 const (
@@ -42,6 +43,49 @@ const (
 	VerificationManual VerificationType = "manual"
 )
 
+type TaskType string
+
+const (
+	TaskTypeText   TaskType = "text"
+	TaskTypeChoice TaskType = "choice"
+	// TaskTypeFile is answered with uploaded file, which is verified manually
+	TaskTypeFile TaskType = "file"
+	// TaskTypeLocation is answered with team coordinates, which have to be within radius of task location
+	TaskTypeLocation TaskType = "location"
+	// TaskTypeParts has several answer slots, which are solved one by one and rewarded with their share of task reward
+	TaskTypeParts TaskType = "parts"
+)
+
+type MatcherType string
+
+const (
+	MatcherExact             MatcherType = "exact"
+	MatcherRegex             MatcherType = "regex"
+	MatcherNumeric           MatcherType = "numeric"
+	MatcherIgnorePunctuation MatcherType = "ignore_punctuation"
+	MatcherNormalized        MatcherType = "normalized"
+	MatcherUnordered         MatcherType = "unordered"
+	MatcherFuzzy             MatcherType = "fuzzy"
+)
+
+// AnswerMatcher selects how answers of task are compared with correct answers. Exact type is the default one,
+// so setting it by update request clears previous matcher the same way as reset of matcher field does
+type AnswerMatcher struct {
+	Type MatcherType `json:"type" enums:"exact,regex,numeric,ignore_punctuation,normalized,unordered,fuzzy"`
+	// Tolerance is maximum absolute difference for numeric matcher
+	Tolerance *float64 `json:"tolerance,omitempty"`
+	// Threshold is maximum Levenshtein distance for fuzzy matcher
+	Threshold *int `json:"threshold,omitempty"`
+}
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "PENDING"
+	ReviewStatusAccepted ReviewStatus = "ACCEPTED"
+	ReviewStatusRejected ReviewStatus = "REJECTED"
+)
+
 type QuestStatus int
 
 const (
@@ -140,6 +184,26 @@ type Quest struct {
 	RegistrationType     RegistrationType `json:"registration_type,omitempty" enums:"AUTO,VERIFY"`
 	QuestType            QuestType        `json:"quest_type,omitempty" enums:"ASSAULT,LINEAR"`
 	FeedbackLink         *string          `json:"feedback_link,omitempty"`
+	AnswerLimits         *AnswerLimits    `json:"answer_limits,omitempty"`
+	// LeaderboardFreeze is a period before FinishTime during which teams see leaderboard as of its beginning
+	LeaderboardFreeze   *Duration `json:"leaderboard_freeze,omitempty" swaggertype:"integer" example:"1800"`
+	LeaderboardRevealed bool      `json:"leaderboard_revealed,omitempty"`
+	// DefaultHintPenalty is inherited by new hints created without penalty
+	DefaultHintPenalty *PenaltyOneOf `json:"default_hint_penalty,omitempty"`
+	// AutoFinish makes quest finished at FinishTime without waiting for creator to finish it
+	AutoFinish bool `json:"auto_finish,omitempty"`
+}
+
+// defaultHintPercent is a penalty of hint when neither hint nor its quest set one
+const defaultHintPercent = 20
+
+// HintPenalty returns penalty inherited by new hints of the quest
+func (q *Quest) HintPenalty() PenaltyOneOf {
+	if q.DefaultHintPenalty != nil {
+		return *q.DefaultHintPenalty
+	}
+	percent := defaultHintPercent
+	return PenaltyOneOf{percent: &percent}
 }
 
 type GetQuestType int
@@ -246,17 +310,58 @@ type TaskGroup struct {
 	Tasks        []Task             `json:"tasks"`
 	HasTimeLimit bool               `json:"has_time_limit,omitempty"`
 	TimeLimit    *Duration          `json:"time_limit,omitempty" swaggertype:"integer" example:"300"`
+	Transitions  []Transition       `json:"transitions,omitempty"`
+	AllowSkip    bool               `json:"allow_skip,omitempty"`
+	SkipPenalty  *PenaltyOneOf      `json:"skip_penalty,omitempty"`
+	Requires     *Prerequisites     `json:"requires,omitempty"`
+	Location     *Location          `json:"location,omitempty"`
 	TeamInfo     *TaskGroupTeamInfo `json:"team_info,omitempty"`
 }
 
+// Transition leads team of linear quest from task group to NextGroupID instead of the next group by order.
+// Transition with TaskID is taken as soon as the task is solved with one of Answers, or with any answer if Answers are empty.
+// Transition without TaskID is default one and is taken when group is closed without matching other transitions.
+type Transition struct {
+	NextGroupID ID       `json:"next_group_id"`
+	TaskID      ID       `json:"task_id,omitempty"`
+	Answers     []string `json:"answers,omitempty"`
+}
+
+// Prerequisites lock task or task group of assault quest until team meets all the conditions
+type Prerequisites struct {
+	// SolvedTasks have to be solved by team
+	SolvedTasks []ID `json:"solved_tasks,omitempty"`
+	// SolvedInGroups require minimal number of solved tasks in each of the groups
+	SolvedInGroups []SolvedInGroup `json:"solved_in_groups,omitempty"`
+}
+
+type SolvedInGroup struct {
+	TaskGroupID ID  `json:"task_group_id"`
+	Count       int `json:"count"`
+}
+
+func (p *Prerequisites) Empty() bool {
+	return p == nil || len(p.SolvedTasks) == 0 && len(p.SolvedInGroups) == 0
+}
+
 type Task struct {
-	ID             ID         `json:"id"`
-	OrderIdx       int        `json:"order_idx"`
-	Group          *TaskGroup `json:"-"`
-	Name           string     `json:"name"`
-	Question       string     `json:"question"`
-	Reward         int        `json:"reward"`
-	CorrectAnswers []string   `json:"correct_answers"`
+	ID             ID             `json:"id"`
+	OrderIdx       int            `json:"order_idx"`
+	Group          *TaskGroup     `json:"-"`
+	Name           string         `json:"name"`
+	Question       string         `json:"question"`
+	Reward         int            `json:"reward"`
+	CorrectAnswers []string       `json:"correct_answers"`
+	Matcher        *AnswerMatcher `json:"matcher,omitempty"`
+	AnswerLimits   *AnswerLimits  `json:"answer_limits,omitempty"`
+	Scoring        *Scoring       `json:"scoring,omitempty"`
+	Requires       *Prerequisites `json:"requires,omitempty"`
+	Type           TaskType       `json:"type,omitempty" enums:"text,choice,file,location,parts"`
+	Choice         *Choice        `json:"choice,omitempty"`
+	Location       *Location      `json:"location,omitempty"`
+	Parts          []TaskPart     `json:"parts,omitempty"`
+	// OrderedHints requires hints to be taken strictly one after another
+	OrderedHints bool `json:"ordered_hints,omitempty"`
 	// Deprecated
 	Verification    VerificationType `json:"verification_type" example:"deprecated"`
 	VerificationNew VerificationType `json:"verification" enums:"auto,manual"`
@@ -269,19 +374,25 @@ type Task struct {
 }
 
 type AnswerTry struct {
-	Team       *Team
-	User       *User
-	TaskID     ID
-	Answer     string
-	AnswerTime *time.Time
+	ID           int64
+	Team         *Team
+	User         *User
+	TaskGroup    *TaskGroup
+	Task         *Task
+	Answer       string
+	File         *AnswerFile
+	AnswerTime   time.Time
+	Accepted     bool
+	Score        int
+	ReviewStatus ReviewStatus
 }
 
-func init() {
-	DefaultPenalty, _ = NewPercentagePenalty(20)
+// AnswerFile is a file uploaded by team with answer. Its content is kept in blob store
+type AnswerFile struct {
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
 }
 
-var DefaultPenalty PenaltyOneOf
-
 type PenaltyOneOf struct {
 	percent *int
 	score   *int
@@ -390,12 +501,85 @@ func (p *PenaltyOneOf) MarshalJSON() ([]byte, error) {
 	return json.Marshal(penalty)
 }
 
+type DecayType string
+
+const (
+	DecayLinear DecayType = "linear"
+	DecayStep   DecayType = "step"
+)
+
+type DecayStart string
+
+const (
+	DecayFromQuestStart   DecayStart = "quest_start"
+	DecayFromGroupOpening DecayStart = "group_opening"
+)
+
+// RewardDecay decreases task reward with time passed since decay start.
+// Linear decay takes points proportionally to passed time, step decay takes Amount points once per full Interval.
+type RewardDecay struct {
+	Type DecayType  `json:"type" enums:"linear,step"`
+	From DecayStart `json:"from,omitempty" enums:"quest_start,group_opening"`
+	// Interval in seconds during which reward decreases by Amount points
+	Interval  Duration `json:"interval" swaggertype:"integer" example:"600"`
+	Amount    int      `json:"amount"`
+	MinReward int      `json:"min_reward,omitempty"`
+}
+
+// Scoring contains optional modifiers of task reward
+type Scoring struct {
+	Decay *RewardDecay `json:"decay,omitempty"`
+	// FirstSolveBonuses are given to first teams solving the task: i-th value goes to (i+1)-th team
+	FirstSolveBonuses []int `json:"first_solve_bonuses,omitempty"`
+}
+
+// Choice contains options of multiple-choice task. Number of submissions is limited with AnswerLimits.MaxAttempts
+type Choice struct {
+	Options []string `json:"options"`
+	// Correct contains indexes of correct options
+	Correct  []int `json:"correct"`
+	Multiple bool  `json:"multiple,omitempty"`
+	// PartialScoring gives share of reward for incomplete selection of multiple options:
+	// every correct option adds its share, while every wrong one takes it back
+	PartialScoring bool `json:"partial_scoring,omitempty"`
+}
+
+// TaskPart is an answer slot of multi-part task. Answers of all the parts are checked with task matcher
+type TaskPart struct {
+	Name           string   `json:"name"`
+	CorrectAnswers []string `json:"correct_answers"`
+	// Share is percent of task reward given for the part
+	Share int `json:"share"`
+}
+
+// Location is a point on the map with radius in meters, within which team is considered to be there
+type Location struct {
+	Lat    float64 `json:"lat" example:"55.7539"`
+	Lon    float64 `json:"lon" example:"37.6208"`
+	Radius float64 `json:"radius" example:"50"`
+}
+
+// AnswerLimits restricts answer tries of a team for a single task.
+// Limits set on task override quest defaults field by field.
+type AnswerLimits struct {
+	// MaxAttempts is a number of wrong tries, which include ones rejected on review, but not ones pending review
+	MaxAttempts *int `json:"max_attempts,omitempty"`
+	// CooldownAttempts is a number of wrong tries after which team has to wait for Cooldown
+	CooldownAttempts   *int          `json:"cooldown_attempts,omitempty"`
+	Cooldown           *Duration     `json:"cooldown,omitempty" swaggertype:"integer" example:"60"`
+	WrongAnswerPenalty *PenaltyOneOf `json:"wrong_answer_penalty,omitempty"`
+}
+
 type Hint struct {
 	TaskID  ID           `json:"-"`
 	Index   int          `json:"index"`
 	Name    *string      `json:"name,omitempty"`
 	Text    string       `json:"text,omitempty"`
 	Penalty PenaltyOneOf `json:"penalty"`
+	// OpensAfter is delay after the task is opened for team, when the hint is revealed automatically
+	OpensAfter *Duration `json:"opens_after,omitempty" swaggertype:"integer" example:"600"`
+	// AutoPenalty is taken for automatically revealed hint instead of Penalty. Such hint is free when it is not set
+	AutoPenalty *PenaltyOneOf `json:"auto_penalty,omitempty"`
 }
 
 type HintTake struct {
@@ -409,10 +593,21 @@ type HintTakes map[ID][]HintTake
 type AcceptedTask struct {
 	Text  string
 	Score int
+	// Parts contain answers of solved parts of multi-part task by part index
+	Parts map[int]string
+	// Partial is set while some parts of multi-part task are not solved yet
+	// or when choice task is answered partially correct
+	Partial bool
 }
 
 type AcceptedTasks map[ID]AcceptedTask
 
+// Solved reports whether task is accepted completely
+func (a AcceptedTasks) Solved(taskID ID) bool {
+	task, ok := a[taskID]
+	return ok && !task.Partial
+}
+
 type SingleTaskResult struct {
 	TeamID    ID
 	TeamName  string
@@ -422,19 +617,93 @@ type SingleTaskResult struct {
 	TaskName  string
 	Score     int
 	ScoreTime *time.Time
+	// Bonus and Decay are already included into Score
+	Bonus int
+	Decay int
+	// SolvedParts is number of solved parts of multi-part task
+	SolvedParts int
 }
 
 // ScoreResults [team_id] -> [task_id] -> Result
 type ScoreResults map[ID]map[ID]SingleTaskResult
 
+// PenaltySource tells where penalty comes from
+type PenaltySource string
+
+const (
+	// PenaltySourceLedger is an entry of team penalty ledger: manual penalty or bonus, or penalty for skipped task group
+	PenaltySourceLedger PenaltySource = "LEDGER"
+	// PenaltySourceWrongAnswer is a sum of penalties given for wrong answers to the task
+	PenaltySourceWrongAnswer PenaltySource = "WRONG_ANSWER"
+)
+
 type Penalty struct {
 	TeamID ID
 	Value  int
+	Source PenaltySource
+	// TaskID is set for penalties given for wrong answers
+	TaskID ID
+	// TaskGroupID is set for penalties given for skipped task groups
+	TaskGroupID ID
+}
+
+type ScorePoint struct {
+	Time  time.Time
+	Score int
 }
 
+// ScoreHistory [team_id] -> cumulative score points ordered by time
+type ScoreHistory map[ID][]ScorePoint
+
 // TeamPenalties [team_id] -> []Penalty
 type TeamPenalties map[ID][]Penalty
 
+// Adjustment is an entry of team penalty ledger. Positive Value is subtracted from team score, while negative one is a bonus
+type Adjustment struct {
+	ID     int64  `json:"id"`
+	TeamID ID     `json:"team_id"`
+	Value  int    `json:"value"`
+	Reason string `json:"reason,omitempty"`
+	// TaskID is an optional task the adjustment is given for
+	TaskID ID `json:"task_id,omitempty"`
+	// TaskGroupID is set for penalties given for skipped task groups
+	TaskGroupID ID `json:"task_group_id,omitempty"`
+	// Legacy is set for entry recorded by legacy penalty endpoint, which replaces it on the next call
+	Legacy bool `json:"legacy,omitempty"`
+	// Author is not set for penalties given automatically
+	Author     *User      `json:"author,omitempty"`
+	CreateTime time.Time  `json:"create_time"`
+	RevokeTime *time.Time `json:"revoke_time,omitempty"`
+	RevokedBy  *User      `json:"revoked_by,omitempty"`
+}
+
+func (a *Adjustment) Revoked() bool {
+	return a.RevokeTime != nil
+}
+
+// BonusCode is a string hidden around the quest, which any team can enter once to earn Value points
+type BonusCode struct {
+	ID    ID     `json:"id"`
+	Code  string `json:"code"`
+	Value int    `json:"value"`
+	// ValidFrom and ValidUntil limit period when code can be entered
+	ValidFrom  *time.Time `json:"valid_from,omitempty" example:"2024-04-14T14:00:00+05:00"`
+	ValidUntil *time.Time `json:"valid_until,omitempty" example:"2024-04-14T16:00:00+05:00"`
+	// MaxUses limits number of teams, which can enter the code
+	MaxUses *int `json:"max_uses,omitempty"`
+	Uses    int  `json:"uses"`
+}
+
+type BonusCodeUse struct {
+	TeamID  ID
+	CodeID  ID
+	Value   int
+	UseTime time.Time
+}
+
+// TeamBonusCodes [team_id] -> []BonusCodeUse
+type TeamBonusCodes map[ID][]BonusCodeUse
+
 type AnswerLog struct {
 	Team       *Team
 	User       *User
@@ -442,8 +711,18 @@ type AnswerLog struct {
 	Task       *Task
 	Accepted   bool
 	Answer     string
+	File       *AnswerFile
 	AnswerTime time.Time
 	Score      int
+	// BonusCode is set for bonus code entered by team, which is not an answer to any task
+	BonusCode bool
+}
+
+// AnswerTryStats describes wrong tries of team for task. Tries pending manual review are not counted,
+// while rejected ones are counted as wrong.
+type AnswerTryStats struct {
+	WrongTries  int
+	LastTryTime *time.Time
 }
 
 type AnswerLogRecords struct {
@@ -455,4 +734,6 @@ type AnswerLogRecords struct {
 type TaskGroupTeamInfo struct {
 	OpeningTime time.Time  `json:"opening_time"`
 	ClosingTime *time.Time `json:"closing_time,omitempty"`
+	// NextGroupID is set for closed groups when team took one of group transitions
+	NextGroupID *ID `json:"next_group_id,omitempty"`
 }
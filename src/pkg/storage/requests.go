@@ -48,6 +48,10 @@ type CreateQuestRequest struct {
 	RegistrationType     RegistrationType `json:"registration_type,omitempty" enums:"AUTO,VERIFY"`
 	QuestType            QuestType        `json:"quest_type,omitempty" enums:"ASSAULT,LINEAR"`
 	FeedbackLink         *string          `json:"feedback_link,omitempty"`
+	AnswerLimits         *AnswerLimits    `json:"answer_limits,omitempty"`
+	LeaderboardFreeze    *Duration        `json:"leaderboard_freeze,omitempty" swaggertype:"integer" example:"1800"`
+	DefaultHintPenalty   *PenaltyOneOf    `json:"default_hint_penalty,omitempty"`
+	AutoFinish           bool             `json:"auto_finish,omitempty"`
 }
 
 type GetQuestRequest struct {
@@ -82,6 +86,11 @@ type UpdateQuestRequest struct {
 	RegistrationType     RegistrationType `json:"registration_type,omitempty" enums:"AUTO,VERIFY"`
 	QuestType            QuestType        `json:"quest_type,omitempty" enums:"ASSAULT,LINEAR"`
 	FeedbackLink         *string          `json:"feedback_link,omitempty"`
+	AnswerLimits         *AnswerLimits    `json:"answer_limits,omitempty"`
+	// LeaderboardFreeze equal to zero turns freeze off
+	LeaderboardFreeze  *Duration     `json:"leaderboard_freeze,omitempty" swaggertype:"integer" example:"1800"`
+	DefaultHintPenalty *PenaltyOneOf `json:"default_hint_penalty,omitempty"`
+	AutoFinish         *bool         `json:"auto_finish,omitempty"`
 }
 
 type DeleteQuestRequest struct {
@@ -92,6 +101,28 @@ type FinishQuestRequest struct {
 	ID ID
 }
 
+type GetExpiredQuestsRequest struct {
+	// Now is a moment, before which finish time of quests has passed
+	Now time.Time
+}
+
+type GetRunningQuestsRequest struct {
+	Now time.Time
+	// QuestType limits quests to ones of given type when set
+	QuestType QuestType
+}
+
+type RevealLeaderboardRequest struct {
+	ID ID
+}
+
+// NotifyRequest sends payload to listeners of the channel. Inside a transaction
+// notification is sent only when it is committed.
+type NotifyRequest struct {
+	Channel string
+	Payload string
+}
+
 type CreateTeamRequest struct {
 	Name               string
 	QuestID            ID
@@ -161,6 +192,13 @@ type CreateTaskGroupRequest struct {
 	Sticky       bool                `json:"sticky,omitempty"`
 	HasTimeLimit bool                `json:"has_time_limit,omitempty"`
 	TimeLimit    *Duration           `json:"time_limit,omitempty"`
+	Transitions  []Transition        `json:"transitions,omitempty"`
+	AllowSkip    bool                `json:"allow_skip,omitempty"`
+	SkipPenalty  *PenaltyOneOf       `json:"skip_penalty,omitempty"`
+	Requires     *Prerequisites      `json:"requires,omitempty"`
+	Location     *Location           `json:"location,omitempty"`
+	// Key is temporary key of new group, which transitions of the same bulk request can use instead of its id
+	Key string `json:"key,omitempty"`
 }
 
 type TeamData struct {
@@ -181,6 +219,27 @@ type GetTaskGroupsRequest struct {
 	TeamData     *TeamData
 }
 
+// ResetField is a name of optional field of task or task group, which update request resets to its default value.
+// Field cannot be both set and reset by the same request
+type ResetField string
+
+const (
+	ResetQuestion       ResetField = "question"
+	ResetReward         ResetField = "reward"
+	ResetCorrectAnswers ResetField = "correct_answers"
+	// ResetMatcher returns task to exact matching of answers
+	ResetMatcher      ResetField = "matcher"
+	ResetAnswerLimits ResetField = "answer_limits"
+	ResetScoring      ResetField = "scoring"
+	ResetChoice       ResetField = "choice"
+	ResetLocation     ResetField = "location"
+	ResetParts        ResetField = "parts"
+	ResetPubTime      ResetField = "pub_time"
+	ResetMediaLinks   ResetField = "media_links"
+	ResetTimeLimit    ResetField = "time_limit"
+	ResetSkipPenalty  ResetField = "skip_penalty"
+)
+
 type UpdateTaskGroupRequest struct {
 	QuestID      ID                      `json:"-"`
 	ID           ID                      `json:"id"`
@@ -192,6 +251,13 @@ type UpdateTaskGroupRequest struct {
 	Sticky       *bool                   `json:"sticky,omitempty"`
 	HasTimeLimit *bool                   `json:"has_time_limit,omitempty"`
 	TimeLimit    *Duration               `json:"time_limit,omitempty"`
+	Transitions  *[]Transition           `json:"transitions,omitempty"`
+	AllowSkip    *bool                   `json:"allow_skip,omitempty"`
+	SkipPenalty  *PenaltyOneOf           `json:"skip_penalty,omitempty"`
+	Requires     *Prerequisites          `json:"requires,omitempty"`
+	Location     *Location               `json:"location,omitempty"`
+	// Reset lists optional fields, which are reset to their default values
+	Reset []ResetField `json:"reset,omitempty" enums:"pub_time,time_limit,skip_penalty,location"`
 }
 
 type DeleteTaskGroupRequest struct {
@@ -206,9 +272,11 @@ type TaskGroupsBulkUpdateRequest struct {
 }
 
 type CreateHintRequest struct {
-	Name    *string      `json:"name,omitempty"`
-	Text    string       `json:"text,omitempty"`
-	Penalty PenaltyOneOf `json:"penalty"`
+	Name        *string       `json:"name,omitempty"`
+	Text        string        `json:"text,omitempty"`
+	Penalty     PenaltyOneOf  `json:"penalty"`
+	OpensAfter  *Duration     `json:"opens_after,omitempty" swaggertype:"integer" example:"600"`
+	AutoPenalty *PenaltyOneOf `json:"auto_penalty,omitempty"`
 }
 
 type CreateTaskRequest struct {
@@ -218,11 +286,22 @@ type CreateTaskRequest struct {
 	Question       string              `json:"question"`
 	Reward         int                 `json:"reward"`
 	CorrectAnswers []string            `json:"correct_answers"`
+	Matcher        *AnswerMatcher      `json:"matcher,omitempty"`
+	AnswerLimits   *AnswerLimits       `json:"answer_limits,omitempty"`
+	Scoring        *Scoring            `json:"scoring,omitempty"`
+	Requires       *Prerequisites      `json:"requires,omitempty"`
+	Type           TaskType            `json:"type,omitempty" enums:"text,choice,file,location,parts"`
+	Choice         *Choice             `json:"choice,omitempty"`
+	Location       *Location           `json:"location,omitempty"`
+	Parts          []TaskPart          `json:"parts,omitempty"`
+	OrderedHints   bool                `json:"ordered_hints,omitempty"`
 	Verification   VerificationType    `json:"verification"`
 	Hints          []string            `json:"hints"`
 	FullHints      []CreateHintRequest `json:"hints_full"`
 	PubTime        *time.Time          `json:"pub_time"`
 	MediaLinks     []string            `json:"media_links,omitempty"`
+	// Key is temporary key of new task, which transitions of the same bulk request can use instead of its id
+	Key string `json:"key,omitempty"`
 	// Deprecated
 	MediaLink string `json:"media_link" example:"deprecated"`
 }
@@ -247,11 +326,22 @@ type UpdateTaskRequest struct {
 	Question       string               `json:"question"`
 	Reward         int                  `json:"reward"`
 	CorrectAnswers []string             `json:"correct_answers"`
+	Matcher        *AnswerMatcher       `json:"matcher,omitempty"`
+	AnswerLimits   *AnswerLimits        `json:"answer_limits,omitempty"`
+	Scoring        *Scoring             `json:"scoring,omitempty"`
+	Requires       *Prerequisites       `json:"requires,omitempty"`
+	Type           TaskType             `json:"type,omitempty" enums:"text,choice,file,location,parts"`
+	Choice         *Choice              `json:"choice,omitempty"`
+	Location       *Location            `json:"location,omitempty"`
+	Parts          []TaskPart           `json:"parts,omitempty"`
+	OrderedHints   *bool                `json:"ordered_hints,omitempty"`
 	Verification   VerificationType     `json:"verification"`
 	Hints          []string             `json:"hints"`
 	FullHints      *[]CreateHintRequest `json:"hints_full"`
 	PubTime        *time.Time           `json:"pub_time"`
 	MediaLinks     []string             `json:"media_links,omitempty"`
+	// Reset lists optional fields, which are reset to their default values
+	Reset []ResetField `json:"reset,omitempty" enums:"question,reward,correct_answers,matcher,answer_limits,scoring,choice,location,parts,pub_time,media_links"`
 	// Deprecated
 	MediaLink *string `json:"media_link" example:"deprecated"`
 }
@@ -286,27 +376,148 @@ type GetAcceptedTasksRequest struct {
 }
 
 type CreateAnswerTryRequest struct {
-	TeamID   ID
-	UserID   ID
-	TaskID   ID
-	Text     string
+	TeamID ID
+	UserID ID
+	TaskID ID
+	Text   string
+	File   *AnswerFile
+	// Part is index of answered part of multi-part task
+	Part     *int
 	Accepted bool
-	Score    int
+	// Partial is set for partially correct choice answer, which is scored, but does not solve the task
+	Partial      bool
+	Score        int
+	Penalty      int
+	Bonus        int
+	Decay        int
+	ReviewStatus ReviewStatus
+}
+
+// LockAnswerTriesRequest serializes answer tries till the end of transaction.
+// Tries of the team for the task are locked when TeamID is set, tries of all teams for the task otherwise.
+type LockAnswerTriesRequest struct {
+	TaskID ID
+	TeamID ID
+}
+
+type GetAnswerTryStatsRequest struct {
+	TeamID ID
+	TaskID ID
+}
+
+type GetTaskSolveCountRequest struct {
+	TaskID ID
+}
+
+type GetAnswerTryRequest struct {
+	ID      int64
+	QuestID ID
+}
+
+type GetReviewAnswerTriesRequest struct {
+	QuestID     ID
+	TeamID      ID
+	OnlyPending bool
+}
+
+type ReviewAnswerTryRequest struct {
+	ID           int64
+	ReviewStatus ReviewStatus
+	Score        int
+	Bonus        int
+	Decay        int
+	ReviewTime   time.Time
+}
+
+// RejectPendingAnswerTriesRequest rejects answers of team to the task, which are still waiting for review
+type RejectPendingAnswerTriesRequest struct {
+	TeamID     ID
+	TaskID     ID
+	ReviewTime time.Time
 }
 
 type GetResultsRequest struct {
 	QuestID ID
 	TeamIDs []ID
+	// Before limits results to answers scored not later than given time
+	Before *time.Time
 }
 
 type GetPenaltiesRequest struct {
 	QuestID ID
 	TeamIDs []ID
+	// Before limits penalties to ones given and not revoked by given time
+	Before *time.Time
+}
+
+type GetScoreHistoryRequest struct {
+	QuestID ID
+	// Bucket groups score changes into intervals of given length, zero means no grouping
+	Bucket time.Duration
+	// Before limits history to changes not later than given time
+	Before *time.Time
 }
 
 type CreatePenaltyRequest struct {
 	TeamID  ID
 	Penalty int
+	// TaskGroupID is set for penalty of skipped task group
+	TaskGroupID ID
+	TaskID      ID
+	Reason      string
+	// AuthorID is empty for penalties given automatically
+	AuthorID ID
+	// Legacy marks penalty set by legacy penalty endpoint
+	Legacy bool
+}
+
+type GetAdjustmentsRequest struct {
+	QuestID ID
+	TeamID  ID
+	// ActiveOnly excludes revoked adjustments
+	ActiveOnly bool
+}
+
+type RevokeAdjustmentRequest struct {
+	ID      int64
+	QuestID ID
+	UserID  ID
+}
+
+type CreateBonusCodeRequest struct {
+	QuestID    ID         `json:"-"`
+	Code       string     `json:"code"`
+	Value      int        `json:"value"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty" example:"2024-04-14T14:00:00+05:00"`
+	ValidUntil *time.Time `json:"valid_until,omitempty" example:"2024-04-14T16:00:00+05:00"`
+	MaxUses    *int       `json:"max_uses,omitempty"`
+}
+
+type GetBonusCodeRequest struct {
+	QuestID ID
+	// Code is matched case-insensitively
+	Code string
+}
+
+type GetBonusCodesRequest struct {
+	QuestID ID
+}
+
+type DeleteBonusCodeRequest struct {
+	QuestID ID
+	ID      ID
+}
+
+type UseBonusCodeRequest struct {
+	CodeID ID
+	TeamID ID
+	UserID ID
+}
+
+type GetBonusCodeUsesRequest struct {
+	QuestID ID
+	// Before limits uses to ones made not later than given time
+	Before *time.Time
 }
 
 type TaskRequestLogFilterOptions struct {
@@ -393,6 +604,9 @@ type UpsertTeamInfoRequest struct {
 	TaskGroupID ID
 	OpeningTime time.Time
 	ClosingTime *time.Time
+	NextGroupID *ID
+	// OnlyOpen keeps already closed group intact, in which case ErrNotFound is returned
+	OnlyOpen bool
 }
 
 type GetTeamInfoRequest struct {
@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/yandex/perforator/library/go/core/xerrors"
+)
+
+// DefaultHeartbeatInterval is small enough to keep idle connections alive through common proxies
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// EventStream writes Server-Sent Events to response.
+// Writes are flushed with http.ResponseController, so middlewares wrapping http.ResponseWriter
+// have to implement Unwrap to keep streaming working.
+type EventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// NewEventStream writes SSE response headers. Nothing but events must be written to response afterwards
+func NewEventStream(w http.ResponseWriter) (*EventStream, error) {
+	rc := http.NewResponseController(w)
+	// server write timeout would otherwise break long-living connection
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, xerrors.Errorf("reset write deadline: %w", err)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return nil, xerrors.Errorf("flush headers: %w", err)
+	}
+	return &EventStream{w: w, rc: rc}, nil
+}
+
+// Send writes event with JSON-encoded data
+func (s *EventStream) Send(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return xerrors.Errorf("marshal event data: %w", err)
+	}
+	if _, err = fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return xerrors.Errorf("write event: %w", err)
+	}
+	return s.flush()
+}
+
+// Heartbeat writes SSE comment which is ignored by clients but keeps connection open
+func (s *EventStream) Heartbeat() error {
+	if _, err := fmt.Fprint(s.w, ": heartbeat\n\n"); err != nil {
+		return xerrors.Errorf("write heartbeat: %w", err)
+	}
+	return s.flush()
+}
+
+func (s *EventStream) flush() error {
+	if err := s.rc.Flush(); err != nil {
+		return xerrors.Errorf("flush: %w", err)
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package transport
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"time"
 
 	"github.com/yandex/perforator/library/go/core/xerrors"
 
@@ -51,3 +54,27 @@ func ServeTextf(w http.ResponseWriter, status int, format string, a ...any) {
 func ServeErr(w http.ResponseWriter, status int, err error) {
 	ServeText(w, status, err.Error())
 }
+
+// ServeFile serves data as attachment, so that browsers download it as file with given name
+func ServeFile(w http.ResponseWriter, contentType, fileName string, data []byte) {
+	setAttachmentHeaders(w, contentType, fileName)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// StartFileStream writes headers of attachment, which body is written afterwards without known length.
+// Write deadline is reset, since streaming of large files may take longer than server write timeout.
+func StartFileStream(w http.ResponseWriter, contentType, fileName string) error {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return xerrors.Errorf("reset write deadline: %w", err)
+	}
+	setAttachmentHeaders(w, contentType, fileName)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func setAttachmentHeaders(w http.ResponseWriter, contentType, fileName string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+}